@@ -0,0 +1,286 @@
+// Package aab reads Android App Bundles (.aab).
+//
+// The manifests and the resource tables in bundles are stored in the protocol buffers format of aapt2,
+// and they are decoded into the same apk.Manifest and androidbinary.TableFile as APKs.
+package aab
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+	"github.com/shogo82148/androidbinary/apk"
+)
+
+const (
+	// BaseModuleName is the name of the base module.
+	BaseModuleName = "base"
+
+	manifestPath  = "manifest/AndroidManifest.xml"
+	resourcesPath = "resources.pb"
+)
+
+// ErrNoBaseModule is returned if the bundle has no base module.
+var ErrNoBaseModule = errors.New("aab: base module not found")
+
+// ModuleType is a type of modules.
+type ModuleType int
+
+const (
+	// ModuleTypeBase is the base module.
+	ModuleTypeBase ModuleType = iota
+
+	// ModuleTypeFeature is a feature module.
+	ModuleTypeFeature
+
+	// ModuleTypeAssetPack is an asset pack.
+	ModuleTypeAssetPack
+)
+
+func (t ModuleType) String() string {
+	switch t {
+	case ModuleTypeBase:
+		return "base"
+	case ModuleTypeFeature:
+		return "feature"
+	case ModuleTypeAssetPack:
+		return "asset-pack"
+	}
+	return fmt.Sprintf("ModuleType(%d)", int(t))
+}
+
+// Module is a module in an Android App Bundle.
+type Module struct {
+	// Name is the name of the module, which is the name of the directory in the bundle.
+	Name string
+
+	// Type is the type of the module.
+	Type ModuleType
+
+	manifest apk.Manifest
+	table    *androidbinary.TableFile
+}
+
+// Manifest returns the manifest of the module.
+func (m *Module) Manifest() apk.Manifest {
+	return m.manifest
+}
+
+// Table returns the resource table of the module.
+// It also contains the resources of the base module, because modules may refer to them.
+func (m *Module) Table() *androidbinary.TableFile {
+	return m.table
+}
+
+// distModule is the dist:module element of the manifest.
+type distModule struct {
+	Module *struct {
+		Type string `xml:"http://schemas.android.com/apk/distribution type,attr"`
+	} `xml:"http://schemas.android.com/apk/distribution module"`
+}
+
+// Bundle is an Android App Bundle.
+type Bundle struct {
+	f         *os.File
+	zipreader *zip.Reader
+	modules   []*Module
+}
+
+// OpenFile will open the file specified by filename and return Bundle.
+func OpenFile(filename string) (bundle *Bundle, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+		}
+	}()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	bundle, err = OpenZipReader(f, fi.Size())
+	if err != nil {
+		return nil, err
+	}
+	bundle.f = f
+	return
+}
+
+// OpenZipReader has same arguments like zip.NewReader.
+func OpenZipReader(r io.ReaderAt, size int64) (*Bundle, error) {
+	zipreader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &Bundle{
+		zipreader: zipreader,
+	}
+	if err := bundle.parseModules(); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// Close is avaliable only if bundle is created with OpenFile.
+func (b *Bundle) Close() error {
+	if b.f == nil {
+		return nil
+	}
+	return b.f.Close()
+}
+
+// Modules returns the modules in the bundle. The base module comes first.
+func (b *Bundle) Modules() []*Module {
+	return b.modules
+}
+
+// Module returns the module of the name, or nil if not found.
+func (b *Bundle) Module(name string) *Module {
+	for _, m := range b.modules {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// Manifest returns the manifest of the base module.
+func (b *Bundle) Manifest() apk.Manifest {
+	return b.modules[0].manifest
+}
+
+// Table returns the resource table of the base module.
+func (b *Bundle) Table() *androidbinary.TableFile {
+	return b.modules[0].table
+}
+
+// PackageName returns the package name of the bundle.
+func (b *Bundle) PackageName() string {
+	manifest := b.Manifest()
+	return manifest.Package.MustString()
+}
+
+// Label returns the label of the bundle.
+func (b *Bundle) Label(resConfig *androidbinary.ResTableConfig) (s string, err error) {
+	manifest := b.Manifest()
+	s, err = manifest.App.Label.WithResTableConfig(resConfig).String()
+	if err != nil {
+		return
+	}
+	if androidbinary.IsResID(s) {
+		err = errors.New("aab: unable to convert label-id to string")
+	}
+	return
+}
+
+func (b *Bundle) parseModules() error {
+	var names []string
+	for _, file := range b.zipreader.File {
+		i := strings.Index(file.Name, "/")
+		if i <= 0 || file.Name[i+1:] != manifestPath {
+			continue
+		}
+		names = append(names, file.Name[:i])
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// the base module comes first.
+		if (names[i] == BaseModuleName) != (names[j] == BaseModuleName) {
+			return names[i] == BaseModuleName
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 || names[0] != BaseModuleName {
+		return ErrNoBaseModule
+	}
+
+	var baseTable *androidbinary.TableFile
+	for _, name := range names {
+		table, err := b.readTable(name)
+		if err != nil {
+			return fmt.Errorf("aab: module %s: %w", name, err)
+		}
+		if name == BaseModuleName {
+			baseTable = table
+		} else {
+			// the resources of the module may refer to the resources of the base module.
+			table = androidbinary.MergeTableFiles(baseTable, table)
+		}
+		m, err := b.parseModule(name, table)
+		if err != nil {
+			return fmt.Errorf("aab: module %s: %w", name, err)
+		}
+		b.modules = append(b.modules, m)
+	}
+	return nil
+}
+
+// readTable reads the resource table of the module.
+func (b *Bundle) readTable(name string) (*androidbinary.TableFile, error) {
+	resources, err := b.readZipFile(name + "/" + resourcesPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	table, err := androidbinary.NewTableFileFromProto(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", resourcesPath, err)
+	}
+	return table, nil
+}
+
+func (b *Bundle) parseModule(name string, table *androidbinary.TableFile) (*Module, error) {
+	m := &Module{
+		Name:  name,
+		Type:  ModuleTypeFeature,
+		table: table,
+	}
+
+	data, err := b.readZipFile(name + "/" + manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	xmlfile, err := androidbinary.NewXMLFileFromProto(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AndroidManifest.xml: %w", err)
+	}
+	if err := xmlfile.Decode(&m.manifest, table, nil); err != nil {
+		return nil, fmt.Errorf("failed to parse AndroidManifest.xml: %w", err)
+	}
+
+	var dist distModule
+	if err := xmlfile.Decode(&dist, nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to parse AndroidManifest.xml: %w", err)
+	}
+	switch {
+	case name == BaseModuleName:
+		m.Type = ModuleTypeBase
+	case dist.Module != nil && dist.Module.Type == "asset-pack":
+		m.Type = ModuleTypeAssetPack
+	}
+	return m, nil
+}
+
+// readZipFile reads the file in the bundle.
+// It returns an error that satisfies os.IsNotExist if the file is not found.
+func (b *Bundle) readZipFile(name string) ([]byte, error) {
+	for _, file := range b.zipreader.File {
+		if file.Name != name {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+	return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+}
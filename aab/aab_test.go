@@ -0,0 +1,171 @@
+package aab
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+const (
+	testAndroidNS = "http://schemas.android.com/apk/res/android"
+	testDistNS    = "http://schemas.android.com/apk/distribution"
+)
+
+// testProto encodes a protocol buffers message for testing.
+type testProto []byte
+
+func (m testProto) uvarint(v uint64) testProto {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(m, buf[:n]...)
+}
+
+func (m testProto) varint(field int, v uint64) testProto {
+	return m.uvarint(uint64(field << 3)).uvarint(v)
+}
+
+func (m testProto) bytes(field int, v []byte) testProto {
+	return append(m.uvarint(uint64(field<<3|2)).uvarint(uint64(len(v))), v...)
+}
+
+func (m testProto) string(field int, v string) testProto {
+	return m.bytes(field, []byte(v))
+}
+
+// testResources returns a ResourceTable message that has a string resource 0x??010000.
+func testResources(packageID uint64, value string) testProto {
+	item := testProto(nil).bytes(2, testProto(nil).string(1, value))
+	entry := testProto(nil).
+		bytes(1, testProto(nil).varint(1, 0)).
+		string(2, "name").
+		bytes(6, testProto(nil).bytes(2, testProto(nil).bytes(4, item)))
+	typ := testProto(nil).
+		bytes(1, testProto(nil).varint(1, 1)).
+		string(2, "string").
+		bytes(3, entry)
+	pkg := testProto(nil).
+		bytes(1, testProto(nil).varint(1, packageID)).
+		bytes(3, typ)
+	return testProto(nil).bytes(2, pkg)
+}
+
+func testAttribute(uri, name, value string, ref uint64) testProto {
+	m := testProto(nil).string(1, uri).string(2, name).string(3, value)
+	if ref != 0 {
+		m = m.bytes(6, testProto(nil).bytes(1, testProto(nil).varint(2, ref)))
+	}
+	return m
+}
+
+// testManifest returns an XmlNode message of AndroidManifest.xml.
+func testManifest(split string, label uint64, moduleType string) testProto {
+	manifest := testProto(nil).
+		bytes(1, testProto(nil).string(1, "android").string(2, testAndroidNS)).
+		bytes(1, testProto(nil).string(1, "dist").string(2, testDistNS)).
+		string(3, "manifest").
+		bytes(4, testAttribute("", "package", "com.example", 0)).
+		bytes(4, testAttribute(testAndroidNS, "versionName", "1.0", 0))
+	if split != "" {
+		manifest = manifest.bytes(4, testAttribute("", "split", split, 0))
+	}
+	module := testProto(nil).string(2, testDistNS).string(3, "module")
+	if moduleType != "" {
+		module = module.bytes(4, testAttribute(testDistNS, "type", moduleType, 0))
+	}
+	application := testProto(nil).
+		string(3, "application").
+		bytes(4, testAttribute(testAndroidNS, "label", "@string/name", label))
+	manifest = manifest.
+		bytes(5, testProto(nil).bytes(1, module)).
+		bytes(5, testProto(nil).bytes(1, application))
+	return testProto(nil).bytes(1, manifest)
+}
+
+func openTestBundle(t *testing.T, files map[string][]byte) (*Bundle, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return OpenZipReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+func TestOpenZipReader(t *testing.T) {
+	bundle, err := openTestBundle(t, map[string][]byte{
+		"BundleConfig.pb":                         nil,
+		"base/manifest/AndroidManifest.xml":       testManifest("", 0x7f010000, ""),
+		"base/resources.pb":                       testResources(0x7f, "Example"),
+		"base/dex/classes.dex":                    nil,
+		"feature/manifest/AndroidManifest.xml":    testManifest("feature", 0x7e010000, ""),
+		"feature/resources.pb":                    testResources(0x7e, "Feature"),
+		"assetpack/manifest/AndroidManifest.xml":  testManifest("assetpack", 0x7f010000, "asset-pack"),
+		"assetpack/assets/data.bin":               nil,
+		"BUNDLE-METADATA/com.android.tools/r8.pb": nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := bundle.PackageName(); got != "com.example" {
+		t.Errorf("want com.example, got %s", got)
+	}
+	if label, err := bundle.Label(&androidbinary.ResTableConfig{}); err != nil || label != "Example" {
+		t.Errorf("want Example, got %q, %v", label, err)
+	}
+
+	modules := bundle.Modules()
+	want := []struct {
+		name  string
+		typ   ModuleType
+		label string
+	}{
+		{"base", ModuleTypeBase, "Example"},
+		{"assetpack", ModuleTypeAssetPack, "Example"},
+		{"feature", ModuleTypeFeature, "Feature"},
+	}
+	if len(modules) != len(want) {
+		t.Fatalf("want %d modules, got %d", len(want), len(modules))
+	}
+	for i, w := range want {
+		m := modules[i]
+		if m.Name != w.name || m.Type != w.typ {
+			t.Errorf("want %s(%v), got %s(%v)", w.name, w.typ, m.Name, m.Type)
+		}
+		manifest := m.Manifest()
+		label, err := manifest.App.Label.WithResTableConfig(&androidbinary.ResTableConfig{}).String()
+		if err != nil || label != w.label {
+			t.Errorf("%s: want %q, got %q, %v", m.Name, w.label, label, err)
+		}
+	}
+
+	// the feature module can refer to the resources of the base module.
+	if v, err := bundle.Module("feature").Table().GetResource(0x7f010000, nil); err != nil || v != "Example" {
+		t.Errorf("want Example, got %v, %v", v, err)
+	}
+	if bundle.Module("unknown") != nil {
+		t.Error("want nil")
+	}
+}
+
+func TestOpenZipReaderNoBaseModule(t *testing.T) {
+	_, err := openTestBundle(t, map[string][]byte{
+		"feature/manifest/AndroidManifest.xml": testManifest("feature", 0, ""),
+	})
+	if !errors.Is(err, ErrNoBaseModule) {
+		t.Errorf("want ErrNoBaseModule, got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package apk
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAlignment is the default alignment of uncompressed entries, same as zipalign.
+	DefaultAlignment = 4
+
+	// DefaultLibraryAlignment is the default alignment of uncompressed native libraries.
+	DefaultLibraryAlignment = PageSize16K
+
+	// localFileHeaderLen is the length of the local file header without the name and the extra field.
+	localFileHeaderLen = 30
+
+	// alignmentExtraID is the ID of the extra field that apksigner uses for alignment padding.
+	alignmentExtraID = 0xd935
+
+	// zip64ExtraID is the ID of the Zip64 extended information extra field.
+	zip64ExtraID = 0x0001
+)
+
+// AlignOptions is the alignments of uncompressed entries.
+type AlignOptions struct {
+	// Alignment is the alignment of uncompressed entries. The default is DefaultAlignment.
+	Alignment int64
+
+	// LibraryAlignment is the alignment of uncompressed native libraries, i.e. the page size.
+	// The default is DefaultLibraryAlignment.
+	LibraryAlignment int64
+}
+
+func (opts *AlignOptions) alignments() (alignment, libraryAlignment int64) {
+	alignment, libraryAlignment = DefaultAlignment, DefaultLibraryAlignment
+	if opts == nil {
+		return
+	}
+	if opts.Alignment != 0 {
+		alignment = opts.Alignment
+	}
+	if opts.LibraryAlignment != 0 {
+		libraryAlignment = opts.LibraryAlignment
+	}
+	return
+}
+
+// AlignmentIssue is an uncompressed entry that is not aligned.
+type AlignmentIssue struct {
+	// Name is the name of the entry.
+	Name string
+
+	// Offset is the offset of the entry data.
+	Offset int64
+
+	// Alignment is the required alignment.
+	Alignment int64
+}
+
+func (i AlignmentIssue) String() string {
+	return fmt.Sprintf("%s: offset %d is not aligned on %d bytes", i.Name, i.Offset, i.Alignment)
+}
+
+// CheckAlignment checks whether the uncompressed entries are aligned, like `zipalign -c`.
+// The native libraries are checked against the library alignment, and other entries against the alignment.
+func (k *Apk) CheckAlignment(opts *AlignOptions) ([]AlignmentIssue, error) {
+	alignment, libraryAlignment := opts.alignments()
+	var issues []AlignmentIssue
+	for _, file := range k.zipreader.File {
+		if file.Method != zip.Store || strings.HasSuffix(file.Name, "/") {
+			continue
+		}
+		offset, err := file.DataOffset()
+		if err != nil {
+			return nil, err
+		}
+		align := entryAlignment(file.Name, alignment, libraryAlignment)
+		if offset%align != 0 {
+			issues = append(issues, AlignmentIssue{
+				Name:      file.Name,
+				Offset:    offset,
+				Alignment: align,
+			})
+		}
+	}
+	return issues, nil
+}
+
+// Align writes the APK to w with the uncompressed entries aligned, like `zipalign`.
+// The entries are padded with an extra field, and copied without recompression.
+// The APK Signing Block is not copied, so the APK must be signed after aligning
+// if it was signed with the APK Signature Scheme v2 or later.
+func (k *Apk) Align(w io.Writer, opts *AlignOptions) error {
+	alignment, libraryAlignment := opts.alignments()
+	zw := newAlignedZipWriter(w, alignment, libraryAlignment)
+	for _, file := range k.zipreader.File {
+		if err := zw.copyFile(file); err != nil {
+			return err
+		}
+	}
+	return zw.close()
+}
+
+// countWriter counts the bytes written.
+type countWriter struct {
+	w     io.Writer
+	count int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.count += int64(n)
+	return n, err
+}
+
+// alignedZipWriter writes a zip file whose uncompressed entries are aligned.
+type alignedZipWriter struct {
+	cw               *countWriter
+	zw               *zip.Writer
+	alignment        int64
+	libraryAlignment int64
+}
+
+func newAlignedZipWriter(w io.Writer, alignment, libraryAlignment int64) *alignedZipWriter {
+	cw := &countWriter{w: w}
+	return &alignedZipWriter{
+		cw:               cw,
+		zw:               zip.NewWriter(cw),
+		alignment:        alignment,
+		libraryAlignment: libraryAlignment,
+	}
+}
+
+// entryAlignment returns the alignment of the entry.
+func entryAlignment(name string, alignment, libraryAlignment int64) int64 {
+	if strings.HasSuffix(name, ".so") {
+		return libraryAlignment
+	}
+	return alignment
+}
+
+// copyFile copies the file without recompression.
+func (w *alignedZipWriter) copyFile(file *zip.File) error {
+	fh := file.FileHeader
+	// the sizes and the CRC are known, so the data descriptor is not necessary.
+	fh.Flags &^= 0x8
+	// zip.Writer adds an extended timestamp field if Modified is set. keep the original MS-DOS time.
+	fh.Modified = time.Time{}
+	fh.Extra = stripExtra(fh.Extra, alignmentExtraID, zip64ExtraID)
+
+	extra := fh.Extra
+	if fh.Method == zip.Store && !strings.HasSuffix(fh.Name, "/") {
+		// flush the buffer of zip.Writer to know the offset of the local file header.
+		if err := w.zw.Flush(); err != nil {
+			return err
+		}
+		offset := w.cw.count + localFileHeaderLen + int64(len(fh.Name)) + int64(len(extra))
+		fh.Extra = append(extra[:len(extra):len(extra)], alignmentExtra(offset, entryAlignment(fh.Name, w.alignment, w.libraryAlignment))...)
+	}
+
+	fw, err := w.zw.CreateRaw(&fh)
+	if err != nil {
+		return err
+	}
+	// zip.Writer keeps fh to write the central directory on Close.
+	// the padding is needed only in the local file header, same as zipalign and apksigner.
+	fh.Extra = extra
+	rc, err := file.OpenRaw()
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, rc)
+	return err
+}
+
+// create adds a compressed file.
+func (w *alignedZipWriter) create(name string, data []byte) error {
+	fw, err := w.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Date(1981, 1, 1, 1, 1, 2, 0, time.UTC),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(data)
+	return err
+}
+
+func (w *alignedZipWriter) close() error {
+	return w.zw.Close()
+}
+
+// alignmentExtra returns the extra field that pads the data at offset to the alignment.
+// offset is the offset of the data without the extra field.
+func alignmentExtra(offset, alignment int64) []byte {
+	if alignment <= 1 {
+		return nil
+	}
+	// the extra field has the ID, the size and the alignment (2 bytes each), followed by the padding.
+	const headerSize = 6
+	padding := (alignment - (offset+headerSize)%alignment) % alignment
+	extra := make([]byte, headerSize+padding)
+	binary.LittleEndian.PutUint16(extra[0:], alignmentExtraID)
+	binary.LittleEndian.PutUint16(extra[2:], uint16(2+padding))
+	binary.LittleEndian.PutUint16(extra[4:], uint16(alignment))
+	return extra
+}
+
+// stripExtra removes the extra fields of ids.
+func stripExtra(extra []byte, ids ...uint16) []byte {
+	var result []byte
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		if 4+size > len(extra) {
+			// broken extra field, keep it as it is.
+			result = append(result, extra...)
+			break
+		}
+		field := extra[:4+size]
+		extra = extra[4+size:]
+		strip := false
+		for _, v := range ids {
+			if id == v {
+				strip = true
+			}
+		}
+		if !strip {
+			result = append(result, field...)
+		}
+	}
+	return result
+}
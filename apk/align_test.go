@@ -0,0 +1,108 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/elf"
+	"io/ioutil"
+	"testing"
+)
+
+func TestAlign(t *testing.T) {
+	var in bytes.Buffer
+	w := zip.NewWriter(&in)
+	files := []struct {
+		name   string
+		method uint16
+		data   []byte
+	}{
+		{"a.txt", zip.Store, []byte("a")},
+		{"b.txt", zip.Store, []byte("bb")},
+		{"c.txt", zip.Deflate, bytes.Repeat([]byte("c"), 100)},
+		{"lib/arm64-v8a/libfoo.so", zip.Store, newTestELF64(t, elf.EM_AARCH64, PageSize16K)},
+	}
+	for _, f := range files {
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: f.name, Method: f.method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(in.Bytes()), int64(in.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apk := &Apk{zipreader: r}
+
+	issues, err := apk.CheckAlignment(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("want alignment issues")
+	}
+
+	var out bytes.Buffer
+	if err := apk.Align(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	r, err = zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aligned := &Apk{zipreader: r}
+	issues, err = aligned.CheckAlignment(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("want no alignment issues, got %v", issues)
+	}
+
+	// the contents are not changed
+	for i, f := range files {
+		file := r.File[i]
+		if file.Name != f.name || file.Method != f.method {
+			t.Errorf("unexpected entry: %s", file.Name)
+		}
+		// zip.Reader reads the extra fields from the central directory, which has no padding.
+		if len(stripExtra(file.Extra, alignmentExtraID)) != len(file.Extra) {
+			t.Errorf("%s: the central directory has the alignment padding", f.name)
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, f.data) {
+			t.Errorf("%s: contents changed", f.name)
+		}
+	}
+
+	// 4 KB page alignment is sufficient with LibraryAlignment: PageSize4K
+	issues, err = aligned.CheckAlignment(&AlignOptions{LibraryAlignment: PageSize4K})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("want no alignment issues, got %v", issues)
+	}
+}
+
+func TestAlignmentExtra(t *testing.T) {
+	for offset := int64(0); offset < 64; offset++ {
+		extra := alignmentExtra(offset, 16)
+		if (offset+int64(len(extra)))%16 != 0 {
+			t.Errorf("offset %d: padded to %d", offset, offset+int64(len(extra)))
+		}
+	}
+}
@@ -3,10 +3,10 @@ package apk
 import (
 	"archive/zip"
 	"bytes"
-	"fmt"
 	"image"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/shogo82148/androidbinary"
 
@@ -14,16 +14,58 @@ import (
 	_ "image/png"  // handle png format
 )
 
+// OpenOptions is options for opening APKs.
+type OpenOptions struct {
+	// Lazy defers parsing AndroidManifest.xml and resources.arsc until they are needed,
+	// so the APK can be inspected without them, e.g. for verifying signatures.
+	// Use Parse to parse them and get the error.
+	Lazy bool
+
+	// AllowMissingResources allows APKs without resources.arsc.
+	// The resource references in the manifest are not resolved.
+	AllowMissingResources bool
+
+	// LazyResources reads the strings and the entries of resources.arsc on demand,
+	// instead of decoding all of them when it is parsed. See androidbinary.NewTableFileLazy.
+	// resources.arsc is read directly from the APK if it is stored without compression,
+	// so the APK must not be closed while the resources are used.
+	LazyResources bool
+
+	// ParseOptions is the options for parsing AndroidManifest.xml and resources.arsc,
+	// such as the context and the limits of the parser.
+	ParseOptions *androidbinary.ParseOptions
+
+	// MaxFileSize is the maximum uncompressed size of a file that is read into memory,
+	// such as AndroidManifest.xml and resources.arsc. Zero means no limit.
+	MaxFileSize int64
+}
+
 // Apk is an application package file for android.
 type Apk struct {
 	f         *os.File
+	r         io.ReaderAt
+	size      int64
 	zipreader *zip.Reader
+	opts      OpenOptions
 	manifest  Manifest
 	table     *androidbinary.TableFile
+
+	// manifestWarnings is the warnings of AndroidManifest.xml in the lenient mode.
+	manifestWarnings []*androidbinary.ParseError
+
+	// lazy parsing
+	lazy      bool
+	parseOnce sync.Once
+	parseErr  error
 }
 
 // OpenFile will open the file specified by filename and return Apk
 func OpenFile(filename string) (apk *Apk, err error) {
+	return OpenFileWithOptions(filename, nil)
+}
+
+// OpenFileWithOptions is same as OpenFile, but it accepts options.
+func OpenFileWithOptions(filename string, opts *OpenOptions) (apk *Apk, err error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -37,7 +79,7 @@ func OpenFile(filename string) (apk *Apk, err error) {
 	if err != nil {
 		return nil, err
 	}
-	apk, err = OpenZipReader(f, fi.Size())
+	apk, err = OpenZipReaderWithOptions(f, fi.Size(), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -47,22 +89,58 @@ func OpenFile(filename string) (apk *Apk, err error) {
 
 // OpenZipReader has same arguments like zip.NewReader
 func OpenZipReader(r io.ReaderAt, size int64) (*Apk, error) {
+	return OpenZipReaderWithOptions(r, size, nil)
+}
+
+// OpenZipReaderWithOptions is same as OpenZipReader, but it accepts options.
+func OpenZipReaderWithOptions(r io.ReaderAt, size int64, opts *OpenOptions) (*Apk, error) {
 	zipreader, err := zip.NewReader(r, size)
 	if err != nil {
 		return nil, err
 	}
 	apk := &Apk{
+		r:         r,
+		size:      size,
 		zipreader: zipreader,
 	}
-	if err = apk.parseResources(); err != nil {
-		return nil, err
+	if opts != nil {
+		apk.opts = *opts
 	}
-	if err = apk.parseManifest(); err != nil {
-		return nil, errorf("parse-manifest: %w", err)
+	if apk.opts.Lazy {
+		apk.lazy = true
+		return apk, nil
+	}
+	if err := apk.parseAll(); err != nil {
+		return nil, err
 	}
 	return apk, nil
 }
 
+// Parse parses AndroidManifest.xml and resources.arsc, if the APK is opened with the Lazy option.
+// The methods that need them call Parse implicitly.
+// Manifest and PackageName return the zero values if it fails.
+func (k *Apk) Parse() error {
+	if !k.lazy {
+		return nil
+	}
+	k.parseOnce.Do(func() {
+		k.parseErr = k.parseAll()
+	})
+	return k.parseErr
+}
+
+func (k *Apk) parseAll() error {
+	if !k.opts.AllowMissingResources || k.hasZipFile("resources.arsc") {
+		if err := k.parseResources(); err != nil {
+			return err
+		}
+	}
+	if err := k.parseManifest(); err != nil {
+		return errorf("parse-manifest: %w", err)
+	}
+	return nil
+}
+
 // Close is avaliable only if apk is created with OpenFile
 func (k *Apk) Close() error {
 	if k.f == nil {
@@ -73,12 +151,15 @@ func (k *Apk) Close() error {
 
 // Icon returns the icon image of the APK.
 func (k *Apk) Icon(resConfig *androidbinary.ResTableConfig) (image.Image, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
 	iconPath, err := k.manifest.App.Icon.WithResTableConfig(resConfig).String()
 	if err != nil {
 		return nil, err
 	}
 	if androidbinary.IsResID(iconPath) {
-		return nil, newError("unable to convert icon-id to icon path")
+		return nil, ErrIconNotResolved
 	}
 	imgData, err := k.readZipFile(iconPath)
 	if err != nil {
@@ -90,51 +171,87 @@ func (k *Apk) Icon(resConfig *androidbinary.ResTableConfig) (image.Image, error)
 
 // Label returns the label of the APK.
 func (k *Apk) Label(resConfig *androidbinary.ResTableConfig) (s string, err error) {
+	if err = k.Parse(); err != nil {
+		return
+	}
 	s, err = k.manifest.App.Label.WithResTableConfig(resConfig).String()
 	if err != nil {
 		return
 	}
 	if androidbinary.IsResID(s) {
-		err = newError("unable to convert label-id to string")
+		err = ErrLabelNotResolved
 	}
 	return
 }
 
 // Manifest returns the manifest of the APK.
 func (k *Apk) Manifest() Manifest {
+	k.Parse()
 	return k.manifest
 }
 
-// PackageName returns the package name of the APK.
-func (k *Apk) PackageName() string {
-	return k.manifest.Package.MustString()
+// Table returns the resource table of the APK.
+// It returns nil if the APK has no resources.arsc or it fails to be parsed.
+func (k *Apk) Table() *androidbinary.TableFile {
+	k.Parse()
+	return k.table
 }
 
-func isMainIntentFilter(intent ActivityIntentFilter) bool {
-	ok := false
-	for _, action := range intent.Actions {
-		s, err := action.Name.String()
-		if err == nil && s == "android.intent.action.MAIN" {
-			ok = true
-			break
+// Theme returns the theme of the activity, which is the theme of the application if the activity doesn't set its own.
+// activity is the name of the activity in the manifest, or the empty string for the theme of the application.
+func (k *Apk) Theme(activity string, resConfig *androidbinary.ResTableConfig) (*androidbinary.Theme, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	id, ok := k.manifest.App.Theme.ResID()
+	if activity != "" {
+		act, err := k.findActivity(activity)
+		if err != nil {
+			return nil, err
+		}
+		if actID, actOK := act.Theme.ResID(); actOK {
+			id, ok = actID, actOK
 		}
 	}
 	if !ok {
-		return false
-	}
-	ok = false
-	for _, category := range intent.Categories {
-		s, err := category.Name.String()
-		if err == nil && s == "android.intent.category.LAUNCHER" {
-			ok = true
-			break
+		return nil, ErrNoTheme
+	}
+	theme := k.table.NewTheme(resConfig)
+	if err := theme.ApplyStyle(id, true); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+func (k *Apk) findActivity(name string) (*AppActivity, error) {
+	for i, act := range k.manifest.App.Activities {
+		if n, err := act.Name.String(); err == nil && n == name {
+			return &k.manifest.App.Activities[i], nil
 		}
 	}
-	return ok
+	return nil, errorf("%w: %q", ErrActivityNotFound, name)
+}
+
+// PackageName returns the package name of the APK.
+func (k *Apk) PackageName() string {
+	k.Parse()
+	return k.manifest.Package.MustString()
+}
+
+var mainIntent = &Intent{
+	Action:     "android.intent.action.MAIN",
+	Categories: []string{"android.intent.category.LAUNCHER"},
+}
+
+func isMainIntentFilter(intent ActivityIntentFilter) bool {
+	return intent.Match(mainIntent)
 }
 
 // MainActivity returns the name of the main activity.
 func (k *Apk) MainActivity() (activity string, err error) {
+	if err = k.Parse(); err != nil {
+		return
+	}
 	for _, act := range k.manifest.App.Activities {
 		for _, intent := range act.IntentFilters {
 			if isMainIntentFilter(intent) {
@@ -150,30 +267,90 @@ func (k *Apk) MainActivity() (activity string, err error) {
 		}
 	}
 
-	return "", newError("No main activity found")
+	return "", ErrNoMainActivity
 }
 
 func (k *Apk) parseManifest() error {
+	return k.decodeManifest(&k.manifest, k.table)
+}
+
+// decodeManifest decodes AndroidManifest.xml into v, resolving the references with table.
+func (k *Apk) decodeManifest(v interface{}, table *androidbinary.TableFile) error {
 	xmlData, err := k.readZipFile("AndroidManifest.xml")
 	if err != nil {
 		return errorf("failed to read AndroidManifest.xml: %w", err)
 	}
-	xmlfile, err := androidbinary.NewXMLFile(bytes.NewReader(xmlData))
+	xmlfile, err := androidbinary.NewXMLFileWithOptions(bytes.NewReader(xmlData), k.opts.ParseOptions)
 	if err != nil {
 		return errorf("failed to parse AndroidManifest.xml: %w", err)
 	}
-	return xmlfile.Decode(&k.manifest, k.table, nil)
+	k.manifestWarnings = xmlfile.Warnings()
+	return xmlfile.Decode(v, table, nil)
+}
+
+// Warnings returns the problems of resources.arsc and AndroidManifest.xml
+// that are tolerated with the Lenient option of ParseOptions.
+func (k *Apk) Warnings() []*androidbinary.ParseError {
+	k.Parse()
+	var warnings []*androidbinary.ParseError
+	if k.table != nil {
+		warnings = append(warnings, k.table.Warnings()...)
+	}
+	return append(warnings, k.manifestWarnings...)
 }
 
 func (k *Apk) parseResources() (err error) {
+	var opts androidbinary.ParseOptions
+	if k.opts.ParseOptions != nil {
+		opts = *k.opts.ParseOptions
+	}
+	if k.opts.LazyResources {
+		opts.Lazy = true
+		r, err := k.openZipFileReaderAt("resources.arsc")
+		if err != nil {
+			return err
+		}
+		k.table, err = androidbinary.NewTableFileWithOptions(r, &opts)
+		return err
+	}
+
 	resData, err := k.readZipFile("resources.arsc")
 	if err != nil {
 		return
 	}
-	k.table, err = androidbinary.NewTableFile(bytes.NewReader(resData))
+	k.table, err = androidbinary.NewTableFileWithOptions(bytes.NewReader(resData), &opts)
 	return
 }
 
+// openZipFileReaderAt returns the reader of the file in the APK.
+// The file is read directly from the APK if it is not compressed, otherwise it is read into memory.
+func (k *Apk) openZipFileReaderAt(name string) (io.ReaderAt, error) {
+	for _, file := range k.zipreader.File {
+		if file.Name != name || file.Method != zip.Store {
+			continue
+		}
+		offset, err := file.DataOffset()
+		if err != nil {
+			return nil, err
+		}
+		return io.NewSectionReader(k.r, offset, int64(file.UncompressedSize64)), nil
+	}
+	data, err := k.readZipFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+func (k *Apk) hasZipFile(name string) bool {
+	for _, file := range k.zipreader.File {
+		if file.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (k *Apk) readZipFile(name string) (data []byte, err error) {
 	buf := bytes.NewBuffer(nil)
 	for _, file := range k.zipreader.File {
@@ -181,15 +358,29 @@ func (k *Apk) readZipFile(name string) (data []byte, err error) {
 			continue
 		}
 
+		limit := k.opts.MaxFileSize
+		if limit > 0 && file.UncompressedSize64 > uint64(limit) {
+			return nil, errorf("%w: %q is %d bytes", ErrFileTooLarge, name, file.UncompressedSize64)
+		}
+
 		localFun := func() error {
 			rc, e := file.Open()
 			if e != nil {
 				return e
 			}
 			defer rc.Close()
-			if _, e = io.Copy(buf, rc); e != nil {
+			var r io.Reader = rc
+			if limit > 0 {
+				// the uncompressed size in the header may be wrong.
+				r = io.LimitReader(rc, limit+1)
+			}
+			n, e := io.Copy(buf, r)
+			if e != nil {
 				return e
 			}
+			if limit > 0 && n > limit {
+				return errorf("%w: %q exceeds %d bytes", ErrFileTooLarge, name, limit)
+			}
 			return nil
 		}
 
@@ -199,5 +390,5 @@ func (k *Apk) readZipFile(name string) (data []byte, err error) {
 
 		return buf.Bytes(), nil
 	}
-	return nil, fmt.Errorf("apk: file %q not found", name)
+	return nil, errorf("%w: %s", ErrFileNotFound, name)
 }
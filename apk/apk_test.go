@@ -1,9 +1,17 @@
 package apk
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
 	_ "image/jpeg"
 	_ "image/png"
+	"io/ioutil"
+	"sync"
 	"testing"
+
+	"github.com/shogo82148/androidbinary"
 )
 
 func TestParseAPKFile(t *testing.T) {
@@ -46,4 +54,239 @@ func TestParseAPKFile(t *testing.T) {
 	if mainActivity != "com.example.helloworld.MainActivity" {
 		t.Errorf("MainActivity is not com.example.helloworld.MainActivity: %s", mainActivity)
 	}
+
+	if apk.Table() == nil {
+		t.Error("Table is nil")
+	}
+}
+
+func TestOpenZipReaderWithOptions(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := func(data []byte, opts *OpenOptions) (*Apk, error) {
+		return OpenZipReaderWithOptions(bytes.NewReader(data), int64(len(data)), opts)
+	}
+
+	t.Run("lazy", func(t *testing.T) {
+		apk, err := open(data, &OpenOptions{Lazy: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if apk.table != nil {
+			t.Error("want resources.arsc not parsed")
+		}
+
+		// Parse is safe for concurrent use.
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if name := apk.PackageName(); name != "com.example.helloworld" {
+					t.Errorf("unexpected package name: %s", name)
+				}
+			}()
+		}
+		wg.Wait()
+		if label, err := apk.Label(nil); err != nil || label != "HelloWorld" {
+			t.Errorf("want HelloWorld, got %q, %v", label, err)
+		}
+	})
+
+	t.Run("lazy with broken manifest", func(t *testing.T) {
+		broken := newTestZip(t, map[string][]byte{
+			"AndroidManifest.xml": []byte("broken"),
+			"resources.arsc":      nil,
+		}, zip.Deflate)
+		if _, err := open(broken, nil); err == nil {
+			t.Fatal("want error, got nil")
+		}
+		apk, err := open(broken, &OpenOptions{Lazy: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := apk.Parse(); err == nil {
+			t.Error("want error, got nil")
+		}
+		if _, err := apk.Label(nil); err == nil {
+			t.Error("want error, got nil")
+		}
+		if name := apk.PackageName(); name != "" {
+			t.Errorf("want empty, got %s", name)
+		}
+	})
+
+	t.Run("missing resources", func(t *testing.T) {
+		noResources := newTestZip(t, map[string][]byte{
+			"AndroidManifest.xml": encodeTestXML(t, `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.noresources">
+	<application android:label="@0x7F010000"></application>
+</manifest>`),
+		}, zip.Deflate)
+		if _, err := open(noResources, nil); err == nil {
+			t.Fatal("want error, got nil")
+		}
+		apk, err := open(noResources, &OpenOptions{AllowMissingResources: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := apk.PackageName(); name != "com.example.noresources" {
+			t.Errorf("unexpected package name: %s", name)
+		}
+		if _, err := apk.Label(nil); err == nil {
+			t.Error("want error for the unresolved label, got nil")
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		manifest := encodeTestXML(t, `<manifest package="com.example.truncated"><application></application></manifest>`)
+		truncated := newTestZip(t, map[string][]byte{
+			"AndroidManifest.xml": manifest[:len(manifest)-10],
+		}, zip.Deflate)
+		if _, err := open(truncated, &OpenOptions{AllowMissingResources: true}); err == nil {
+			t.Fatal("want error, got nil")
+		}
+		apk, err := open(truncated, &OpenOptions{
+			AllowMissingResources: true,
+			ParseOptions:          &androidbinary.ParseOptions{Lenient: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := apk.PackageName(); name != "com.example.truncated" {
+			t.Errorf("unexpected package name: %s", name)
+		}
+		if len(apk.Warnings()) == 0 {
+			t.Error("want warnings, got none")
+		}
+	})
+
+	t.Run("lazy resources", func(t *testing.T) {
+		r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := make(map[string][]byte)
+		for _, file := range r.File {
+			rc, err := file.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[file.Name], err = ioutil.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// resources.arsc is read from the APK directly if it is stored, otherwise it is read into memory.
+		for _, apkData := range [][]byte{data, newTestZip(t, files, zip.Store)} {
+			apk, err := open(apkData, &OpenOptions{LazyResources: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if label, err := apk.Label(nil); err != nil || label != "HelloWorld" {
+				t.Errorf("want HelloWorld, got %q, %v", label, err)
+			}
+			if _, err := apk.Icon(nil); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	t.Run("parse options", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := open(data, &OpenOptions{ParseOptions: &androidbinary.ParseOptions{Context: ctx}}); !errors.Is(err, context.Canceled) {
+			t.Errorf("want context.Canceled, got %v", err)
+		}
+		var limitErr *androidbinary.LimitError
+		if _, err := open(data, &OpenOptions{ParseOptions: &androidbinary.ParseOptions{MaxChunks: 10}}); !errors.As(err, &limitErr) {
+			t.Errorf("want LimitError, got %v", err)
+		}
+	})
+
+	t.Run("max file size", func(t *testing.T) {
+		if _, err := open(data, &OpenOptions{MaxFileSize: 4096}); !errors.Is(err, ErrFileTooLarge) {
+			t.Errorf("want ErrFileTooLarge, got %v", err)
+		}
+		apk, err := open(data, &OpenOptions{Lazy: true, MaxFileSize: 4096})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := apk.Parse(); !errors.Is(err, ErrFileTooLarge) {
+			t.Errorf("want ErrFileTooLarge, got %v", err)
+		}
+		if _, err := open(data, &OpenOptions{MaxFileSize: 1 << 20}); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestApkErrors(t *testing.T) {
+	apk, err := OpenFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer apk.Close()
+
+	if _, err := apk.readZipFile("not-found"); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("want ErrFileNotFound, got %v", err)
+	}
+	var zero Apk
+	if _, err := zero.VerifySignatures(); !errors.Is(err, ErrNoReader) {
+		t.Errorf("want ErrNoReader, got %v", err)
+	}
+
+	noActivity := newTestZip(t, map[string][]byte{
+		"AndroidManifest.xml": encodeTestXML(t, `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.noactivity"></manifest>`),
+	}, zip.Deflate)
+	apk, err = OpenZipReaderWithOptions(bytes.NewReader(noActivity), int64(len(noActivity)), &OpenOptions{AllowMissingResources: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := apk.MainActivity(); !errors.Is(err, ErrNoMainActivity) {
+		t.Errorf("want ErrNoMainActivity, got %v", err)
+	}
+	if _, err := apk.Theme("", nil); !errors.Is(err, ErrNoTheme) {
+		t.Errorf("want ErrNoTheme, got %v", err)
+	}
+}
+
+func TestApkTheme(t *testing.T) {
+	apk, err := OpenFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer apk.Close()
+
+	for _, activity := range []string{"", "com.example.helloworld.MainActivity"} {
+		theme, err := apk.Theme(activity, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// ?attr/colorPrimary of AppTheme is @color/colorPrimary.
+		v, err := theme.ResolveAttribute(androidbinary.ResID(0x7F0100A8))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.DataType != androidbinary.TypeIntColorRGB8 || v.Data != 0xFF3F51B5 {
+			t.Errorf("unexpected colorPrimary: %+v", v)
+		}
+
+		// ?attr/actionBarPopupTheme is inherited from Base.Theme.AppCompat.Light.DarkActionBar.
+		v, err = theme.ResolveAttribute(androidbinary.ResID(0x7F010066))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.DataType != androidbinary.TypeReference || v.Data != 0x7F080103 {
+			t.Errorf("unexpected actionBarPopupTheme: %+v", v)
+		}
+	}
+
+	if _, err := apk.Theme("not.found.Activity", nil); !errors.Is(err, ErrActivityNotFound) {
+		t.Errorf("want ErrActivityNotFound, got %v", err)
+	}
 }
@@ -22,10 +22,28 @@ type ActivityCategory struct {
 	Name androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
 }
 
+// ActivityData is a data specification of an intent filter.
+type ActivityData struct {
+	Scheme              androidbinary.String `xml:"http://schemas.android.com/apk/res/android scheme,attr"`
+	Host                androidbinary.String `xml:"http://schemas.android.com/apk/res/android host,attr"`
+	Port                androidbinary.String `xml:"http://schemas.android.com/apk/res/android port,attr"`
+	Path                androidbinary.String `xml:"http://schemas.android.com/apk/res/android path,attr"`
+	PathPrefix          androidbinary.String `xml:"http://schemas.android.com/apk/res/android pathPrefix,attr"`
+	PathPattern         androidbinary.String `xml:"http://schemas.android.com/apk/res/android pathPattern,attr"`
+	PathAdvancedPattern androidbinary.String `xml:"http://schemas.android.com/apk/res/android pathAdvancedPattern,attr"`
+	PathSuffix          androidbinary.String `xml:"http://schemas.android.com/apk/res/android pathSuffix,attr"`
+	SSP                 androidbinary.String `xml:"http://schemas.android.com/apk/res/android ssp,attr"`
+	SSPPrefix           androidbinary.String `xml:"http://schemas.android.com/apk/res/android sspPrefix,attr"`
+	SSPPattern          androidbinary.String `xml:"http://schemas.android.com/apk/res/android sspPattern,attr"`
+	MimeType            androidbinary.String `xml:"http://schemas.android.com/apk/res/android mimeType,attr"`
+}
+
 // ActivityIntentFilter is an androidbinary.Int32ent filter of an activity.
 type ActivityIntentFilter struct {
-	Actions    []ActivityAction   `xml:"action"`
-	Categories []ActivityCategory `xml:"category"`
+	AutoVerify *androidbinary.Bool `xml:"http://schemas.android.com/apk/res/android autoVerify,attr,omitempty"`
+	Actions    []ActivityAction    `xml:"action"`
+	Categories []ActivityCategory  `xml:"category"`
+	Data       []ActivityData      `xml:"data"`
 }
 
 // AppActivity is an activity in an application.
@@ -35,6 +53,7 @@ type AppActivity struct {
 	Label             androidbinary.String   `xml:"http://schemas.android.com/apk/res/android label,attr"`
 	ScreenOrientation androidbinary.String   `xml:"http://schemas.android.com/apk/res/android screenOrientation,attr"`
 	IntentFilters     []ActivityIntentFilter `xml:"intent-filter"`
+	MetaData          []MetaData             `xml:"meta-data"`
 }
 
 // AppActivityAlias https://developer.android.com/guide/topics/manifest/activity-alias-element
@@ -43,46 +62,82 @@ type AppActivityAlias struct {
 	Label          androidbinary.String   `xml:"http://schemas.android.com/apk/res/android label,attr"`
 	TargetActivity androidbinary.String   `xml:"http://schemas.android.com/apk/res/android targetActivity,attr"`
 	IntentFilters  []ActivityIntentFilter `xml:"intent-filter"`
+	MetaData       []MetaData             `xml:"meta-data"`
+}
+
+// AppService is a service in an application.
+type AppService struct {
+	Name          androidbinary.String   `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	Label         androidbinary.String   `xml:"http://schemas.android.com/apk/res/android label,attr"`
+	Permission    androidbinary.String   `xml:"http://schemas.android.com/apk/res/android permission,attr"`
+	IntentFilters []ActivityIntentFilter `xml:"intent-filter"`
+	MetaData      []MetaData             `xml:"meta-data"`
+}
+
+// AppReceiver is a broadcast receiver in an application.
+type AppReceiver struct {
+	Name          androidbinary.String   `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	Label         androidbinary.String   `xml:"http://schemas.android.com/apk/res/android label,attr"`
+	Permission    androidbinary.String   `xml:"http://schemas.android.com/apk/res/android permission,attr"`
+	IntentFilters []ActivityIntentFilter `xml:"intent-filter"`
+	MetaData      []MetaData             `xml:"meta-data"`
 }
 
-// MetaData is a metadata in an application.
+// MetaData is a metadata in an application or a component.
 type MetaData struct {
 	Name  androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
 	Value androidbinary.String `xml:"http://schemas.android.com/apk/res/android value,attr"`
+
+	// Resource is the reference to a resource, such as an XML resource. See Apk.DecodeMetaData.
+	Resource *androidbinary.String `xml:"http://schemas.android.com/apk/res/android resource,attr,omitempty"`
+}
+
+// UsesLibrary is a shared library that an application must be linked against.
+type UsesLibrary struct {
+	Name     androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	Required *androidbinary.Bool  `xml:"http://schemas.android.com/apk/res/android required,attr,omitempty"`
 }
 
 // Application is an application in an APK.
 type Application struct {
-	AllowTaskReparenting  androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android allowTaskReparenting,attr"`
-	AllowBackup           androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android allowBackup,attr"`
-	BackupAgent           androidbinary.String `xml:"http://schemas.android.com/apk/res/android backupAgent,attr"`
-	Debuggable            androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android debuggable,attr"`
-	Description           androidbinary.String `xml:"http://schemas.android.com/apk/res/android description,attr"`
-	Enabled               androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android enabled,attr"`
-	HasCode               androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android hasCode,attr"`
-	HardwareAccelerated   androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android hardwareAccelerated,attr"`
-	Icon                  androidbinary.String `xml:"http://schemas.android.com/apk/res/android icon,attr"`
-	KillAfterRestore      androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android killAfterRestore,attr"`
-	LargeHeap             androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android largeHeap,attr"`
-	Label                 androidbinary.String `xml:"http://schemas.android.com/apk/res/android label,attr"`
-	Logo                  androidbinary.String `xml:"http://schemas.android.com/apk/res/android logo,attr"`
-	ManageSpaceActivity   androidbinary.String `xml:"http://schemas.android.com/apk/res/android manageSpaceActivity,attr"`
-	Name                  androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
-	Permission            androidbinary.String `xml:"http://schemas.android.com/apk/res/android permission,attr"`
-	Persistent            androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android persistent,attr"`
-	Process               androidbinary.String `xml:"http://schemas.android.com/apk/res/android process,attr"`
-	RestoreAnyVersion     androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android restoreAnyVersion,attr"`
-	RequiredAccountType   androidbinary.String `xml:"http://schemas.android.com/apk/res/android requiredAccountType,attr"`
-	RestrictedAccountType androidbinary.String `xml:"http://schemas.android.com/apk/res/android restrictedAccountType,attr"`
-	SupportsRtl           androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android supportsRtl,attr"`
-	TaskAffinity          androidbinary.String `xml:"http://schemas.android.com/apk/res/android taskAffinity,attr"`
-	TestOnly              androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android testOnly,attr"`
-	Theme                 androidbinary.String `xml:"http://schemas.android.com/apk/res/android theme,attr"`
-	UIOptions             androidbinary.String `xml:"http://schemas.android.com/apk/res/android uiOptions,attr"`
-	VMSafeMode            androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android vmSafeMode,attr"`
-	Activities            []AppActivity        `xml:"activity"`
-	ActivityAliases       []AppActivityAlias   `xml:"activity-alias"`
-	MetaData              []MetaData           `xml:"meta-data"`
+	AllowTaskReparenting  androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android allowTaskReparenting,attr"`
+	AllowBackup           androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android allowBackup,attr"`
+	BackupAgent           androidbinary.String  `xml:"http://schemas.android.com/apk/res/android backupAgent,attr"`
+	DataExtractionRules   *androidbinary.String `xml:"http://schemas.android.com/apk/res/android dataExtractionRules,attr,omitempty"`
+	Debuggable            androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android debuggable,attr"`
+	Description           androidbinary.String  `xml:"http://schemas.android.com/apk/res/android description,attr"`
+	Enabled               androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android enabled,attr"`
+	ExtractNativeLibs     *androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android extractNativeLibs,attr,omitempty"`
+	FullBackupContent     *androidbinary.String `xml:"http://schemas.android.com/apk/res/android fullBackupContent,attr,omitempty"`
+	HasCode               androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android hasCode,attr"`
+	HardwareAccelerated   androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android hardwareAccelerated,attr"`
+	Icon                  androidbinary.String  `xml:"http://schemas.android.com/apk/res/android icon,attr"`
+	KillAfterRestore      androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android killAfterRestore,attr"`
+	LargeHeap             androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android largeHeap,attr"`
+	Label                 androidbinary.String  `xml:"http://schemas.android.com/apk/res/android label,attr"`
+	Logo                  androidbinary.String  `xml:"http://schemas.android.com/apk/res/android logo,attr"`
+	ManageSpaceActivity   androidbinary.String  `xml:"http://schemas.android.com/apk/res/android manageSpaceActivity,attr"`
+	Name                  androidbinary.String  `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	NetworkSecurityConfig *androidbinary.String `xml:"http://schemas.android.com/apk/res/android networkSecurityConfig,attr,omitempty"`
+	Permission            androidbinary.String  `xml:"http://schemas.android.com/apk/res/android permission,attr"`
+	Persistent            androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android persistent,attr"`
+	Process               androidbinary.String  `xml:"http://schemas.android.com/apk/res/android process,attr"`
+	RestoreAnyVersion     androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android restoreAnyVersion,attr"`
+	RequiredAccountType   androidbinary.String  `xml:"http://schemas.android.com/apk/res/android requiredAccountType,attr"`
+	RestrictedAccountType androidbinary.String  `xml:"http://schemas.android.com/apk/res/android restrictedAccountType,attr"`
+	SupportsRtl           androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android supportsRtl,attr"`
+	TaskAffinity          androidbinary.String  `xml:"http://schemas.android.com/apk/res/android taskAffinity,attr"`
+	TestOnly              androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android testOnly,attr"`
+	Theme                 androidbinary.String  `xml:"http://schemas.android.com/apk/res/android theme,attr"`
+	UIOptions             androidbinary.String  `xml:"http://schemas.android.com/apk/res/android uiOptions,attr"`
+	UsesCleartextTraffic  *androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android usesCleartextTraffic,attr,omitempty"`
+	VMSafeMode            androidbinary.Bool    `xml:"http://schemas.android.com/apk/res/android vmSafeMode,attr"`
+	Activities            []AppActivity         `xml:"activity"`
+	ActivityAliases       []AppActivityAlias    `xml:"activity-alias"`
+	Services              []AppService          `xml:"service"`
+	Receivers             []AppReceiver         `xml:"receiver"`
+	MetaData              []MetaData            `xml:"meta-data"`
+	UsesLibraries         []UsesLibrary         `xml:"uses-library"`
 }
 
 // UsesSDK is target SDK version.
@@ -98,15 +153,71 @@ type UsesPermission struct {
 	Max  androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android maxSdkVersion,attr"`
 }
 
+// UsesFeature is a hardware or software feature used by an application.
+type UsesFeature struct {
+	Name        androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	Required    *androidbinary.Bool  `xml:"http://schemas.android.com/apk/res/android required,attr,omitempty"`
+	GLESVersion androidbinary.String `xml:"http://schemas.android.com/apk/res/android glEsVersion,attr"`
+}
+
+// SupportsScreens is the screen sizes an application supports.
+type SupportsScreens struct {
+	Resizeable              *androidbinary.Bool `xml:"http://schemas.android.com/apk/res/android resizeable,attr,omitempty"`
+	SmallScreens            *androidbinary.Bool `xml:"http://schemas.android.com/apk/res/android smallScreens,attr,omitempty"`
+	NormalScreens           *androidbinary.Bool `xml:"http://schemas.android.com/apk/res/android normalScreens,attr,omitempty"`
+	LargeScreens            *androidbinary.Bool `xml:"http://schemas.android.com/apk/res/android largeScreens,attr,omitempty"`
+	XLargeScreens           *androidbinary.Bool `xml:"http://schemas.android.com/apk/res/android xlargeScreens,attr,omitempty"`
+	AnyDensity              *androidbinary.Bool `xml:"http://schemas.android.com/apk/res/android anyDensity,attr,omitempty"`
+	RequiresSmallestWidthDp androidbinary.Int32 `xml:"http://schemas.android.com/apk/res/android requiresSmallestWidthDp,attr"`
+	CompatibleWidthLimitDp  androidbinary.Int32 `xml:"http://schemas.android.com/apk/res/android compatibleWidthLimitDp,attr"`
+	LargestWidthLimitDp     androidbinary.Int32 `xml:"http://schemas.android.com/apk/res/android largestWidthLimitDp,attr"`
+}
+
+// CompatibleScreen is a screen configuration an application is compatible with.
+type CompatibleScreen struct {
+	ScreenSize    androidbinary.String `xml:"http://schemas.android.com/apk/res/android screenSize,attr"`
+	ScreenDensity androidbinary.String `xml:"http://schemas.android.com/apk/res/android screenDensity,attr"`
+}
+
+// CompatibleScreens is the screen configurations an application is compatible with.
+type CompatibleScreens struct {
+	Screens []CompatibleScreen `xml:"screen"`
+}
+
+// Permission is a security permission declared by an application.
+type Permission struct {
+	Name            androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	Label           androidbinary.String `xml:"http://schemas.android.com/apk/res/android label,attr"`
+	Description     androidbinary.String `xml:"http://schemas.android.com/apk/res/android description,attr"`
+	PermissionGroup androidbinary.String `xml:"http://schemas.android.com/apk/res/android permissionGroup,attr"`
+	ProtectionLevel androidbinary.String `xml:"http://schemas.android.com/apk/res/android protectionLevel,attr"`
+}
+
+// UsesSplit is a split APK that a split APK depends on.
+type UsesSplit struct {
+	Name androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
+}
+
 // Manifest is a manifest of an APK.
 type Manifest struct {
-	Package                   androidbinary.String `xml:"package,attr"`
-	CompileSDKVersion         androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android compileSdkVersion,attr"`
-	CompileSDKVersionCodename androidbinary.String `xml:"http://schemas.android.com/apk/res/android compileSdkVersionCodename,attr"`
-	VersionCode               androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android versionCode,attr"`
-	VersionName               androidbinary.String `xml:"http://schemas.android.com/apk/res/android versionName,attr"`
-	App                       Application          `xml:"application"`
-	Instrument                Instrumentation      `xml:"instrumentation"`
-	SDK                       UsesSDK              `xml:"uses-sdk"`
-	UsesPermissions           []UsesPermission     `xml:"uses-permission"`
+	Package                   androidbinary.String  `xml:"package,attr"`
+	Split                     *androidbinary.String `xml:"split,attr,omitempty"`
+	ConfigForSplit            *androidbinary.String `xml:"configForSplit,attr,omitempty"`
+	IsFeatureSplit            *androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android isFeatureSplit,attr,omitempty"`
+	RequiredSplitTypes        *androidbinary.String `xml:"http://schemas.android.com/apk/res/android requiredSplitTypes,attr,omitempty"`
+	SplitTypes                *androidbinary.String `xml:"http://schemas.android.com/apk/res/android splitTypes,attr,omitempty"`
+	CompileSDKVersion         androidbinary.Int32   `xml:"http://schemas.android.com/apk/res/android compileSdkVersion,attr"`
+	CompileSDKVersionCodename androidbinary.String  `xml:"http://schemas.android.com/apk/res/android compileSdkVersionCodename,attr"`
+	VersionCode               androidbinary.Int32   `xml:"http://schemas.android.com/apk/res/android versionCode,attr"`
+	VersionName               androidbinary.String  `xml:"http://schemas.android.com/apk/res/android versionName,attr"`
+	App                       Application           `xml:"application"`
+	Instrument                Instrumentation       `xml:"instrumentation"`
+	SDK                       UsesSDK               `xml:"uses-sdk"`
+	UsesPermissions           []UsesPermission      `xml:"uses-permission"`
+	UsesPermissionsSDK23      []UsesPermission      `xml:"uses-permission-sdk-23"`
+	Permissions               []Permission          `xml:"permission"`
+	UsesFeatures              []UsesFeature         `xml:"uses-feature"`
+	SupportsScreens           *SupportsScreens      `xml:"supports-screens"`
+	CompatibleScreens         *CompatibleScreens    `xml:"compatible-screens"`
+	UsesSplits                []UsesSplit           `xml:"uses-split"`
 }
@@ -0,0 +1,100 @@
+package apk
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SigningCertificate is a signing certificate of an APK.
+type SigningCertificate struct {
+	// Scheme is the signature scheme that the certificate is taken from.
+	Scheme SignatureScheme
+
+	// Certificate is the signing certificate.
+	Certificate *x509.Certificate
+
+	// SHA256Fingerprint and SHA1Fingerprint is the lower-case hex encoded digests of the DER encoded certificate,
+	// same as the output of `apksigner verify --print-certs`.
+	SHA256Fingerprint string
+	SHA1Fingerprint   string
+
+	// Subject is the distinguished name of the subject.
+	Subject string
+
+	// NotBefore and NotAfter is the validity period of the certificate.
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+func newSigningCertificate(scheme SignatureScheme, cert *x509.Certificate) SigningCertificate {
+	sha256sum := sha256.Sum256(cert.Raw)
+	sha1sum := sha1.Sum(cert.Raw)
+	return SigningCertificate{
+		Scheme:            scheme,
+		Certificate:       cert,
+		SHA256Fingerprint: hex.EncodeToString(sha256sum[:]),
+		SHA1Fingerprint:   hex.EncodeToString(sha1sum[:]),
+		Subject:           cert.Subject.String(),
+		NotBefore:         cert.NotBefore,
+		NotAfter:          cert.NotAfter,
+	}
+}
+
+// SigningCertificates returns the signing certificates of the APK, one for each signer.
+// The certificates are taken from the APK Signature Scheme v3 if present,
+// then the v2, and then the JAR signing (v1).
+// It returns an error if the signatures of the scheme are not verified or any scheme is broken,
+// and ErrUnsigned if the APK is not signed.
+func (k *Apk) SigningCertificates() ([]SigningCertificate, error) {
+	verification, err := k.VerifySignatures()
+	if err != nil && !errors.Is(err, ErrNoSigningBlock) {
+		return nil, err
+	}
+	if verification != nil {
+		// a scheme that fails to parse must not fall back to the older schemes.
+		if len(verification.Errors) != 0 {
+			return nil, fmt.Errorf("apk: the signatures are not verified: %v", verification.Errors)
+		}
+		schemes := []struct {
+			scheme  SignatureScheme
+			signers []*Signer
+		}{
+			{SignatureSchemeV3, verification.V3},
+			{SignatureSchemeV2, verification.V2},
+		}
+		for _, s := range schemes {
+			if len(s.signers) == 0 {
+				continue
+			}
+			certs := make([]SigningCertificate, 0, len(s.signers))
+			for _, signer := range s.signers {
+				if !signer.Verified() || len(signer.Certificates) == 0 {
+					return nil, fmt.Errorf("apk: the %v signature is not verified: %v", s.scheme, signer.Errors)
+				}
+				certs = append(certs, newSigningCertificate(s.scheme, signer.Certificates[0]))
+			}
+			return certs, nil
+		}
+	}
+
+	jar, err := k.VerifyJARSignature()
+	if errors.Is(err, ErrNoJARSignature) {
+		return nil, ErrUnsigned
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !jar.Verified() {
+		return nil, fmt.Errorf("apk: the %v signature is not verified", SignatureSchemeV1)
+	}
+	certs := make([]SigningCertificate, 0, len(jar.Signers))
+	for _, signer := range jar.Signers {
+		certs = append(certs, newSigningCertificate(SignatureSchemeV1, signer.Certificates[0]))
+	}
+	return certs, nil
+}
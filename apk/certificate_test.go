@@ -0,0 +1,69 @@
+package apk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSigningCertificates(t *testing.T) {
+	t.Run("v3", func(t *testing.T) {
+		v2Signer := newTestSigner(t, "v2")
+		v3Signer := newTestSigner(t, "v3")
+		data := signTestAPK(t, readHelloWorld(t), func(digest []byte) map[uint32][]byte {
+			return map[uint32][]byte{
+				signatureSchemeV2BlockID: encodeTestSigner(t, v2Signer, SignatureSchemeV2, digest),
+				signatureSchemeV3BlockID: encodeTestSigner(t, v3Signer, SignatureSchemeV3, digest),
+			}
+		})
+		certs, err := openTestAPK(t, data).SigningCertificates()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(certs) != 1 || certs[0].Scheme != SignatureSchemeV3 || certs[0].Subject != "CN=v3" {
+			t.Errorf("unexpected certificates: %+v", certs)
+		}
+	})
+
+	t.Run("broken v3", func(t *testing.T) {
+		// the broken v3 block must not be downgraded to the valid v2 signature.
+		v2Signer := newTestSigner(t, "v2")
+		data := signTestAPK(t, readHelloWorld(t), func(digest []byte) map[uint32][]byte {
+			return map[uint32][]byte{
+				signatureSchemeV2BlockID: encodeTestSigner(t, v2Signer, SignatureSchemeV2, digest),
+				signatureSchemeV3BlockID: {0x01, 0x02, 0x03},
+			}
+		})
+		certs, err := openTestAPK(t, data).SigningCertificates()
+		if err == nil {
+			t.Errorf("want error, got %+v", certs)
+		}
+	})
+
+	t.Run("v1", func(t *testing.T) {
+		certs, err := openTestJAR(t, nil, nil).SigningCertificates()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(certs) != 1 {
+			t.Fatalf("want 1 certificate, got %d", len(certs))
+		}
+		cert := certs[0]
+		if cert.Scheme != SignatureSchemeV1 || cert.Subject != "CN=v1 test" {
+			t.Errorf("unexpected certificate: %+v", cert)
+		}
+		// openssl x509 -noout -fingerprint -sha256
+		if want := "41d3e4c1b333e52f9b0e907390c77a20dc896ec91185031d1ada67b17a2ff697"; cert.SHA256Fingerprint != want {
+			t.Errorf("want SHA-256 fingerprint %s, got %s", want, cert.SHA256Fingerprint)
+		}
+		if want := "57aae990f44b3d7ac9049913ddaafc45c928f4ec"; cert.SHA1Fingerprint != want {
+			t.Errorf("want SHA-1 fingerprint %s, got %s", want, cert.SHA1Fingerprint)
+		}
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		_, err := openTestAPK(t, readHelloWorld(t)).SigningCertificates()
+		if !errors.Is(err, ErrUnsigned) {
+			t.Errorf("want ErrUnsigned, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,353 @@
+package apk
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// ScreenSize is a generalized screen size.
+// The values are same as the values of the screenSize attribute in <compatible-screens>.
+type ScreenSize int
+
+// The constants for ScreenSize.
+const (
+	ScreenSizeUndefined ScreenSize = 0
+	ScreenSizeSmall     ScreenSize = 200
+	ScreenSizeNormal    ScreenSize = 300
+	ScreenSizeLarge     ScreenSize = 400
+	ScreenSizeXLarge    ScreenSize = 500
+)
+
+// String returns the name of the screen size.
+func (s ScreenSize) String() string {
+	switch s {
+	case ScreenSizeSmall:
+		return "small"
+	case ScreenSizeNormal:
+		return "normal"
+	case ScreenSizeLarge:
+		return "large"
+	case ScreenSizeXLarge:
+		return "xlarge"
+	}
+	return strconv.Itoa(int(s))
+}
+
+// DeviceProfile is a configuration of a device.
+type DeviceProfile struct {
+	// SDKVersion is the API level of the device.
+	SDKVersion int32
+
+	// ABIs is the list of the ABIs the device supports, e.g. "arm64-v8a".
+	ABIs []string
+
+	// ScreenSize is the generalized screen size of the device.
+	// ScreenSizeUndefined skips the screen checks.
+	ScreenSize ScreenSize
+
+	// Density is the screen density of the device in dpi.
+	Density int
+
+	// Features is the list of the features the device has, e.g. "android.hardware.camera".
+	Features []string
+
+	// GLESVersion is the OpenGL ES version of the device.
+	// The higher 16 bits are the major version and the lower 16 bits are the minor version,
+	// e.g. 0x00030002 for OpenGL ES 3.2.
+	GLESVersion uint32
+
+	// Libraries is the list of the shared libraries available on the device.
+	Libraries []string
+}
+
+// IncompatibilityReason is a category of incompatibilities.
+type IncompatibilityReason string
+
+// The constants for IncompatibilityReason.
+const (
+	IncompatibleSDKVersion IncompatibilityReason = "sdk-version"
+	IncompatibleFeature    IncompatibilityReason = "feature"
+	IncompatibleScreen     IncompatibilityReason = "screen"
+	IncompatibleABI        IncompatibilityReason = "abi"
+	IncompatibleLibrary    IncompatibilityReason = "library"
+)
+
+// Incompatibility is a reason why an APK can't be installed on a device.
+type Incompatibility struct {
+	Reason  IncompatibilityReason
+	Message string
+}
+
+func (i Incompatibility) String() string {
+	return fmt.Sprintf("%s: %s", i.Reason, i.Message)
+}
+
+// CompatibilityReport is the result of the compatibility check.
+type CompatibilityReport struct {
+	Incompatibilities []Incompatibility
+}
+
+// Installable returns whether the APK can be installed on the device.
+func (r *CompatibilityReport) Installable() bool {
+	return len(r.Incompatibilities) == 0
+}
+
+func (r *CompatibilityReport) add(reason IncompatibilityReason, format string, args ...interface{}) {
+	r.Incompatibilities = append(r.Incompatibilities, Incompatibility{
+		Reason:  reason,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// boolOrDefault returns the value of v, or def if v is not specified.
+func boolOrDefault(v *androidbinary.Bool, def bool) (bool, error) {
+	if v == nil {
+		return def, nil
+	}
+	return v.Bool()
+}
+
+func parseUint32(s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 0, 32)
+	return uint32(v), err
+}
+
+var screenSizeNames = map[string]ScreenSize{
+	"small":  ScreenSizeSmall,
+	"normal": ScreenSizeNormal,
+	"large":  ScreenSizeLarge,
+	"xlarge": ScreenSizeXLarge,
+}
+
+var screenDensityNames = map[string]int{
+	"ldpi":    120,
+	"mdpi":    160,
+	"tvdpi":   213,
+	"hdpi":    240,
+	"xhdpi":   320,
+	"xxhdpi":  480,
+	"xxxhdpi": 640,
+}
+
+// CheckCompatibility reports whether the APK can be installed on the device,
+// following the filters of the package manager and Google Play.
+// It checks minSdkVersion and maxSdkVersion, required <uses-feature>, <supports-screens>,
+// <compatible-screens>, native libraries in lib/ and required <uses-library>.
+func (k *Apk) CheckCompatibility(device *DeviceProfile) (*CompatibilityReport, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	report := new(CompatibilityReport)
+	checks := []func(*DeviceProfile, *CompatibilityReport) error{
+		k.checkSDKVersion,
+		k.checkFeatures,
+		k.checkScreens,
+		k.checkABIs,
+		k.checkLibraries,
+	}
+	for _, check := range checks {
+		if err := check(device, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (k *Apk) checkSDKVersion(device *DeviceProfile, report *CompatibilityReport) error {
+	sdk := k.manifest.SDK
+	min, err := sdk.Min.Int32()
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) && numErr.Err == strconv.ErrSyntax {
+		// the codename of a preview platform, such as "Tiramisu".
+		// the APK is installed only on the preview, which DeviceProfile doesn't express.
+		report.add(IncompatibleSDKVersion, "minSdkVersion %s is the codename of a preview platform", numErr.Num)
+	} else if err != nil {
+		return err
+	}
+	if min == 0 {
+		min = 1
+	}
+	max, err := sdk.Max.Int32()
+	if err != nil {
+		return err
+	}
+	if numErr == nil && device.SDKVersion < min {
+		report.add(IncompatibleSDKVersion, "minSdkVersion %d is higher than the device API level %d", min, device.SDKVersion)
+	}
+	if max != 0 && device.SDKVersion > max {
+		report.add(IncompatibleSDKVersion, "maxSdkVersion %d is lower than the device API level %d", max, device.SDKVersion)
+	}
+	return nil
+}
+
+func (k *Apk) checkFeatures(device *DeviceProfile, report *CompatibilityReport) error {
+	for _, feature := range k.manifest.UsesFeatures {
+		required, err := boolOrDefault(feature.Required, true)
+		if err != nil {
+			return err
+		}
+		if !required {
+			continue
+		}
+
+		name, err := feature.Name.String()
+		if err != nil {
+			return err
+		}
+		if name != "" && !contains(device.Features, name) {
+			report.add(IncompatibleFeature, "the device doesn't have the feature %s", name)
+		}
+
+		gles, err := feature.GLESVersion.String()
+		if err != nil {
+			return err
+		}
+		if gles == "" {
+			continue
+		}
+		version, err := parseUint32(gles)
+		if err != nil {
+			return fmt.Errorf("apk: invalid glEsVersion %q: %w", gles, err)
+		}
+		if version > device.GLESVersion {
+			report.add(IncompatibleFeature, "OpenGL ES %d.%d is required, but the device supports %d.%d",
+				version>>16, version&0xffff, device.GLESVersion>>16, device.GLESVersion&0xffff)
+		}
+	}
+	return nil
+}
+
+func (k *Apk) checkScreens(device *DeviceProfile, report *CompatibilityReport) error {
+	if device.ScreenSize == ScreenSizeUndefined {
+		return nil
+	}
+
+	if screens := k.manifest.SupportsScreens; screens != nil {
+		sizes := []struct {
+			size ScreenSize
+			attr *androidbinary.Bool
+		}{
+			{ScreenSizeSmall, screens.SmallScreens},
+			{ScreenSizeNormal, screens.NormalScreens},
+			{ScreenSizeLarge, screens.LargeScreens},
+			{ScreenSizeXLarge, screens.XLargeScreens},
+		}
+
+		// Larger screens than supported ones run the app in the screen compatibility mode,
+		// so only smaller screens than every supported size are filtered.
+		smallest := ScreenSizeUndefined
+		for _, s := range sizes {
+			supported, err := boolOrDefault(s.attr, true)
+			if err != nil {
+				return err
+			}
+			if supported {
+				smallest = s.size
+				break
+			}
+		}
+		if smallest == ScreenSizeUndefined || device.ScreenSize < smallest {
+			report.add(IncompatibleScreen, "the app doesn't support %s screens", device.ScreenSize)
+		}
+	}
+
+	if screens := k.manifest.CompatibleScreens; screens != nil {
+		found := false
+		for _, screen := range screens.Screens {
+			size, density, err := parseCompatibleScreen(screen)
+			if err != nil {
+				return err
+			}
+			if size == device.ScreenSize && density == device.Density {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report.add(IncompatibleScreen, "the app isn't compatible with %s screens of %d dpi", device.ScreenSize, device.Density)
+		}
+	}
+	return nil
+}
+
+func parseCompatibleScreen(screen CompatibleScreen) (ScreenSize, int, error) {
+	s, err := screen.ScreenSize.String()
+	if err != nil {
+		return 0, 0, err
+	}
+	size, ok := screenSizeNames[s]
+	if !ok {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, 0, fmt.Errorf("apk: invalid screenSize %q", s)
+		}
+		size = ScreenSize(v)
+	}
+
+	d, err := screen.ScreenDensity.String()
+	if err != nil {
+		return 0, 0, err
+	}
+	density, ok := screenDensityNames[d]
+	if !ok {
+		density, err = strconv.Atoi(d)
+		if err != nil {
+			return 0, 0, fmt.Errorf("apk: invalid screenDensity %q", d)
+		}
+	}
+	return size, density, nil
+}
+
+// nativeABIs returns the ABIs of the native libraries in the APK.
+func (k *Apk) nativeABIs() []string {
+	var abis []string
+	for _, file := range k.zipreader.File {
+		abi, _, ok := parseNativeLibraryPath(file.Name)
+		if ok && !contains(abis, abi) {
+			abis = append(abis, abi)
+		}
+	}
+	return abis
+}
+
+func (k *Apk) checkABIs(device *DeviceProfile, report *CompatibilityReport) error {
+	if k.zipreader == nil {
+		return nil
+	}
+	abis := k.nativeABIs()
+	if len(abis) == 0 {
+		// the app has no native code.
+		return nil
+	}
+	for _, abi := range device.ABIs {
+		if contains(abis, abi) {
+			return nil
+		}
+	}
+	report.add(IncompatibleABI, "the app has native libraries only for %s", strings.Join(abis, ", "))
+	return nil
+}
+
+func (k *Apk) checkLibraries(device *DeviceProfile, report *CompatibilityReport) error {
+	for _, lib := range k.manifest.App.UsesLibraries {
+		required, err := boolOrDefault(lib.Required, true)
+		if err != nil {
+			return err
+		}
+		if !required {
+			continue
+		}
+		name, err := lib.Name.String()
+		if err != nil {
+			return err
+		}
+		if !contains(device.Libraries, name) {
+			report.add(IncompatibleLibrary, "the device doesn't have the shared library %s", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+const compatibilityTestManifest = `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.compatibility">
+	<uses-sdk android:minSdkVersion="21" android:targetSdkVersion="30"/>
+	<uses-feature android:name="android.hardware.camera"/>
+	<uses-feature android:name="android.hardware.bluetooth" android:required="false"/>
+	<uses-feature android:glEsVersion="0x00030000" android:required="true"/>
+	<supports-screens android:smallScreens="false"/>
+	<application>
+		<uses-library android:name="com.google.android.maps"/>
+		<uses-library android:name="org.apache.http.legacy" android:required="false"/>
+	</application>
+</manifest>`
+
+func TestCheckCompatibility(t *testing.T) {
+	apk := loadTestManifest(t, compatibilityTestManifest)
+	data := newTestZip(t, map[string][]byte{
+		"lib/arm64-v8a/libfoo.so":   []byte("dummy"),
+		"lib/armeabi-v7a/libfoo.so": []byte("dummy"),
+	}, zip.Deflate)
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apk.zipreader = r
+
+	device := &DeviceProfile{
+		SDKVersion:  30,
+		ABIs:        []string{"arm64-v8a", "armeabi-v7a"},
+		ScreenSize:  ScreenSizeNormal,
+		Density:     480,
+		Features:    []string{"android.hardware.camera"},
+		GLESVersion: 0x00030002,
+		Libraries:   []string{"com.google.android.maps"},
+	}
+	report, err := apk.CheckCompatibility(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Installable() {
+		t.Errorf("want installable, got %v", report.Incompatibilities)
+	}
+
+	device = &DeviceProfile{
+		SDKVersion:  19,
+		ABIs:        []string{"x86_64"},
+		ScreenSize:  ScreenSizeSmall,
+		Density:     160,
+		GLESVersion: 0x00020000,
+	}
+	report, err = apk.CheckCompatibility(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reasons := map[IncompatibilityReason]int{}
+	for _, i := range report.Incompatibilities {
+		reasons[i.Reason]++
+	}
+	want := map[IncompatibilityReason]int{
+		IncompatibleSDKVersion: 1,
+		IncompatibleFeature:    2,
+		IncompatibleScreen:     1,
+		IncompatibleABI:        1,
+		IncompatibleLibrary:    1,
+	}
+	for reason, n := range want {
+		if reasons[reason] != n {
+			t.Errorf("%s: want %d incompatibilities, got %d: %v", reason, n, reasons[reason], report.Incompatibilities)
+		}
+	}
+}
+
+func TestCheckCompatibilityPreviewSDK(t *testing.T) {
+	apk := loadTestManifest(t, `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.preview">
+	<uses-sdk android:minSdkVersion="Tiramisu" android:targetSdkVersion="Tiramisu"/>
+</manifest>`)
+	report, err := apk.CheckCompatibility(&DeviceProfile{SDKVersion: 33})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Incompatibilities) != 1 || report.Incompatibilities[0].Reason != IncompatibleSDKVersion {
+		t.Errorf("want an sdk-version incompatibility, got %v", report.Incompatibilities)
+	}
+}
+
+func TestCheckCompatibleScreens(t *testing.T) {
+	apk := loadTestManifest(t, `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.screens">
+	<compatible-screens>
+		<screen android:screenSize="normal" android:screenDensity="xhdpi"/>
+		<screen android:screenSize="400" android:screenDensity="480"/>
+	</compatible-screens>
+</manifest>`)
+
+	cases := []struct {
+		size    ScreenSize
+		density int
+		want    bool
+	}{
+		{ScreenSizeNormal, 320, true},
+		{ScreenSizeLarge, 480, true},
+		{ScreenSizeNormal, 480, false},
+	}
+	for _, c := range cases {
+		report, err := apk.CheckCompatibility(&DeviceProfile{
+			SDKVersion: 30,
+			ScreenSize: c.size,
+			Density:    c.density,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Installable() != c.want {
+			t.Errorf("%s %ddpi: want %v, got %v", c.size, c.density, c.want, report.Incompatibilities)
+		}
+	}
+}
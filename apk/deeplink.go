@@ -0,0 +1,182 @@
+package apk
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+const (
+	actionView        = "android.intent.action.VIEW"
+	categoryBrowsable = "android.intent.category.BROWSABLE"
+)
+
+// DeepLink is a URL pattern that an activity handles.
+type DeepLink struct {
+	// Type is the type of the component, ComponentActivity or ComponentActivityAlias.
+	Type ComponentType
+
+	// Activity is the name of the activity or the activity alias.
+	Activity string
+
+	// Scheme is the scheme of the URL, e.g. "https".
+	Scheme string
+
+	// Host is the host of the URL. It may start with the wildcard "*".
+	// It is empty if the intent filter accepts any host.
+	Host string
+
+	// Port is the port of the URL. It is empty if the intent filter accepts any port.
+	Port string
+
+	// Path is the pattern of the path. It is nil if the intent filter accepts any path.
+	Path *PatternMatcher
+
+	// AutoVerify is the autoVerify flag of the intent filter.
+	AutoVerify bool
+}
+
+// IsAppLink returns whether l is an Android App Link,
+// which is a verified http or https URL.
+func (l DeepLink) IsAppLink() bool {
+	return l.AutoVerify && (l.Scheme == "http" || l.Scheme == "https")
+}
+
+// String returns the URL pattern in a human readable format.
+// Paths of PatternPrefix and PatternSuffix are expressed with the wildcard "*".
+func (l DeepLink) String() string {
+	var buf strings.Builder
+	buf.WriteString(l.Scheme)
+	buf.WriteString("://")
+	if l.Host == "" {
+		buf.WriteString("*")
+	} else {
+		buf.WriteString(l.Host)
+	}
+	if l.Port != "" {
+		buf.WriteString(":")
+		buf.WriteString(l.Port)
+	}
+	if l.Path == nil {
+		return buf.String()
+	}
+	switch l.Path.Type {
+	case PatternPrefix:
+		buf.WriteString(l.Path.Pattern)
+		buf.WriteString("*")
+	case PatternSuffix:
+		buf.WriteString("*")
+		buf.WriteString(l.Path.Pattern)
+	default:
+		buf.WriteString(l.Path.Pattern)
+	}
+	return buf.String()
+}
+
+func isBrowsableFilter(filter *intentFilter) bool {
+	return contains(filter.actions, actionView) && contains(filter.categories, categoryBrowsable)
+}
+
+func (f *intentFilter) deepLinks(typ ComponentType, name string, autoVerify bool) []DeepLink {
+	var links []DeepLink
+	authorities := f.authorities
+	if len(authorities) == 0 {
+		// the intent filter accepts any host
+		authorities = []authorityEntry{{port: -1}}
+	}
+	paths := make([]*PatternMatcher, 0, len(f.paths))
+	for i := range f.paths {
+		paths = append(paths, &f.paths[i])
+	}
+	if len(paths) == 0 || len(f.authorities) == 0 {
+		// paths are ignored if no authority is declared
+		paths = []*PatternMatcher{nil}
+	}
+
+	for _, scheme := range f.schemes {
+		for _, authority := range authorities {
+			host := authority.host
+			if authority.wild {
+				host = "*" + host
+			}
+			var port string
+			if authority.port >= 0 {
+				port = strconv.Itoa(authority.port)
+			}
+			for _, path := range paths {
+				links = append(links, DeepLink{
+					Type:       typ,
+					Activity:   name,
+					Scheme:     scheme,
+					Host:       host,
+					Port:       port,
+					Path:       path,
+					AutoVerify: autoVerify,
+				})
+			}
+		}
+	}
+	return links
+}
+
+// DeepLinks returns every URL pattern that the activities handle.
+// They are derived from the intent filters with the action android.intent.action.VIEW
+// and the category android.intent.category.BROWSABLE.
+func (k *Apk) DeepLinks() ([]DeepLink, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	var links []DeepLink
+	add := func(typ ComponentType, name androidbinary.String, filters []ActivityIntentFilter) error {
+		for _, filter := range filters {
+			f := newIntentFilter(filter)
+			if !isBrowsableFilter(f) {
+				continue
+			}
+			s, err := name.String()
+			if err != nil {
+				return err
+			}
+			// autoVerify is false if it is not set.
+			autoVerify, err := boolOrDefault(filter.AutoVerify, false)
+			if err != nil {
+				return err
+			}
+			links = append(links, f.deepLinks(typ, s, autoVerify)...)
+		}
+		return nil
+	}
+
+	app := k.manifest.App
+	for _, act := range app.Activities {
+		if err := add(ComponentActivity, act.Name, act.IntentFilters); err != nil {
+			return nil, err
+		}
+	}
+	for _, alias := range app.ActivityAliases {
+		if err := add(ComponentActivityAlias, alias.Name, alias.IntentFilters); err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+// AppLinkHosts returns the hosts that are subject to the Android App Links verification.
+// Each host appears only once in the result.
+func (k *Apk) AppLinkHosts() ([]string, error) {
+	links, err := k.DeepLinks()
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	seen := map[string]bool{}
+	for _, link := range links {
+		if !link.IsAppLink() || link.Host == "" || seen[link.Host] {
+			continue
+		}
+		seen[link.Host] = true
+		hosts = append(hosts, link.Host)
+	}
+	return hosts, nil
+}
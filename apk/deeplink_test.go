@@ -0,0 +1,81 @@
+package apk
+
+import (
+	"reflect"
+	"testing"
+)
+
+const deepLinkTestManifest = `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.deeplink">
+	<application>
+		<activity android:name="com.example.deeplink.MainActivity">
+			<intent-filter>
+				<action android:name="android.intent.action.MAIN"/>
+				<category android:name="android.intent.category.LAUNCHER"/>
+			</intent-filter>
+		</activity>
+		<activity android:name="com.example.deeplink.ItemActivity">
+			<intent-filter android:autoVerify="true">
+				<action android:name="android.intent.action.VIEW"/>
+				<category android:name="android.intent.category.DEFAULT"/>
+				<category android:name="android.intent.category.BROWSABLE"/>
+				<data android:scheme="http"/>
+				<data android:scheme="https"/>
+				<data android:host="example.com"/>
+				<data android:pathPrefix="/items/"/>
+			</intent-filter>
+			<intent-filter>
+				<action android:name="android.intent.action.VIEW"/>
+				<category android:name="android.intent.category.DEFAULT"/>
+				<category android:name="android.intent.category.BROWSABLE"/>
+				<data android:scheme="example"/>
+			</intent-filter>
+		</activity>
+		<activity-alias android:name="com.example.deeplink.Alias" android:targetActivity="com.example.deeplink.ItemActivity">
+			<intent-filter>
+				<action android:name="android.intent.action.VIEW"/>
+				<category android:name="android.intent.category.BROWSABLE"/>
+				<data android:scheme="https" android:host="*.example.org" android:port="8443"/>
+			</intent-filter>
+		</activity-alias>
+	</application>
+</manifest>`
+
+func TestDeepLinks(t *testing.T) {
+	apk := loadTestManifest(t, deepLinkTestManifest)
+	links, err := apk.DeepLinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, link := range links {
+		got = append(got, link.String())
+	}
+	want := []string{
+		"http://example.com/items/*",
+		"https://example.com/items/*",
+		"example://*",
+		"https://*.example.org:8443",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if !links[0].IsAppLink() || !links[1].IsAppLink() {
+		t.Error("want http and https links with autoVerify to be App Links")
+	}
+	if links[2].IsAppLink() || links[3].IsAppLink() {
+		t.Error("want links without autoVerify not to be App Links")
+	}
+	if links[3].Type != ComponentActivityAlias || links[3].Activity != "com.example.deeplink.Alias" {
+		t.Errorf("unexpected component: %s %s", links[3].Type, links[3].Activity)
+	}
+
+	hosts, err := apk.AppLinkHosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(hosts, []string{"example.com"}) {
+		t.Errorf("want [example.com], got %v", hosts)
+	}
+}
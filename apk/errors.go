@@ -10,3 +10,55 @@ import (
 
 var newError = errors.New
 var errorf = fmt.Errorf
+
+// The errors returned by Apk and SplitSet.
+// They may be wrapped with the details, so use errors.Is to examine them.
+var (
+	// ErrNoReader is returned if the APK is not opened with OpenFile or OpenZipReader.
+	ErrNoReader = newError("apk: the APK is not opened with OpenFile or OpenZipReader")
+
+	// ErrFileNotFound is returned if a file is not found in the APK.
+	ErrFileNotFound = newError("apk: file not found")
+
+	// ErrFileTooLarge is returned if a file in the APK exceeds OpenOptions.MaxFileSize.
+	ErrFileTooLarge = newError("apk: file too large")
+
+	// ErrLabelNotResolved is returned if the label is a resource reference that can't be resolved.
+	ErrLabelNotResolved = newError("apk: unable to convert label-id to string")
+
+	// ErrIconNotResolved is returned if the icon is a resource reference that can't be resolved.
+	ErrIconNotResolved = newError("apk: unable to convert icon-id to icon path")
+
+	// ErrNoMainActivity is returned if no activity handles the launcher intent.
+	ErrNoMainActivity = newError("apk: no main activity found")
+
+	// ErrActivityNotFound is returned if an activity is not declared in the manifest.
+	ErrActivityNotFound = newError("apk: activity not found")
+
+	// ErrNotDeclared is returned if the manifest doesn't declare the resource, such as the backup rules.
+	ErrNotDeclared = newError("apk: not declared in the manifest")
+
+	// ErrNoTheme is returned if neither the activity nor the application sets a theme.
+	ErrNoTheme = newError("apk: no theme")
+
+	// ErrUnsigned is returned if the APK is signed with none of the signature schemes.
+	ErrUnsigned = newError("apk: the APK is not signed")
+
+	// ErrNoJARSignature is returned if the APK is not signed with the JAR signing (APK Signature Scheme v1).
+	ErrNoJARSignature = newError("apk: JAR signature not found")
+
+	// ErrNoSigningBlock is returned if the APK has no APK Signing Block.
+	ErrNoSigningBlock = newError("apk: APK Signing Block not found")
+
+	// ErrNoBaseAPK is returned if a split set has no base APK.
+	ErrNoBaseAPK = newError("apk: base APK not found in the split set")
+
+	// ErrMultipleBaseAPKs is returned if a split set has more than one base APK.
+	ErrMultipleBaseAPKs = newError("apk: multiple base APKs in the split set")
+
+	// ErrDuplicateSplit is returned if a split set has the split APKs of the same name.
+	ErrDuplicateSplit = newError("apk: duplicate split")
+
+	// ErrInconsistentSplit is returned if a split APK has the different package or version from the base APK.
+	ErrInconsistentSplit = newError("apk: inconsistent split")
+)
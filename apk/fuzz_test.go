@@ -0,0 +1,59 @@
+//go:build go1.18
+// +build go1.18
+
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+func FuzzOpenZipReader(f *testing.F) {
+	for _, name := range []string{"testdata/helloworld.apk", "testdata/v1signed.zip"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add(newTestZip(f, map[string][]byte{
+		"AndroidManifest.xml": encodeTestXML(f, `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.fuzz">
+	<application android:label="label" android:icon="icon.png">
+		<activity android:name=".MainActivity">
+			<intent-filter>
+				<action android:name="android.intent.action.MAIN"></action>
+				<category android:name="android.intent.category.LAUNCHER"></category>
+			</intent-filter>
+		</activity>
+	</application>
+</manifest>`),
+		"icon.png": {0x89, 'P', 'N', 'G'},
+	}, zip.Store))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		opts := []*OpenOptions{
+			nil,
+			{AllowMissingResources: true, LazyResources: true},
+			{AllowMissingResources: true, ParseOptions: &androidbinary.ParseOptions{Lenient: true}},
+		}
+		for _, opt := range opts {
+			apk, err := OpenZipReaderWithOptions(bytes.NewReader(data), int64(len(data)), opt)
+			if err != nil {
+				continue
+			}
+			apk.PackageName()
+			apk.Label(nil)
+			apk.Icon(nil)
+			apk.MainActivity()
+			apk.Warnings()
+			apk.Permissions()
+			apk.DeepLinks()
+			apk.NativeLibraries()
+			apk.VerifySignatures()
+		}
+	})
+}
@@ -0,0 +1,543 @@
+package apk
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// Intent is an abstract description of an operation to be performed.
+// It is used for simulating the intent resolution of the package manager.
+//
+// Context.startActivity implicitly adds the category android.intent.category.DEFAULT,
+// so include it in Categories to mimic resolving activities.
+type Intent struct {
+	// Action is the action to be performed, e.g. "android.intent.action.VIEW".
+	// The empty action matches any intent filter.
+	Action string
+
+	// Categories are the additional categories of the intent.
+	Categories []string
+
+	// Data is the URI of the data to operate on, e.g. "https://example.com/foo".
+	Data string
+
+	// Type is the MIME type of the data, e.g. "image/png".
+	Type string
+}
+
+// ComponentType is a type of application components.
+type ComponentType string
+
+// The constants for ComponentType.
+const (
+	ComponentActivity      ComponentType = "activity"
+	ComponentActivityAlias ComponentType = "activity-alias"
+	ComponentService       ComponentType = "service"
+	ComponentReceiver      ComponentType = "receiver"
+)
+
+// IntentMatch is a component that has an intent filter matching with an intent.
+type IntentMatch struct {
+	Type   ComponentType
+	Name   string
+	Filter ActivityIntentFilter
+}
+
+// PatternType is a type of patterns in intent filters.
+type PatternType int
+
+// The constants for PatternType.
+// They are same as the constants of android.os.PatternMatcher.
+const (
+	// PatternLiteral matches if the string is exactly the pattern.
+	PatternLiteral PatternType = 0
+
+	// PatternPrefix matches if the string starts with the pattern.
+	PatternPrefix PatternType = 1
+
+	// PatternSimpleGlob matches with a simple glob syntax.
+	// '.' matches any character and '*' matches zero or more instances of the previous character.
+	PatternSimpleGlob PatternType = 2
+
+	// PatternAdvancedGlob matches with an advanced glob syntax.
+	// It supports character ranges and the '+' and '{m,n}' quantifiers too.
+	PatternAdvancedGlob PatternType = 3
+
+	// PatternSuffix matches if the string ends with the pattern.
+	PatternSuffix PatternType = 4
+)
+
+// PatternMatcher is a pattern of paths or scheme specific parts in intent filters.
+type PatternMatcher struct {
+	Pattern string
+	Type    PatternType
+}
+
+// Match returns whether s matches with the pattern.
+func (p PatternMatcher) Match(s string) bool {
+	switch p.Type {
+	case PatternLiteral:
+		return s == p.Pattern
+	case PatternPrefix:
+		return strings.HasPrefix(s, p.Pattern)
+	case PatternSimpleGlob:
+		return matchGlobPattern(p.Pattern, s)
+	case PatternAdvancedGlob:
+		re, err := compileAdvancedPattern(p.Pattern)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(s)
+	case PatternSuffix:
+		return strings.HasSuffix(s, p.Pattern)
+	}
+	return false
+}
+
+// matchGlobPattern is a port of PatternMatcher.matchGlobPattern of Android.
+func matchGlobPattern(pattern, match string) bool {
+	np := len(pattern)
+	if np <= 0 {
+		return len(match) <= 0
+	}
+	nm := len(match)
+	at := func(i int) byte {
+		if i < np {
+			return pattern[i]
+		}
+		return 0
+	}
+
+	ip, im := 0, 0
+	nextChar := pattern[0]
+	for ip < np && im < nm {
+		c := nextChar
+		ip++
+		nextChar = at(ip)
+		escaped := c == '\\'
+		if escaped {
+			c = nextChar
+			ip++
+			nextChar = at(ip)
+		}
+		if nextChar == '*' {
+			if !escaped && c == '.' {
+				if ip >= np-1 {
+					// at the end with a pattern match, so all is good without checking!
+					return true
+				}
+				ip++
+				nextChar = pattern[ip]
+				// Consume everything until the next character in the pattern is found.
+				if nextChar == '\\' {
+					ip++
+					nextChar = at(ip)
+				}
+				for im < nm && match[im] != nextChar {
+					im++
+				}
+				if im == nm {
+					// the next character in the pattern didn't exist in the match.
+					return false
+				}
+				ip++
+				nextChar = at(ip)
+				im++
+			} else {
+				// Consume only characters matching the one before '*'.
+				for im < nm && match[im] == c {
+					im++
+				}
+				ip++
+				nextChar = at(ip)
+			}
+		} else {
+			if c != '.' && match[im] != c {
+				return false
+			}
+			im++
+		}
+	}
+
+	if ip >= np && im >= nm {
+		return true
+	}
+
+	// We may have finished the match string, but still have a '.*' at the end of the pattern,
+	// which should still count as a match.
+	if ip == np-2 && pattern[ip] == '.' && pattern[ip+1] == '*' {
+		return true
+	}
+	return false
+}
+
+// compileAdvancedPattern converts an advanced glob pattern into a regular expression.
+func compileAdvancedPattern(pattern string) (*regexp.Regexp, error) {
+	var buf strings.Builder
+	buf.WriteString("^(?:")
+	inRange := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case inRange:
+			if c == ']' {
+				inRange = false
+			}
+			buf.WriteByte(c)
+		case c == '[':
+			inRange = true
+			buf.WriteByte(c)
+		case c == '.', c == '*', c == '+':
+			buf.WriteByte(c)
+		case c == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				buf.WriteString(regexp.QuoteMeta(pattern[i:]))
+				i = len(pattern)
+				continue
+			}
+			buf.WriteString(pattern[i : i+end+1])
+			i += end
+		default:
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	buf.WriteString(")$")
+	return regexp.Compile(buf.String())
+}
+
+type authorityEntry struct {
+	host string
+	wild bool
+	port int
+}
+
+func (a authorityEntry) match(u *url.URL) bool {
+	host := u.Hostname()
+	if host == "" {
+		return false
+	}
+	if a.wild {
+		if len(host) < len(a.host) {
+			return false
+		}
+		host = host[len(host)-len(a.host):]
+	}
+	if !strings.EqualFold(host, a.host) {
+		return false
+	}
+	if a.port >= 0 {
+		return strconv.Itoa(a.port) == u.Port()
+	}
+	return true
+}
+
+// intentFilter is an intent filter in which every reference is resolved.
+type intentFilter struct {
+	actions         []string
+	categories      []string
+	schemes         []string
+	ssps            []PatternMatcher
+	authorities     []authorityEntry
+	paths           []PatternMatcher
+	types           []string
+	hasPartialTypes bool
+}
+
+func newIntentFilter(filter ActivityIntentFilter) *intentFilter {
+	f := new(intentFilter)
+	for _, action := range filter.Actions {
+		if s, err := action.Name.String(); err == nil {
+			f.actions = append(f.actions, s)
+		}
+	}
+	for _, category := range filter.Categories {
+		if s, err := category.Name.String(); err == nil {
+			f.categories = append(f.categories, s)
+		}
+	}
+	for _, data := range filter.Data {
+		f.addData(data)
+	}
+	return f
+}
+
+func (f *intentFilter) addData(data ActivityData) {
+	if s, err := data.Scheme.String(); err == nil && s != "" {
+		f.schemes = append(f.schemes, s)
+	}
+	if host, err := data.Host.String(); err == nil && host != "" {
+		entry := authorityEntry{port: -1}
+		if strings.HasPrefix(host, "*") {
+			entry.wild = true
+			host = host[1:]
+		}
+		entry.host = host
+		if s, err := data.Port.String(); err == nil && s != "" {
+			if port, err := strconv.Atoi(s); err == nil {
+				entry.port = port
+			}
+		}
+		f.authorities = append(f.authorities, entry)
+	}
+
+	patterns := []struct {
+		value androidbinary.String
+		typ   PatternType
+		dst   *[]PatternMatcher
+	}{
+		{data.Path, PatternLiteral, &f.paths},
+		{data.PathPrefix, PatternPrefix, &f.paths},
+		{data.PathPattern, PatternSimpleGlob, &f.paths},
+		{data.PathAdvancedPattern, PatternAdvancedGlob, &f.paths},
+		{data.PathSuffix, PatternSuffix, &f.paths},
+		{data.SSP, PatternLiteral, &f.ssps},
+		{data.SSPPrefix, PatternPrefix, &f.ssps},
+		{data.SSPPattern, PatternSimpleGlob, &f.ssps},
+	}
+	for _, p := range patterns {
+		if s, err := p.value.String(); err == nil && s != "" {
+			*p.dst = append(*p.dst, PatternMatcher{Pattern: s, Type: p.typ})
+		}
+	}
+
+	if typ, err := data.MimeType.String(); err == nil && typ != "" {
+		f.addDataType(typ)
+	}
+}
+
+func (f *intentFilter) addDataType(typ string) {
+	slash := strings.IndexByte(typ, '/')
+	if slash <= 0 || len(typ) < slash+2 {
+		// malformed MIME type
+		return
+	}
+	if len(typ) == slash+2 && typ[slash+1] == '*' {
+		f.types = append(f.types, typ[:slash])
+		f.hasPartialTypes = true
+		return
+	}
+	f.types = append(f.types, typ)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *intentFilter) match(intent *Intent) bool {
+	if intent.Action != "" && !contains(f.actions, intent.Action) {
+		return false
+	}
+	if !f.matchData(intent) {
+		return false
+	}
+	for _, category := range intent.Categories {
+		if !contains(f.categories, category) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *intentFilter) matchData(intent *Intent) bool {
+	var data *url.URL
+	if intent.Data != "" {
+		var err error
+		data, err = url.Parse(intent.Data)
+		if err != nil {
+			return false
+		}
+	}
+
+	if f.types == nil && f.schemes == nil {
+		return intent.Type == "" && data == nil
+	}
+
+	var scheme string
+	if data != nil {
+		scheme = data.Scheme
+	}
+	if f.schemes != nil {
+		if !contains(f.schemes, scheme) {
+			return false
+		}
+		matchSSP := false
+		if f.ssps != nil && data != nil {
+			ssp := schemeSpecificPart(intent.Data)
+			for _, p := range f.ssps {
+				if p.Match(ssp) {
+					matchSSP = true
+					break
+				}
+			}
+		}
+		if !matchSSP {
+			switch {
+			case f.authorities != nil:
+				if !f.matchAuthority(data) {
+					return false
+				}
+				if f.paths != nil && !f.matchPath(data) {
+					return false
+				}
+			case f.ssps != nil && data != nil:
+				// the scheme specific part doesn't match.
+				return false
+			}
+		}
+	} else {
+		// Special case: match either an Intent with no data URI, or with a scheme: URI.
+		// This is to give a convenience for the common case where you want to deal with data in a content provider,
+		// which is done by type, and we don't want to force everyone to say they handle content: or file: URIs.
+		if scheme != "" && scheme != "content" && scheme != "file" {
+			return false
+		}
+	}
+
+	if f.types != nil {
+		return f.findMimeType(intent.Type)
+	}
+	return intent.Type == ""
+}
+
+func (f *intentFilter) matchAuthority(data *url.URL) bool {
+	if data == nil {
+		return false
+	}
+	for _, a := range f.authorities {
+		if a.match(data) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *intentFilter) matchPath(data *url.URL) bool {
+	if data == nil || data.Opaque != "" {
+		// opaque URIs have no path.
+		return false
+	}
+	for _, p := range f.paths {
+		if p.Match(data.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *intentFilter) findMimeType(typ string) bool {
+	if typ == "" {
+		return false
+	}
+	if contains(f.types, typ) {
+		return true
+	}
+
+	// Deal with an Intent wanting to match every type in the IntentFilter.
+	if typ == "*/*" {
+		return len(f.types) > 0
+	}
+
+	// Deal with this IntentFilter wanting to match every Intent type.
+	if f.hasPartialTypes && contains(f.types, "*") {
+		return true
+	}
+
+	slash := strings.IndexByte(typ, '/')
+	if slash > 0 {
+		if f.hasPartialTypes && contains(f.types, typ[:slash]) {
+			return true
+		}
+		if len(typ) == slash+2 && typ[slash+1] == '*' {
+			// Need to look through all types for one that matches our base...
+			for _, v := range f.types {
+				if strings.HasPrefix(v, typ[:slash+1]) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// schemeSpecificPart returns the part of the URI between the scheme and the fragment.
+func schemeSpecificPart(uri string) string {
+	if i := strings.IndexByte(uri, ':'); i >= 0 {
+		uri = uri[i+1:]
+	}
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		uri = uri[:i]
+	}
+	if s, err := url.PathUnescape(uri); err == nil {
+		return s
+	}
+	return uri
+}
+
+// Match returns whether the intent filter matches with the intent,
+// following the rules of IntentFilter.match of Android.
+// References that can't be resolved are ignored.
+func (filter ActivityIntentFilter) Match(intent *Intent) bool {
+	return newIntentFilter(filter).match(intent)
+}
+
+// ResolveIntent returns the components that have an intent filter matching with the intent.
+// It doesn't consider whether the components are exported, enabled or protected by permissions.
+func (k *Apk) ResolveIntent(intent *Intent) ([]IntentMatch, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	var matches []IntentMatch
+	add := func(typ ComponentType, name androidbinary.String, filters []ActivityIntentFilter) error {
+		for _, filter := range filters {
+			if !filter.Match(intent) {
+				continue
+			}
+			s, err := name.String()
+			if err != nil {
+				return err
+			}
+			matches = append(matches, IntentMatch{
+				Type:   typ,
+				Name:   s,
+				Filter: filter,
+			})
+		}
+		return nil
+	}
+
+	app := k.manifest.App
+	for _, act := range app.Activities {
+		if err := add(ComponentActivity, act.Name, act.IntentFilters); err != nil {
+			return nil, err
+		}
+	}
+	for _, alias := range app.ActivityAliases {
+		if err := add(ComponentActivityAlias, alias.Name, alias.IntentFilters); err != nil {
+			return nil, err
+		}
+	}
+	for _, service := range app.Services {
+		if err := add(ComponentService, service.Name, service.IntentFilters); err != nil {
+			return nil, err
+		}
+	}
+	for _, receiver := range app.Receivers {
+		if err := add(ComponentReceiver, receiver.Name, receiver.IntentFilters); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
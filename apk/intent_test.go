@@ -0,0 +1,214 @@
+package apk
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func loadTestManifest(t *testing.T, data string) *Apk {
+	t.Helper()
+	var manifest Manifest
+	if err := xml.Unmarshal([]byte(data), &manifest); err != nil {
+		t.Fatal(err)
+	}
+	return &Apk{manifest: manifest}
+}
+
+const intentTestManifest = `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.intent">
+	<application>
+		<activity android:name="com.example.intent.MainActivity">
+			<intent-filter>
+				<action android:name="android.intent.action.MAIN"/>
+				<category android:name="android.intent.category.LAUNCHER"/>
+			</intent-filter>
+		</activity>
+		<activity android:name="com.example.intent.ViewActivity">
+			<intent-filter>
+				<action android:name="android.intent.action.VIEW"/>
+				<category android:name="android.intent.category.DEFAULT"/>
+				<category android:name="android.intent.category.BROWSABLE"/>
+				<data android:scheme="https" android:host="example.com"/>
+				<data android:pathPrefix="/items/"/>
+				<data android:pathPattern="/users/.*/profile"/>
+			</intent-filter>
+			<intent-filter>
+				<action android:name="android.intent.action.VIEW"/>
+				<category android:name="android.intent.category.DEFAULT"/>
+				<data android:scheme="example" android:host="*.example.org" android:port="8080"/>
+			</intent-filter>
+		</activity>
+		<activity android:name="com.example.intent.ShareActivity">
+			<intent-filter>
+				<action android:name="android.intent.action.SEND"/>
+				<category android:name="android.intent.category.DEFAULT"/>
+				<data android:mimeType="image/*"/>
+			</intent-filter>
+		</activity>
+		<service android:name="com.example.intent.SyncService">
+			<intent-filter>
+				<action android:name="com.example.intent.SYNC"/>
+			</intent-filter>
+		</service>
+		<receiver android:name="com.example.intent.BootReceiver">
+			<intent-filter>
+				<action android:name="android.intent.action.BOOT_COMPLETED"/>
+			</intent-filter>
+		</receiver>
+	</application>
+</manifest>`
+
+func TestResolveIntent(t *testing.T) {
+	apk := loadTestManifest(t, intentTestManifest)
+
+	cases := []struct {
+		intent Intent
+		want   []string
+	}{
+		{
+			Intent{
+				Action:     "android.intent.action.MAIN",
+				Categories: []string{"android.intent.category.LAUNCHER"},
+			},
+			[]string{"com.example.intent.MainActivity"},
+		},
+		{
+			Intent{
+				Action:     "android.intent.action.VIEW",
+				Categories: []string{"android.intent.category.DEFAULT", "android.intent.category.BROWSABLE"},
+				Data:       "https://example.com/items/42",
+			},
+			[]string{"com.example.intent.ViewActivity"},
+		},
+		{
+			Intent{
+				Action:     "android.intent.action.VIEW",
+				Categories: []string{"android.intent.category.DEFAULT"},
+				Data:       "https://EXAMPLE.com/users/shogo/profile",
+			},
+			[]string{"com.example.intent.ViewActivity"},
+		},
+		{
+			// the path doesn't match
+			Intent{
+				Action: "android.intent.action.VIEW",
+				Data:   "https://example.com/other",
+			},
+			nil,
+		},
+		{
+			// the host doesn't match
+			Intent{
+				Action: "android.intent.action.VIEW",
+				Data:   "https://example.net/items/42",
+			},
+			nil,
+		},
+		{
+			Intent{
+				Action: "android.intent.action.VIEW",
+				Data:   "example://www.example.org:8080/",
+			},
+			[]string{"com.example.intent.ViewActivity"},
+		},
+		{
+			// the port doesn't match
+			Intent{
+				Action: "android.intent.action.VIEW",
+				Data:   "example://www.example.org/",
+			},
+			nil,
+		},
+		{
+			// the category BROWSABLE isn't declared
+			Intent{
+				Action:     "android.intent.action.VIEW",
+				Categories: []string{"android.intent.category.BROWSABLE"},
+				Data:       "example://www.example.org:8080/",
+			},
+			nil,
+		},
+		{
+			Intent{
+				Action: "android.intent.action.SEND",
+				Type:   "image/png",
+			},
+			[]string{"com.example.intent.ShareActivity"},
+		},
+		{
+			Intent{
+				Action: "android.intent.action.SEND",
+				Data:   "content://com.example.provider/images/1",
+				Type:   "image/png",
+			},
+			[]string{"com.example.intent.ShareActivity"},
+		},
+		{
+			Intent{
+				Action: "android.intent.action.SEND",
+				Type:   "text/plain",
+			},
+			nil,
+		},
+		{
+			Intent{
+				Action: "com.example.intent.SYNC",
+			},
+			[]string{"com.example.intent.SyncService"},
+		},
+		{
+			Intent{
+				Action: "android.intent.action.BOOT_COMPLETED",
+			},
+			[]string{"com.example.intent.BootReceiver"},
+		},
+	}
+
+	for i, c := range cases {
+		matches, err := apk.ResolveIntent(&c.intent)
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		var got []string
+		for _, m := range matches {
+			got = append(got, m.Name)
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("#%d: want %v, got %v", i, c.want, got)
+			continue
+		}
+		for j := range got {
+			if got[j] != c.want[j] {
+				t.Errorf("#%d: want %v, got %v", i, c.want, got)
+			}
+		}
+	}
+}
+
+func TestPatternMatcher(t *testing.T) {
+	cases := []struct {
+		pattern PatternMatcher
+		input   string
+		want    bool
+	}{
+		{PatternMatcher{"/foo", PatternLiteral}, "/foo", true},
+		{PatternMatcher{"/foo", PatternLiteral}, "/foo/bar", false},
+		{PatternMatcher{"/foo", PatternPrefix}, "/foo/bar", true},
+		{PatternMatcher{".html", PatternSuffix}, "/index.html", true},
+		{PatternMatcher{".*", PatternSimpleGlob}, "/anything", true},
+		{PatternMatcher{"/a.*b", PatternSimpleGlob}, "/axxxb", true},
+		{PatternMatcher{"/a.*b", PatternSimpleGlob}, "/axxxc", false},
+		{PatternMatcher{"/ab*c", PatternSimpleGlob}, "/abbbc", true},
+		{PatternMatcher{"/ab*c", PatternSimpleGlob}, "/ac", true},
+		{PatternMatcher{"/a\\.b", PatternSimpleGlob}, "/a.b", true},
+		{PatternMatcher{"/items/[0-9]+", PatternAdvancedGlob}, "/items/123", true},
+		{PatternMatcher{"/items/[0-9]+", PatternAdvancedGlob}, "/items/abc", false},
+		{PatternMatcher{"/a{2,3}", PatternAdvancedGlob}, "/aaa", true},
+		{PatternMatcher{"/a{2,3}", PatternAdvancedGlob}, "/a", false},
+	}
+	for _, c := range cases {
+		if got := c.pattern.Match(c.input); got != c.want {
+			t.Errorf("%v.Match(%q): want %v, got %v", c.pattern, c.input, c.want, got)
+		}
+	}
+}
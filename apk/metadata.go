@@ -0,0 +1,144 @@
+package apk
+
+import (
+	"github.com/shogo82148/androidbinary"
+)
+
+// The names of the meta-data that reference the XML resources.
+const (
+	MetaDataShortcuts            = "android.app.shortcuts"
+	MetaDataAppWidgetProvider    = "android.appwidget.provider"
+	MetaDataAccessibilityService = "android.accessibilityservice"
+)
+
+// DecodeMetaData decodes the XML resource referenced by the resource attribute of the meta-data into v.
+// It returns ErrNotDeclared if the meta-data doesn't reference a resource.
+func (k *Apk) DecodeMetaData(m *MetaData, v interface{}, resConfig *androidbinary.ResTableConfig) error {
+	if err := k.Parse(); err != nil {
+		return err
+	}
+	if m.Resource == nil {
+		return ErrNotDeclared
+	}
+	id, ok := m.Resource.ResID()
+	if !ok {
+		return ErrNotDeclared
+	}
+	return k.DecodeXMLResource(id, v, resConfig)
+}
+
+// findMetaData returns the meta-data of the name, or nil if it is not found.
+func findMetaData(list []MetaData, name string) *MetaData {
+	for i := range list {
+		if n, err := list[i].Name.String(); err == nil && n == name {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// ActivityShortcuts is the static shortcuts of an activity.
+type ActivityShortcuts struct {
+	// Activity is the name of the activity or the activity alias.
+	Activity  string
+	Shortcuts *Shortcuts
+}
+
+// Shortcuts returns the static shortcuts of the activities and the activity aliases,
+// which are referenced by the android.app.shortcuts meta-data.
+func (k *Apk) Shortcuts() ([]ActivityShortcuts, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	var ret []ActivityShortcuts
+	add := func(name androidbinary.String, metaData []MetaData) error {
+		m := findMetaData(metaData, MetaDataShortcuts)
+		if m == nil {
+			return nil
+		}
+		var shortcuts Shortcuts
+		if err := k.DecodeMetaData(m, &shortcuts, nil); err != nil {
+			return err
+		}
+		activity, err := name.String()
+		if err != nil {
+			return err
+		}
+		ret = append(ret, ActivityShortcuts{Activity: activity, Shortcuts: &shortcuts})
+		return nil
+	}
+	for _, act := range k.manifest.App.Activities {
+		if err := add(act.Name, act.MetaData); err != nil {
+			return nil, err
+		}
+	}
+	for _, alias := range k.manifest.App.ActivityAliases {
+		if err := add(alias.Name, alias.MetaData); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// AppWidget is an app widget provided by a receiver.
+type AppWidget struct {
+	// Receiver is the name of the receiver of the widget updates.
+	Receiver string
+	Info     *AppWidgetProviderInfo
+}
+
+// AppWidgets returns the app widgets of the receivers, which are referenced by the android.appwidget.provider meta-data.
+func (k *Apk) AppWidgets() ([]AppWidget, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	var ret []AppWidget
+	for _, receiver := range k.manifest.App.Receivers {
+		m := findMetaData(receiver.MetaData, MetaDataAppWidgetProvider)
+		if m == nil {
+			continue
+		}
+		var info AppWidgetProviderInfo
+		if err := k.DecodeMetaData(m, &info, nil); err != nil {
+			return nil, err
+		}
+		name, err := receiver.Name.String()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, AppWidget{Receiver: name, Info: &info})
+	}
+	return ret, nil
+}
+
+// AccessibilityService is an accessibility service in the application.
+type AccessibilityService struct {
+	// Service is the name of the service.
+	Service string
+	Info    *AccessibilityServiceInfo
+}
+
+// AccessibilityServices returns the accessibility services,
+// whose configurations are referenced by the android.accessibilityservice meta-data.
+func (k *Apk) AccessibilityServices() ([]AccessibilityService, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	var ret []AccessibilityService
+	for _, service := range k.manifest.App.Services {
+		m := findMetaData(service.MetaData, MetaDataAccessibilityService)
+		if m == nil {
+			continue
+		}
+		var info AccessibilityServiceInfo
+		if err := k.DecodeMetaData(m, &info, nil); err != nil {
+			return nil, err
+		}
+		name, err := service.Name.String()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, AccessibilityService{Service: name, Info: &info})
+	}
+	return ret, nil
+}
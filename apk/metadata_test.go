@@ -0,0 +1,74 @@
+package apk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMetaDataResources(t *testing.T) {
+	apk := newTestXMLResourcesAPK(t, map[string]string{
+		"AndroidManifest.xml": `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.helloworld">
+	<application>
+		<activity android:name="com.example.helloworld.MainActivity">
+			<meta-data android:name="android.app.shortcuts" android:resource="@0x7F040000"/>
+		</activity>
+		<receiver android:name="com.example.helloworld.WidgetProvider">
+			<meta-data android:name="android.appwidget.provider" android:resource="@0x7F040001"/>
+		</receiver>
+		<service android:name="com.example.helloworld.AccessibilityService" android:permission="android.permission.BIND_ACCESSIBILITY_SERVICE">
+			<meta-data android:name="android.accessibilityservice" android:resource="@0x7F040002"/>
+			<meta-data android:name="com.example.value" android:value="value"/>
+		</service>
+	</application>
+</manifest>`,
+		"res/layout/abc_action_bar_title_item.xml": `<shortcuts xmlns:android="http://schemas.android.com/apk/res/android">
+	<shortcut android:shortcutId="compose" android:shortcutShortLabel="Compose">
+		<intent android:action="android.intent.action.VIEW" android:targetClass="com.example.helloworld.ComposeActivity"/>
+	</shortcut>
+</shortcuts>`,
+		"res/layout/abc_action_bar_up_container.xml":         `<appwidget-provider xmlns:android="http://schemas.android.com/apk/res/android" android:minWidth="40.0dip" android:targetCellWidth="2" android:updatePeriodMillis="86400000" android:resizeMode="0x00000003"/>`,
+		"res/layout/abc_action_bar_view_list_nav_layout.xml": `<accessibility-service xmlns:android="http://schemas.android.com/apk/res/android" android:canRetrieveWindowContent="true" android:notificationTimeout="100" android:packageNames="com.example.target"/>`,
+	})
+
+	shortcuts, err := apk.Shortcuts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shortcuts) != 1 || shortcuts[0].Activity != "com.example.helloworld.MainActivity" {
+		t.Fatalf("unexpected shortcuts: %+v", shortcuts)
+	}
+	if s := shortcuts[0].Shortcuts.Shortcuts; len(s) != 1 || s[0].ShortcutID.MustString() != "compose" {
+		t.Errorf("unexpected shortcuts: %+v", s)
+	}
+
+	widgets, err := apk.AppWidgets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(widgets) != 1 || widgets[0].Receiver != "com.example.helloworld.WidgetProvider" {
+		t.Fatalf("unexpected widgets: %+v", widgets)
+	}
+	info := widgets[0].Info
+	if info.MinWidth.MustString() != "40.0dip" || info.TargetCellWidth.MustInt32() != 2 || info.UpdatePeriodMillis.MustInt32() != 86400000 || info.ResizeMode.MustString() != "0x00000003" {
+		t.Errorf("unexpected widget: %+v", info)
+	}
+
+	services, err := apk.AccessibilityServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].Service != "com.example.helloworld.AccessibilityService" {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+	if s := services[0].Info; !s.CanRetrieveWindowContent.MustBool() || s.NotificationTimeout.MustInt32() != 100 || s.PackageNames.MustString() != "com.example.target" {
+		t.Errorf("unexpected service: %+v", s)
+	}
+
+	metaData := apk.Manifest().App.Services[0].MetaData
+	if len(metaData) != 2 {
+		t.Fatalf("unexpected meta-data: %+v", metaData)
+	}
+	if err := apk.DecodeMetaData(&metaData[1], &struct{}{}, nil); !errors.Is(err, ErrNotDeclared) {
+		t.Errorf("want ErrNotDeclared, got %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// The page sizes that native libraries should be aligned on.
+const (
+	PageSize4K  = 4 * 1024
+	PageSize16K = 16 * 1024
+)
+
+// maxELFHeaderSize is the limit of the size of the ELF header and the program header table.
+const maxELFHeaderSize = 1 << 20
+
+// NativeLibrary is a native library in lib/<abi>/ of an APK.
+type NativeLibrary struct {
+	// ABI is the ABI of the library, e.g. "arm64-v8a".
+	ABI string
+
+	// Name is the file name of the library, e.g. "libfoo.so".
+	Name string
+
+	// Path is the path of the library in the APK, e.g. "lib/arm64-v8a/libfoo.so".
+	Path string
+
+	// Size is the uncompressed size of the library.
+	Size uint64
+
+	// CompressedSize is the compressed size of the library.
+	CompressedSize uint64
+
+	// Method is the compression method of the zip entry, e.g. zip.Store or zip.Deflate.
+	Method uint16
+
+	// Offset is the offset of the library data in the APK.
+	Offset int64
+
+	// Class is the class of the ELF file, elf.ELFCLASS32 or elf.ELFCLASS64.
+	Class elf.Class
+
+	// Machine is the architecture of the ELF file.
+	Machine elf.Machine
+
+	// LoadAlignment is the minimum alignment of the PT_LOAD segments, where p_align 0 counts as 1.
+	// It is zero if the library has no PT_LOAD segment.
+	LoadAlignment uint64
+
+	// Err is the error in reading the ELF header, if the library is not a valid ELF file.
+	// Class, Machine and LoadAlignment are unknown in that case.
+	Err error
+}
+
+// IsStored returns whether the library is stored without compression.
+func (l *NativeLibrary) IsStored() bool {
+	return l.Method == zip.Store
+}
+
+// IsPageAligned returns whether the library is stored without compression
+// and its data starts at a boundary of pageSize, so that it can be mapped directly from the APK.
+func (l *NativeLibrary) IsPageAligned(pageSize int64) bool {
+	return l.IsStored() && l.Offset%pageSize == 0
+}
+
+// SupportsPageSize returns whether every PT_LOAD segment is aligned on pageSize,
+// so that the library can be loaded on devices with the page size.
+func (l *NativeLibrary) SupportsPageSize(pageSize uint64) bool {
+	return l.LoadAlignment >= pageSize && l.LoadAlignment%pageSize == 0
+}
+
+// parseNativeLibraryPath parses a path like "lib/<abi>/<name>.so".
+func parseNativeLibraryPath(name string) (abi, lib string, ok bool) {
+	if !strings.HasPrefix(name, "lib/") || !strings.HasSuffix(name, ".so") {
+		return "", "", false
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// NativeLibraries returns the native libraries in the APK grouped by ABI.
+// The libraries that are not valid ELF files are also returned, with their errors in Err.
+func (k *Apk) NativeLibraries() (map[string][]NativeLibrary, error) {
+	libs := make(map[string][]NativeLibrary)
+	for _, file := range k.zipreader.File {
+		abi, name, ok := parseNativeLibraryPath(file.Name)
+		if !ok {
+			continue
+		}
+		offset, err := file.DataOffset()
+		if err != nil {
+			return nil, err
+		}
+		lib := NativeLibrary{
+			ABI:            abi,
+			Name:           name,
+			Path:           file.Name,
+			Size:           file.UncompressedSize64,
+			CompressedSize: file.CompressedSize64,
+			Method:         file.Method,
+			Offset:         offset,
+		}
+		if err := readELFHeader(file, &lib); err != nil {
+			// a broken library doesn't fail the others.
+			lib.Err = errorf("apk: failed to read %s: %w", file.Name, err)
+		}
+		libs[abi] = append(libs[abi], lib)
+	}
+	for _, l := range libs {
+		sort.Slice(l, func(i, j int) bool {
+			return l[i].Name < l[j].Name
+		})
+	}
+	return libs, nil
+}
+
+// readELFHeader reads the ELF header and the program header table of the file.
+// It reads only the beginning of the file, so it works well with compressed files.
+func readELFHeader(file *zip.File, lib *NativeLibrary) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	var ident [elf.EI_NIDENT]byte
+	if _, err := io.ReadFull(rc, ident[:]); err != nil {
+		return err
+	}
+	if !bytes.Equal(ident[:4], []byte(elf.ELFMAG)) {
+		return fmt.Errorf("invalid ELF magic number: %x", ident[:4])
+	}
+
+	var order binary.ByteOrder
+	switch elf.Data(ident[elf.EI_DATA]) {
+	case elf.ELFDATA2LSB:
+		order = binary.LittleEndian
+	case elf.ELFDATA2MSB:
+		order = binary.BigEndian
+	default:
+		return fmt.Errorf("unknown ELF data encoding: %v", elf.Data(ident[elf.EI_DATA]))
+	}
+
+	// read the rest of the ELF header
+	var phoff, phentsize, phnum uint64
+	read := uint64(elf.EI_NIDENT)
+	lib.Class = elf.Class(ident[elf.EI_CLASS])
+	switch lib.Class {
+	case elf.ELFCLASS32:
+		var hdr elf.Header32
+		buf := make([]byte, binary.Size(hdr))
+		copy(buf, ident[:])
+		if _, err := io.ReadFull(rc, buf[elf.EI_NIDENT:]); err != nil {
+			return err
+		}
+		if err := binary.Read(bytes.NewReader(buf), order, &hdr); err != nil {
+			return err
+		}
+		lib.Machine = elf.Machine(hdr.Machine)
+		phoff, phentsize, phnum = uint64(hdr.Phoff), uint64(hdr.Phentsize), uint64(hdr.Phnum)
+		read = uint64(len(buf))
+	case elf.ELFCLASS64:
+		var hdr elf.Header64
+		buf := make([]byte, binary.Size(hdr))
+		copy(buf, ident[:])
+		if _, err := io.ReadFull(rc, buf[elf.EI_NIDENT:]); err != nil {
+			return err
+		}
+		if err := binary.Read(bytes.NewReader(buf), order, &hdr); err != nil {
+			return err
+		}
+		lib.Machine = elf.Machine(hdr.Machine)
+		phoff, phentsize, phnum = hdr.Phoff, uint64(hdr.Phentsize), uint64(hdr.Phnum)
+		read = uint64(len(buf))
+	default:
+		return fmt.Errorf("unknown ELF class: %v", lib.Class)
+	}
+
+	// read the program header table
+	end := phoff + phentsize*phnum
+	if phoff < read || end < phoff || end > maxELFHeaderSize {
+		return fmt.Errorf("invalid program header table: offset %d, size %d", phoff, phentsize*phnum)
+	}
+	table := make([]byte, end-read)
+	if _, err := io.ReadFull(rc, table); err != nil {
+		return err
+	}
+	table = table[phoff-read:]
+
+	loaded := false
+	for i := uint64(0); i < phnum; i++ {
+		entry := bytes.NewReader(table[i*phentsize : (i+1)*phentsize])
+		var typ elf.ProgType
+		var align uint64
+		if lib.Class == elf.ELFCLASS32 {
+			var prog elf.Prog32
+			if err := binary.Read(entry, order, &prog); err != nil {
+				return err
+			}
+			typ, align = elf.ProgType(prog.Type), uint64(prog.Align)
+		} else {
+			var prog elf.Prog64
+			if err := binary.Read(entry, order, &prog); err != nil {
+				return err
+			}
+			typ, align = elf.ProgType(prog.Type), prog.Align
+		}
+		if typ != elf.PT_LOAD {
+			continue
+		}
+		if align == 0 {
+			// p_align 0 and 1 mean no alignment.
+			align = 1
+		}
+		if !loaded || align < lib.LoadAlignment {
+			lib.LoadAlignment = align
+			loaded = true
+		}
+	}
+	return nil
+}
+
+// ExtractNativeLibs returns whether the package installer extracts the native libraries.
+// If it is false, the libraries must be stored uncompressed and page-aligned in the APK.
+func (k *Apk) ExtractNativeLibs() (bool, error) {
+	if err := k.Parse(); err != nil {
+		return false, err
+	}
+	return boolOrDefault(k.manifest.App.ExtractNativeLibs, true)
+}
+
+// PageSizeIssue is a problem that prevents a native library from being loaded on devices with a page size.
+type PageSizeIssue struct {
+	Library NativeLibrary
+	Message string
+}
+
+func (i PageSizeIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Library.Path, i.Message)
+}
+
+// is64bitABI reports whether abi is a 64-bit ABI.
+// Only 64-bit ABIs are required to support 16 KB page sizes.
+func is64bitABI(abi string) bool {
+	return abi == "arm64-v8a" || abi == "x86_64" || abi == "riscv64"
+}
+
+// CheckPageSize checks whether the 64-bit native libraries can be loaded on devices with pageSize,
+// e.g. PageSize16K for the 16 KB page size requirement of Google Play.
+// It checks the alignment of the PT_LOAD segments,
+// and the zip alignment of uncompressed libraries if extractNativeLibs is false.
+func (k *Apk) CheckPageSize(pageSize uint64) ([]PageSizeIssue, error) {
+	libs, err := k.NativeLibraries()
+	if err != nil {
+		return nil, err
+	}
+	extract, err := k.ExtractNativeLibs()
+	if err != nil {
+		return nil, err
+	}
+
+	var abis []string
+	for abi := range libs {
+		abis = append(abis, abi)
+	}
+	sort.Strings(abis)
+
+	var issues []PageSizeIssue
+	for _, abi := range abis {
+		if !is64bitABI(abi) {
+			continue
+		}
+		for _, lib := range libs[abi] {
+			if lib.Err != nil {
+				issues = append(issues, PageSizeIssue{
+					Library: lib,
+					Message: lib.Err.Error(),
+				})
+			} else if !lib.SupportsPageSize(pageSize) {
+				issues = append(issues, PageSizeIssue{
+					Library: lib,
+					Message: fmt.Sprintf("LOAD segments are aligned on %d bytes, want %d bytes", lib.LoadAlignment, pageSize),
+				})
+			}
+			if extract {
+				continue
+			}
+			if !lib.IsStored() {
+				issues = append(issues, PageSizeIssue{
+					Library: lib,
+					Message: "the library is compressed, but extractNativeLibs is false",
+				})
+			} else if !lib.IsPageAligned(int64(pageSize)) {
+				issues = append(issues, PageSizeIssue{
+					Library: lib,
+					Message: fmt.Sprintf("the library starts at offset %d, which is not aligned on %d bytes", lib.Offset, pageSize),
+				})
+			}
+		}
+	}
+	return issues, nil
+}
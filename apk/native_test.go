@@ -0,0 +1,154 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"testing"
+)
+
+// newTestELF64 returns a minimal 64-bit ELF file that has PT_LOAD segments aligned on aligns.
+func newTestELF64(t *testing.T, machine elf.Machine, aligns ...uint64) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	hdr := elf.Header64{
+		Type:      uint16(elf.ET_DYN),
+		Machine:   uint16(machine),
+		Version:   uint32(elf.EV_CURRENT),
+		Phoff:     64,
+		Ehsize:    64,
+		Phentsize: 56,
+		Phnum:     uint16(1 + len(aligns)),
+	}
+	copy(hdr.Ident[:], elf.ELFMAG)
+	hdr.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	hdr.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	hdr.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	progs := []elf.Prog64{{Type: uint32(elf.PT_PHDR), Align: 8}}
+	for _, align := range aligns {
+		progs = append(progs, elf.Prog64{Type: uint32(elf.PT_LOAD), Align: align})
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, hdr); err != nil {
+		t.Fatal(err)
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, progs); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newTestELF32 returns a minimal 32-bit ELF file that has a PT_LOAD segment aligned on align.
+func newTestELF32(t *testing.T, machine elf.Machine, align uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	hdr := elf.Header32{
+		Type:      uint16(elf.ET_DYN),
+		Machine:   uint16(machine),
+		Version:   uint32(elf.EV_CURRENT),
+		Phoff:     52,
+		Ehsize:    52,
+		Phentsize: 32,
+		Phnum:     1,
+	}
+	copy(hdr.Ident[:], elf.ELFMAG)
+	hdr.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS32)
+	hdr.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	hdr.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	prog := elf.Prog32{Type: uint32(elf.PT_LOAD), Align: align}
+	if err := binary.Write(&buf, binary.LittleEndian, hdr); err != nil {
+		t.Fatal(err)
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, prog); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNativeLibraries(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := []struct {
+		name   string
+		method uint16
+		data   []byte
+	}{
+		{"lib/arm64-v8a/libfoo.so", zip.Store, newTestELF64(t, elf.EM_AARCH64, 0x4000, 0x4000)},
+		{"lib/arm64-v8a/libbar.so", zip.Deflate, newTestELF64(t, elf.EM_AARCH64, 0x1000, 0x4000)},
+		// p_align 0 of the first segment is not overwritten by the second one.
+		{"lib/arm64-v8a/libbaz.so", zip.Store, newTestELF64(t, elf.EM_AARCH64, 0, 0x4000)},
+		{"lib/armeabi-v7a/libfoo.so", zip.Deflate, newTestELF32(t, elf.EM_ARM, 0x1000)},
+		{"lib/readme.txt", zip.Deflate, []byte("not a library")},
+		{"lib/x86_64/libbroken.so", zip.Deflate, []byte("not an ELF file")},
+	}
+	for _, f := range files {
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: f.name, Method: f.method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apk := loadTestManifest(t, `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.native">
+	<application android:extractNativeLibs="false"/>
+</manifest>`)
+	apk.zipreader = r
+
+	libs, err := apk.NativeLibraries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(libs) != 3 {
+		t.Fatalf("want 3 ABIs, got %d", len(libs))
+	}
+
+	arm64 := libs["arm64-v8a"]
+	if len(arm64) != 3 || arm64[0].Name != "libbar.so" || arm64[1].Name != "libbaz.so" || arm64[2].Name != "libfoo.so" {
+		t.Fatalf("unexpected arm64-v8a libraries: %v", arm64)
+	}
+	foo := arm64[2]
+	if !foo.IsStored() || foo.Class != elf.ELFCLASS64 || foo.Machine != elf.EM_AARCH64 {
+		t.Errorf("unexpected libfoo.so: %+v", foo)
+	}
+	if foo.LoadAlignment != 0x4000 || !foo.SupportsPageSize(PageSize16K) {
+		t.Errorf("want libfoo.so to support 16 KB pages, got alignment %#x", foo.LoadAlignment)
+	}
+	if bar := arm64[0]; bar.IsStored() || bar.SupportsPageSize(PageSize16K) || !bar.SupportsPageSize(PageSize4K) {
+		t.Errorf("unexpected libbar.so: %+v", bar)
+	}
+	if baz := arm64[1]; baz.LoadAlignment != 1 || baz.SupportsPageSize(PageSize4K) {
+		t.Errorf("want libbaz.so not to be aligned, got alignment %#x", baz.LoadAlignment)
+	}
+
+	arm := libs["armeabi-v7a"]
+	if len(arm) != 1 || arm[0].Class != elf.ELFCLASS32 || arm[0].Machine != elf.EM_ARM || arm[0].LoadAlignment != 0x1000 {
+		t.Errorf("unexpected armeabi-v7a libraries: %+v", arm)
+	}
+
+	// the broken library is reported without failing the others.
+	if broken := libs["x86_64"]; len(broken) != 1 || broken[0].Err == nil {
+		t.Errorf("want an error for libbroken.so, got %+v", broken)
+	}
+
+	issues, err := apk.CheckPageSize(PageSize16K)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// libbar.so is compressed and its segments are aligned on 4 KB.
+	// libbaz.so has a segment of p_align 0, and is not zip-aligned on 16 KB.
+	// libfoo.so is not zip-aligned on 16 KB.
+	// libbroken.so is not an ELF file, and is compressed.
+	// armeabi-v7a is ignored because it is a 32-bit ABI.
+	if len(issues) != 7 {
+		t.Errorf("want 7 issues, got %v", issues)
+	}
+}
@@ -0,0 +1,317 @@
+package apk
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// NetworkSecurityPolicy is the effective network security configuration of the application.
+// The defaults for the target SDK version and the inheritance of the configurations are applied,
+// in the same way as android.security.net.config.ManifestConfigSource.
+type NetworkSecurityPolicy struct {
+	// File is the name of the network security config file, such as "res/xml/network_security_config.xml".
+	// It is empty if the application doesn't have one, and then the usesCleartextTraffic attribute is used instead.
+	File string
+
+	// Base is the configuration for the domains not covered by Domains.
+	Base NetworkPolicy
+
+	// Domains is the configurations of the domains, in the order of the file.
+	// The nested domain configurations are flattened.
+	Domains []DomainNetworkPolicy
+
+	// DebugOverrides is the trust anchors added to all the configurations if the application is debuggable.
+	DebugOverrides []TrustAnchor
+
+	// Debuggable reports whether the application is debuggable.
+	// The trust anchors of DebugOverrides are already merged into Base and Domains if it is true.
+	Debuggable bool
+}
+
+// NetworkPolicy is the policy of the connections.
+type NetworkPolicy struct {
+	CleartextTrafficPermitted bool
+	TrustAnchors              []TrustAnchor
+
+	// Pins is the public key pins, or nil if the connections are not pinned.
+	Pins *PinPolicy
+}
+
+// DomainNetworkPolicy is the policy for the specific domains.
+type DomainNetworkPolicy struct {
+	Domains []DomainName
+	NetworkPolicy
+}
+
+// DomainName is a domain of a domain configuration.
+type DomainName struct {
+	Name              string
+	IncludeSubdomains bool
+}
+
+// TrustAnchor is a source of the trusted certificates.
+type TrustAnchor struct {
+	// Source is "system", "user" or the name of the file of the certificates, such as "res/raw/my_ca.pem".
+	Source string
+
+	// OverridePins reports whether the certificates bypass the public key pins.
+	OverridePins bool
+}
+
+// PinPolicy is the public key pins of the domains.
+type PinPolicy struct {
+	// Expiration is the date after which the pins are not enforced.
+	// It is the zero time if the pins never expire.
+	Expiration time.Time
+	Pins       []PublicKeyPin
+}
+
+// Expired reports whether the pins are expired at t.
+func (p *PinPolicy) Expired(t time.Time) bool {
+	return !p.Expiration.IsZero() && t.After(p.Expiration)
+}
+
+// PublicKeyPin is a public key pin.
+type PublicKeyPin struct {
+	// Digest is the digest algorithm, which is "SHA-256".
+	Digest string
+
+	// Value is the base64 encoded digest of the SubjectPublicKeyInfo.
+	Value string
+}
+
+// PolicyFor returns the policy for the connections to host.
+// The exact domain is preferred, and then the longest domain that includes the subdomains, as Android does.
+func (p *NetworkSecurityPolicy) PolicyFor(host string) NetworkPolicy {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	var best *DomainNetworkPolicy
+	var bestLen int
+	for i := range p.Domains {
+		d := &p.Domains[i]
+		for _, domain := range d.Domains {
+			name := strings.ToLower(domain.Name)
+			if name == host {
+				return d.NetworkPolicy
+			}
+			if domain.IncludeSubdomains && strings.HasSuffix(host, "."+name) && len(name) > bestLen {
+				best, bestLen = d, len(name)
+			}
+		}
+	}
+	if best != nil {
+		return best.NetworkPolicy
+	}
+	return p.Base
+}
+
+// NetworkSecurityConfig returns the network security configuration of the application,
+// following the networkSecurityConfig attribute of the application.
+// If the application doesn't have the configuration file, the default policy for the target SDK version
+// and the usesCleartextTraffic attribute is returned.
+func (k *Apk) NetworkSecurityConfig() (*NetworkSecurityPolicy, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	app := k.manifest.App
+	targetSDK, err := k.targetSDKVersion()
+	if err != nil {
+		return nil, err
+	}
+	debuggable, err := app.Debuggable.Bool()
+	if err != nil {
+		return nil, err
+	}
+
+	// the defaults of NetworkSecurityConfig.getDefaultBuilder.
+	base := NetworkPolicy{
+		CleartextTrafficPermitted: targetSDK < 28,
+		TrustAnchors:              []TrustAnchor{{Source: "system"}},
+	}
+	if targetSDK < 24 {
+		base.TrustAnchors = append(base.TrustAnchors, TrustAnchor{Source: "user"})
+	}
+	policy := &NetworkSecurityPolicy{
+		Base:       base,
+		Debuggable: debuggable,
+	}
+
+	var id androidbinary.ResID
+	var ok bool
+	if app.NetworkSecurityConfig != nil {
+		id, ok = app.NetworkSecurityConfig.ResID()
+	}
+	if !ok {
+		// usesCleartextTraffic is used only without the configuration file.
+		policy.Base.CleartextTrafficPermitted, err = boolOrDefault(app.UsesCleartextTraffic, base.CleartextTrafficPermitted)
+		if err != nil {
+			return nil, err
+		}
+		return policy, nil
+	}
+
+	policy.File, err = k.xmlResourceFile(id, nil)
+	if err != nil {
+		return nil, err
+	}
+	var config NetworkSecurityConfig
+	if err := k.DecodeXMLFile(policy.File, &config, nil); err != nil {
+		return nil, err
+	}
+
+	if config.DebugOverrides != nil {
+		policy.DebugOverrides, err = trustAnchors(config.DebugOverrides.TrustAnchors)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var cleartext *androidbinary.Bool
+	var anchors *TrustAnchors
+	if c := config.BaseConfig; c != nil {
+		cleartext, anchors = c.CleartextTrafficPermitted, c.TrustAnchors
+	}
+	policy.Base, err = policy.inherit(base, cleartext, anchors, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.addDomains(policy.Base, config.DomainConfigs); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// addDomains adds the domain configurations and their nested ones, which inherit parent.
+func (p *NetworkSecurityPolicy) addDomains(parent NetworkPolicy, configs []NetworkDomainConfig) error {
+	for _, c := range configs {
+		np, err := p.inherit(parent, c.CleartextTrafficPermitted, c.TrustAnchors, c.PinSet)
+		if err != nil {
+			return err
+		}
+		d := DomainNetworkPolicy{NetworkPolicy: np}
+		for _, domain := range c.Domains {
+			include, err := domain.IncludeSubdomains.Bool()
+			if err != nil {
+				return err
+			}
+			d.Domains = append(d.Domains, DomainName{
+				Name:              strings.TrimSpace(domain.Name),
+				IncludeSubdomains: include,
+			})
+		}
+		p.Domains = append(p.Domains, d)
+		if err := p.addDomains(np, c.DomainConfigs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inherit returns the policy that overrides parent with the settings of a configuration.
+func (p *NetworkSecurityPolicy) inherit(parent NetworkPolicy, cleartext *androidbinary.Bool, anchors *TrustAnchors, pinSet *PinSet) (NetworkPolicy, error) {
+	var err error
+	np := parent
+	np.CleartextTrafficPermitted, err = boolOrDefault(cleartext, parent.CleartextTrafficPermitted)
+	if err != nil {
+		return NetworkPolicy{}, err
+	}
+	if anchors != nil {
+		np.TrustAnchors, err = trustAnchors(anchors)
+		if err != nil {
+			return NetworkPolicy{}, err
+		}
+	} else {
+		np.TrustAnchors = append([]TrustAnchor(nil), parent.TrustAnchors...)
+	}
+	if p.Debuggable {
+		np.TrustAnchors = mergeTrustAnchors(np.TrustAnchors, p.DebugOverrides)
+	}
+	if pinSet != nil {
+		np.Pins, err = pinPolicy(pinSet)
+		if err != nil {
+			return NetworkPolicy{}, err
+		}
+	}
+	return np, nil
+}
+
+func trustAnchors(anchors *TrustAnchors) ([]TrustAnchor, error) {
+	if anchors == nil {
+		return nil, nil
+	}
+	ret := []TrustAnchor{}
+	for _, c := range anchors.Certificates {
+		src, err := c.Src.String()
+		if err != nil {
+			return nil, err
+		}
+		override, err := c.OverridePins.Bool()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, TrustAnchor{Source: src, OverridePins: override})
+	}
+	return ret, nil
+}
+
+// mergeTrustAnchors adds the anchors of overrides that anchors doesn't have.
+// If both have the same source, the pins are overridden if either of them overrides.
+func mergeTrustAnchors(anchors, overrides []TrustAnchor) []TrustAnchor {
+	for _, o := range overrides {
+		found := false
+		for i := range anchors {
+			if anchors[i].Source == o.Source {
+				anchors[i].OverridePins = anchors[i].OverridePins || o.OverridePins
+				found = true
+			}
+		}
+		if !found {
+			anchors = append(anchors, o)
+		}
+	}
+	return anchors
+}
+
+func pinPolicy(pinSet *PinSet) (*PinPolicy, error) {
+	policy := &PinPolicy{}
+	expiration, err := pinSet.Expiration.String()
+	if err != nil {
+		return nil, err
+	}
+	if expiration != "" {
+		policy.Expiration, err = time.Parse("2006-01-02", expiration)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, pin := range pinSet.Pins {
+		digest, err := pin.Digest.String()
+		if err != nil {
+			return nil, err
+		}
+		policy.Pins = append(policy.Pins, PublicKeyPin{
+			Digest: digest,
+			Value:  strings.TrimSpace(pin.Value),
+		})
+	}
+	return policy, nil
+}
+
+// targetSDKVersion returns the target SDK version, which defaults to the minimum SDK version.
+func (k *Apk) targetSDKVersion() (int32, error) {
+	target, err := k.manifest.SDK.Target.Int32()
+	if err != nil {
+		return 0, err
+	}
+	if target != 0 {
+		return target, nil
+	}
+	min, err := k.manifest.SDK.Min.Int32()
+	if err != nil {
+		return 0, err
+	}
+	if min == 0 {
+		min = 1
+	}
+	return min, nil
+}
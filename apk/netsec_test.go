@@ -0,0 +1,124 @@
+package apk
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNetworkSecurityConfig(t *testing.T) {
+	apk := newTestXMLResourcesAPK(t, map[string]string{
+		"AndroidManifest.xml": `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.helloworld">
+	<uses-sdk android:minSdkVersion="21" android:targetSdkVersion="30"/>
+	<application android:networkSecurityConfig="@0x7F040000" android:usesCleartextTraffic="true" android:debuggable="true"></application>
+</manifest>`,
+		"res/layout/abc_action_bar_title_item.xml": `<network-security-config>
+	<base-config>
+		<trust-anchors>
+			<certificates src="system"/>
+		</trust-anchors>
+	</base-config>
+	<domain-config cleartextTrafficPermitted="true">
+		<domain includeSubdomains="true">example.com</domain>
+		<pin-set expiration="2030-01-01">
+			<pin digest="SHA-256">7HIpactkIAq2Y49orFOOQKurWxmmSFZhBCoQYcRhJ3Y=</pin>
+		</pin-set>
+		<domain-config cleartextTrafficPermitted="false">
+			<domain>secure.example.com</domain>
+		</domain-config>
+	</domain-config>
+	<debug-overrides>
+		<trust-anchors>
+			<certificates src="user" overridePins="true"/>
+		</trust-anchors>
+	</debug-overrides>
+</network-security-config>`,
+	})
+
+	policy, err := apk.NetworkSecurityConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.File != "res/layout/abc_action_bar_title_item.xml" {
+		t.Errorf("unexpected file: %s", policy.File)
+	}
+
+	// usesCleartextTraffic is ignored, and the default of API level 30 is used.
+	if policy.Base.CleartextTrafficPermitted {
+		t.Error("want cleartext traffic not permitted")
+	}
+	anchors := []TrustAnchor{{Source: "system"}, {Source: "user", OverridePins: true}}
+	if !reflect.DeepEqual(policy.Base.TrustAnchors, anchors) {
+		t.Errorf("want %v, got %v", anchors, policy.Base.TrustAnchors)
+	}
+
+	if len(policy.Domains) != 2 {
+		t.Fatalf("want 2 domains, got %v", policy.Domains)
+	}
+	example := policy.PolicyFor("www.example.com")
+	if !example.CleartextTrafficPermitted || example.Pins == nil || len(example.Pins.Pins) != 1 {
+		t.Errorf("unexpected policy: %+v", example)
+	}
+	if example.Pins.Expired(time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC)) || !example.Pins.Expired(time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected expiration: %v", example.Pins.Expiration)
+	}
+
+	// the nested domain config inherits the pins.
+	secure := policy.PolicyFor("Secure.Example.com")
+	if secure.CleartextTrafficPermitted || secure.Pins == nil {
+		t.Errorf("unexpected policy: %+v", secure)
+	}
+	if !reflect.DeepEqual(secure.TrustAnchors, anchors) {
+		t.Errorf("want %v, got %v", anchors, secure.TrustAnchors)
+	}
+
+	// secure.example.com doesn't include its subdomains.
+	if p := policy.PolicyFor("a.secure.example.com"); !p.CleartextTrafficPermitted {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+	if p := policy.PolicyFor("example.org"); !reflect.DeepEqual(p, policy.Base) {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+}
+
+func TestNetworkSecurityConfigDefault(t *testing.T) {
+	testCases := []struct {
+		manifest  string
+		cleartext bool
+		anchors   []TrustAnchor
+	}{
+		{
+			manifest:  `<uses-sdk android:targetSdkVersion="30"/><application></application>`,
+			cleartext: false,
+			anchors:   []TrustAnchor{{Source: "system"}},
+		},
+		{
+			manifest:  `<uses-sdk android:targetSdkVersion="30"/><application android:usesCleartextTraffic="true"></application>`,
+			cleartext: true,
+			anchors:   []TrustAnchor{{Source: "system"}},
+		},
+		{
+			manifest:  `<uses-sdk android:minSdkVersion="19"/><application></application>`,
+			cleartext: true,
+			anchors:   []TrustAnchor{{Source: "system"}, {Source: "user"}},
+		},
+	}
+	for _, tc := range testCases {
+		apk := newTestXMLResourcesAPK(t, map[string]string{
+			"AndroidManifest.xml": `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.helloworld">` + tc.manifest + `</manifest>`,
+		})
+		policy, err := apk.NetworkSecurityConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy.File != "" || len(policy.Domains) != 0 {
+			t.Errorf("unexpected policy: %+v", policy)
+		}
+		if policy.Base.CleartextTrafficPermitted != tc.cleartext {
+			t.Errorf("%s: want %t, got %t", tc.manifest, tc.cleartext, policy.Base.CleartextTrafficPermitted)
+		}
+		if !reflect.DeepEqual(policy.Base.TrustAnchors, tc.anchors) {
+			t.Errorf("%s: want %v, got %v", tc.manifest, tc.anchors, policy.Base.TrustAnchors)
+		}
+	}
+}
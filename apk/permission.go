@@ -0,0 +1,344 @@
+package apk
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProtectionLevel is a protection level of a permission.
+// The lower 4 bits are the base protection level and the other bits are additional flags.
+type ProtectionLevel uint32
+
+// The constants for the base protection levels.
+// They are same as the constants of android.content.pm.PermissionInfo.
+const (
+	ProtectionNormal            ProtectionLevel = 0
+	ProtectionDangerous         ProtectionLevel = 1
+	ProtectionSignature         ProtectionLevel = 2
+	ProtectionSignatureOrSystem ProtectionLevel = 3
+	ProtectionInternal          ProtectionLevel = 4
+	ProtectionMaskBase          ProtectionLevel = 0xf
+)
+
+// The constants for the additional flags of ProtectionLevel.
+const (
+	ProtectionFlagPrivileged             ProtectionLevel = 0x10
+	ProtectionFlagDevelopment            ProtectionLevel = 0x20
+	ProtectionFlagAppOp                  ProtectionLevel = 0x40
+	ProtectionFlagPre23                  ProtectionLevel = 0x80
+	ProtectionFlagInstaller              ProtectionLevel = 0x100
+	ProtectionFlagVerifier               ProtectionLevel = 0x200
+	ProtectionFlagPreinstalled           ProtectionLevel = 0x400
+	ProtectionFlagSetup                  ProtectionLevel = 0x800
+	ProtectionFlagInstant                ProtectionLevel = 0x1000
+	ProtectionFlagRuntimeOnly            ProtectionLevel = 0x2000
+	ProtectionFlagOEM                    ProtectionLevel = 0x4000
+	ProtectionFlagVendorPrivileged       ProtectionLevel = 0x8000
+	ProtectionFlagSystemTextClassifier   ProtectionLevel = 0x10000
+	ProtectionFlagWellbeing              ProtectionLevel = 0x20000
+	ProtectionFlagDocumenter             ProtectionLevel = 0x40000
+	ProtectionFlagConfigurator           ProtectionLevel = 0x80000
+	ProtectionFlagIncidentReportApprover ProtectionLevel = 0x100000
+	ProtectionFlagAppPredictor           ProtectionLevel = 0x200000
+	ProtectionFlagModule                 ProtectionLevel = 0x400000
+	ProtectionFlagCompanion              ProtectionLevel = 0x800000
+	ProtectionFlagRetailDemo             ProtectionLevel = 0x1000000
+	ProtectionFlagRecents                ProtectionLevel = 0x2000000
+	ProtectionFlagRole                   ProtectionLevel = 0x4000000
+	ProtectionFlagKnownSigner            ProtectionLevel = 0x8000000
+)
+
+var protectionBaseNames = []struct {
+	level ProtectionLevel
+	name  string
+}{
+	{ProtectionNormal, "normal"},
+	{ProtectionDangerous, "dangerous"},
+	{ProtectionSignature, "signature"},
+	{ProtectionSignatureOrSystem, "signatureOrSystem"},
+	{ProtectionInternal, "internal"},
+}
+
+var protectionFlagNames = []struct {
+	flag ProtectionLevel
+	name string
+}{
+	{ProtectionFlagPrivileged, "privileged"},
+	{ProtectionFlagDevelopment, "development"},
+	{ProtectionFlagAppOp, "appop"},
+	{ProtectionFlagPre23, "pre23"},
+	{ProtectionFlagInstaller, "installer"},
+	{ProtectionFlagVerifier, "verifier"},
+	{ProtectionFlagPreinstalled, "preinstalled"},
+	{ProtectionFlagSetup, "setup"},
+	{ProtectionFlagInstant, "instant"},
+	{ProtectionFlagRuntimeOnly, "runtime"},
+	{ProtectionFlagOEM, "oem"},
+	{ProtectionFlagVendorPrivileged, "vendorPrivileged"},
+	{ProtectionFlagSystemTextClassifier, "textClassifier"},
+	{ProtectionFlagWellbeing, "wellbeing"},
+	{ProtectionFlagDocumenter, "documenter"},
+	{ProtectionFlagConfigurator, "configurator"},
+	{ProtectionFlagIncidentReportApprover, "incidentReportApprover"},
+	{ProtectionFlagAppPredictor, "appPredictor"},
+	{ProtectionFlagModule, "module"},
+	{ProtectionFlagCompanion, "companion"},
+	{ProtectionFlagRetailDemo, "retailDemo"},
+	{ProtectionFlagRecents, "recents"},
+	{ProtectionFlagRole, "role"},
+	{ProtectionFlagKnownSigner, "knownSigner"},
+}
+
+// Base returns the base protection level.
+func (l ProtectionLevel) Base() ProtectionLevel {
+	return l & ProtectionMaskBase
+}
+
+// Flags returns the additional flags.
+func (l ProtectionLevel) Flags() ProtectionLevel {
+	return l &^ ProtectionMaskBase
+}
+
+// String returns the protection level in the format of AndroidManifest.xml, e.g. "signature|privileged".
+func (l ProtectionLevel) String() string {
+	var names []string
+	base := l.Base()
+	found := false
+	for _, v := range protectionBaseNames {
+		if v.level == base {
+			names = append(names, v.name)
+			found = true
+			break
+		}
+	}
+	if !found {
+		names = append(names, fmt.Sprintf("0x%x", uint32(base)))
+	}
+
+	flags := l.Flags()
+	for _, v := range protectionFlagNames {
+		if flags&v.flag != 0 {
+			names = append(names, v.name)
+			flags &^= v.flag
+		}
+	}
+	if flags != 0 {
+		names = append(names, fmt.Sprintf("0x%x", uint32(flags)))
+	}
+	return strings.Join(names, "|")
+}
+
+// ParseProtectionLevel parses a protection level.
+// It accepts both of the compiled integer value, e.g. "0x00000012",
+// and the textual format of AndroidManifest.xml, e.g. "signature|privileged".
+func ParseProtectionLevel(s string) (ProtectionLevel, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ProtectionNormal, nil
+	}
+	if v, err := strconv.ParseUint(s, 0, 32); err == nil {
+		return ProtectionLevel(v), nil
+	}
+
+	var level ProtectionLevel
+LOOP:
+	for _, name := range strings.Split(s, "|") {
+		name = strings.TrimSpace(name)
+		for _, v := range protectionBaseNames {
+			if v.name == name {
+				level = (level &^ ProtectionMaskBase) | v.level
+				continue LOOP
+			}
+		}
+		for _, v := range protectionFlagNames {
+			if v.name == name {
+				level |= v.flag
+				continue LOOP
+			}
+		}
+		switch name {
+		case "system":
+			// deprecated alias of privileged
+			level |= ProtectionFlagPrivileged
+		case "ephemeral":
+			// deprecated alias of instant
+			level |= ProtectionFlagInstant
+		default:
+			return 0, fmt.Errorf("apk: unknown protection level: %q", name)
+		}
+	}
+	return level, nil
+}
+
+// LookupPermission returns the protection level of the permission defined by the Android framework.
+func LookupPermission(name string) (ProtectionLevel, bool) {
+	level, ok := frameworkPermissions[name]
+	return level, ok
+}
+
+// RequestedPermission is a permission that an application requests.
+type RequestedPermission struct {
+	// Name is the name of the permission.
+	Name string
+
+	// MaxSDKVersion is the highest API level at which the permission is requested.
+	// It is zero if there is no limit.
+	MaxSDKVersion int32
+
+	// SDK23 is true if the permission is requested by <uses-permission-sdk-23>.
+	SDK23 bool
+
+	// ProtectionLevel is the protection level of the permission.
+	// It is valid only if Known is true.
+	ProtectionLevel ProtectionLevel
+
+	// Known is true if the protection level is known.
+	Known bool
+
+	// Custom is true if the permission is declared by the application itself.
+	Custom bool
+}
+
+// DeclaredPermission is a permission that an application declares by <permission>.
+type DeclaredPermission struct {
+	Name            string
+	PermissionGroup string
+	ProtectionLevel ProtectionLevel
+}
+
+// PermissionReport is a report of the permissions of an application.
+type PermissionReport struct {
+	// Requested is the permissions requested by <uses-permission> and <uses-permission-sdk-23>.
+	Requested []RequestedPermission
+
+	// Declared is the permissions declared by <permission>.
+	Declared []DeclaredPermission
+}
+
+// ByProtectionLevel groups the requested permissions by their base protection levels.
+// The permissions whose protection levels are unknown are not included.
+func (r *PermissionReport) ByProtectionLevel() map[ProtectionLevel][]RequestedPermission {
+	ret := make(map[ProtectionLevel][]RequestedPermission)
+	for _, p := range r.Requested {
+		if !p.Known {
+			continue
+		}
+		base := p.ProtectionLevel.Base()
+		ret[base] = append(ret[base], p)
+	}
+	return ret
+}
+
+// Dangerous returns the requested permissions that are dangerous.
+func (r *PermissionReport) Dangerous() []RequestedPermission {
+	return r.ByProtectionLevel()[ProtectionDangerous]
+}
+
+// Unknown returns the requested permissions whose protection levels are unknown.
+// They are typically defined by other applications.
+func (r *PermissionReport) Unknown() []RequestedPermission {
+	var ret []RequestedPermission
+	for _, p := range r.Requested {
+		if !p.Known {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// Added returns the requested permissions that are not requested in the previous report.
+// It is useful for detecting newly added dangerous permissions between releases.
+func (r *PermissionReport) Added(previous *PermissionReport) []RequestedPermission {
+	old := make(map[string]bool)
+	if previous != nil {
+		for _, p := range previous.Requested {
+			old[p.Name] = true
+		}
+	}
+	var ret []RequestedPermission
+	for _, p := range r.Requested {
+		if !old[p.Name] {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// Permissions returns the report of the permissions requested and declared by the APK.
+func (k *Apk) Permissions() (*PermissionReport, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	report := new(PermissionReport)
+	declared := make(map[string]ProtectionLevel)
+	for _, p := range k.manifest.Permissions {
+		name, err := p.Name.String()
+		if err != nil {
+			return nil, err
+		}
+		group, err := p.PermissionGroup.String()
+		if err != nil {
+			return nil, err
+		}
+		s, err := p.ProtectionLevel.String()
+		if err != nil {
+			return nil, err
+		}
+		level, err := ParseProtectionLevel(s)
+		if err != nil {
+			return nil, err
+		}
+		declared[name] = level
+		report.Declared = append(report.Declared, DeclaredPermission{
+			Name:            name,
+			PermissionGroup: group,
+			ProtectionLevel: level,
+		})
+	}
+
+	seen := make(map[string]bool)
+	add := func(permissions []UsesPermission, sdk23 bool) error {
+		for _, p := range permissions {
+			name, err := p.Name.String()
+			if err != nil {
+				return err
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			max, err := p.Max.Int32()
+			if err != nil {
+				return err
+			}
+			req := RequestedPermission{
+				Name:          name,
+				MaxSDKVersion: max,
+				SDK23:         sdk23,
+			}
+			if level, ok := LookupPermission(name); ok {
+				req.ProtectionLevel = level
+				req.Known = true
+			} else if level, ok := declared[name]; ok {
+				req.ProtectionLevel = level
+				req.Known = true
+				req.Custom = true
+			}
+			report.Requested = append(report.Requested, req)
+		}
+		return nil
+	}
+	if err := add(k.manifest.UsesPermissions, false); err != nil {
+		return nil, err
+	}
+	if err := add(k.manifest.UsesPermissionsSDK23, true); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(report.Requested, func(i, j int) bool {
+		return report.Requested[i].Name < report.Requested[j].Name
+	})
+	return report, nil
+}
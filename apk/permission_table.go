@@ -0,0 +1,216 @@
+package apk
+
+// frameworkPermissions is the protection levels of the permissions defined by the Android framework.
+var frameworkPermissions = map[string]ProtectionLevel{
+	// dangerous (runtime) permissions
+	"android.permission.READ_CALENDAR":                   ProtectionDangerous,
+	"android.permission.WRITE_CALENDAR":                  ProtectionDangerous,
+	"android.permission.CAMERA":                          ProtectionDangerous,
+	"android.permission.READ_CONTACTS":                   ProtectionDangerous,
+	"android.permission.WRITE_CONTACTS":                  ProtectionDangerous,
+	"android.permission.GET_ACCOUNTS":                    ProtectionDangerous,
+	"android.permission.ACCESS_FINE_LOCATION":            ProtectionDangerous,
+	"android.permission.ACCESS_COARSE_LOCATION":          ProtectionDangerous,
+	"android.permission.ACCESS_BACKGROUND_LOCATION":      ProtectionDangerous,
+	"android.permission.ACCESS_MEDIA_LOCATION":           ProtectionDangerous,
+	"android.permission.RECORD_AUDIO":                    ProtectionDangerous,
+	"android.permission.READ_PHONE_STATE":                ProtectionDangerous,
+	"android.permission.READ_PHONE_NUMBERS":              ProtectionDangerous,
+	"android.permission.CALL_PHONE":                      ProtectionDangerous,
+	"android.permission.ANSWER_PHONE_CALLS":              ProtectionDangerous,
+	"android.permission.ACCEPT_HANDOVER":                 ProtectionDangerous,
+	"android.permission.READ_CALL_LOG":                   ProtectionDangerous,
+	"android.permission.WRITE_CALL_LOG":                  ProtectionDangerous,
+	"android.permission.PROCESS_OUTGOING_CALLS":          ProtectionDangerous,
+	"android.permission.USE_SIP":                         ProtectionDangerous,
+	"com.android.voicemail.permission.ADD_VOICEMAIL":     ProtectionDangerous,
+	"android.permission.BODY_SENSORS":                    ProtectionDangerous,
+	"android.permission.BODY_SENSORS_BACKGROUND":         ProtectionDangerous,
+	"android.permission.ACTIVITY_RECOGNITION":            ProtectionDangerous,
+	"android.permission.SEND_SMS":                        ProtectionDangerous,
+	"android.permission.RECEIVE_SMS":                     ProtectionDangerous,
+	"android.permission.READ_SMS":                        ProtectionDangerous,
+	"android.permission.RECEIVE_WAP_PUSH":                ProtectionDangerous,
+	"android.permission.RECEIVE_MMS":                     ProtectionDangerous,
+	"android.permission.READ_CELL_BROADCASTS":            ProtectionDangerous,
+	"android.permission.READ_EXTERNAL_STORAGE":           ProtectionDangerous,
+	"android.permission.WRITE_EXTERNAL_STORAGE":          ProtectionDangerous,
+	"android.permission.READ_MEDIA_AUDIO":                ProtectionDangerous,
+	"android.permission.READ_MEDIA_IMAGES":               ProtectionDangerous,
+	"android.permission.READ_MEDIA_VIDEO":                ProtectionDangerous,
+	"android.permission.READ_MEDIA_VISUAL_USER_SELECTED": ProtectionDangerous,
+	"android.permission.BLUETOOTH_ADVERTISE":             ProtectionDangerous,
+	"android.permission.BLUETOOTH_CONNECT":               ProtectionDangerous,
+	"android.permission.BLUETOOTH_SCAN":                  ProtectionDangerous,
+	"android.permission.NEARBY_WIFI_DEVICES":             ProtectionDangerous,
+	"android.permission.UWB_RANGING":                     ProtectionDangerous,
+	"android.permission.POST_NOTIFICATIONS":              ProtectionDangerous,
+
+	// normal permissions
+	"android.permission.ACCESS_LOCATION_EXTRA_COMMANDS":            ProtectionNormal,
+	"android.permission.ACCESS_NETWORK_STATE":                      ProtectionNormal,
+	"android.permission.ACCESS_NOTIFICATION_POLICY":                ProtectionNormal,
+	"android.permission.ACCESS_WIFI_STATE":                         ProtectionNormal,
+	"android.permission.BLUETOOTH":                                 ProtectionNormal,
+	"android.permission.BLUETOOTH_ADMIN":                           ProtectionNormal,
+	"android.permission.BROADCAST_STICKY":                          ProtectionNormal,
+	"android.permission.CALL_COMPANION_APP":                        ProtectionNormal,
+	"android.permission.CHANGE_NETWORK_STATE":                      ProtectionNormal,
+	"android.permission.CHANGE_WIFI_MULTICAST_STATE":               ProtectionNormal,
+	"android.permission.CHANGE_WIFI_STATE":                         ProtectionNormal,
+	"android.permission.DELIVER_COMPANION_MESSAGES":                ProtectionNormal,
+	"android.permission.DISABLE_KEYGUARD":                          ProtectionNormal,
+	"android.permission.EXPAND_STATUS_BAR":                         ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE":                        ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_CAMERA":                 ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_CONNECTED_DEVICE":       ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_DATA_SYNC":              ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_HEALTH":                 ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_LOCATION":               ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_MEDIA_PLAYBACK":         ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_MEDIA_PROJECTION":       ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_MICROPHONE":             ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_PHONE_CALL":             ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_REMOTE_MESSAGING":       ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_SPECIAL_USE":            ProtectionNormal,
+	"android.permission.FOREGROUND_SERVICE_SYSTEM_EXEMPTED":        ProtectionNormal,
+	"android.permission.GET_PACKAGE_SIZE":                          ProtectionNormal,
+	"android.permission.GET_TASKS":                                 ProtectionNormal,
+	"android.permission.HIDE_OVERLAY_WINDOWS":                      ProtectionNormal,
+	"android.permission.HIGH_SAMPLING_RATE_SENSORS":                ProtectionNormal,
+	"android.permission.INTERNET":                                  ProtectionNormal,
+	"android.permission.KILL_BACKGROUND_PROCESSES":                 ProtectionNormal,
+	"android.permission.MANAGE_OWN_CALLS":                          ProtectionNormal,
+	"android.permission.MODIFY_AUDIO_SETTINGS":                     ProtectionNormal,
+	"android.permission.NFC":                                       ProtectionNormal,
+	"android.permission.NFC_PREFERRED_PAYMENT_INFO":                ProtectionNormal,
+	"android.permission.NFC_TRANSACTION_EVENT":                     ProtectionNormal,
+	"android.permission.PERSISTENT_ACTIVITY":                       ProtectionNormal,
+	"android.permission.QUERY_ALL_PACKAGES":                        ProtectionNormal,
+	"android.permission.READ_BASIC_PHONE_STATE":                    ProtectionNormal,
+	"android.permission.READ_SYNC_SETTINGS":                        ProtectionNormal,
+	"android.permission.READ_SYNC_STATS":                           ProtectionNormal,
+	"android.permission.RECEIVE_BOOT_COMPLETED":                    ProtectionNormal,
+	"android.permission.REORDER_TASKS":                             ProtectionNormal,
+	"android.permission.REQUEST_COMPANION_PROFILE_WATCH":           ProtectionNormal,
+	"android.permission.REQUEST_COMPANION_RUN_IN_BACKGROUND":       ProtectionNormal,
+	"android.permission.REQUEST_COMPANION_USE_DATA_IN_BACKGROUND":  ProtectionNormal,
+	"android.permission.REQUEST_DELETE_PACKAGES":                   ProtectionNormal,
+	"android.permission.REQUEST_IGNORE_BATTERY_OPTIMIZATIONS":      ProtectionNormal,
+	"android.permission.REQUEST_OBSERVE_COMPANION_DEVICE_PRESENCE": ProtectionNormal,
+	"android.permission.REQUEST_PASSWORD_COMPLEXITY":               ProtectionNormal,
+	"android.permission.RESTART_PACKAGES":                          ProtectionNormal,
+	"android.permission.RUN_USER_INITIATED_JOBS":                   ProtectionNormal,
+	"android.permission.SET_WALLPAPER":                             ProtectionNormal,
+	"android.permission.SET_WALLPAPER_HINTS":                       ProtectionNormal,
+	"android.permission.TRANSMIT_IR":                               ProtectionNormal,
+	"android.permission.UPDATE_PACKAGES_WITHOUT_USER_ACTION":       ProtectionNormal,
+	"android.permission.USE_BIOMETRIC":                             ProtectionNormal,
+	"android.permission.USE_EXACT_ALARM":                           ProtectionNormal,
+	"android.permission.USE_FINGERPRINT":                           ProtectionNormal,
+	"android.permission.USE_FULL_SCREEN_INTENT":                    ProtectionNormal,
+	"android.permission.VIBRATE":                                   ProtectionNormal,
+	"android.permission.WAKE_LOCK":                                 ProtectionNormal,
+	"android.permission.WRITE_SYNC_SETTINGS":                       ProtectionNormal,
+	"com.android.alarm.permission.SET_ALARM":                       ProtectionNormal,
+	"com.android.launcher.permission.INSTALL_SHORTCUT":             ProtectionNormal,
+	"com.android.launcher.permission.UNINSTALL_SHORTCUT":           ProtectionNormal,
+	"android.permission.SCHEDULE_EXACT_ALARM":                      ProtectionNormal | ProtectionFlagAppOp,
+
+	// signature permissions that normal applications may be granted
+	"android.permission.SYSTEM_ALERT_WINDOW":                   ProtectionSignature | ProtectionFlagSetup | ProtectionFlagAppOp | ProtectionFlagInstaller | ProtectionFlagPre23 | ProtectionFlagDevelopment,
+	"android.permission.WRITE_SETTINGS":                        ProtectionSignature | ProtectionFlagPreinstalled | ProtectionFlagAppOp | ProtectionFlagPre23,
+	"android.permission.REQUEST_INSTALL_PACKAGES":              ProtectionSignature | ProtectionFlagAppOp,
+	"android.permission.MANAGE_EXTERNAL_STORAGE":               ProtectionSignature | ProtectionFlagAppOp | ProtectionFlagPreinstalled,
+	"android.permission.MANAGE_MEDIA":                          ProtectionSignature | ProtectionFlagAppOp | ProtectionFlagPreinstalled,
+	"android.permission.MANAGE_ONGOING_CALLS":                  ProtectionSignature | ProtectionFlagAppOp,
+	"android.permission.PACKAGE_USAGE_STATS":                   ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment | ProtectionFlagAppOp | ProtectionFlagRetailDemo,
+	"android.permission.LOADER_USAGE_STATS":                    ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagAppOp,
+	"android.permission.SMS_FINANCIAL_TRANSACTIONS":            ProtectionSignature | ProtectionFlagAppOp,
+	"android.permission.INSTANT_APP_FOREGROUND_SERVICE":        ProtectionSignature | ProtectionFlagDevelopment | ProtectionFlagInstant | ProtectionFlagAppOp,
+	"android.permission.ACCESS_SURFACE_FLINGER":                ProtectionSignature,
+	"android.permission.ACCOUNT_MANAGER":                       ProtectionSignature,
+	"android.permission.BIND_ACCESSIBILITY_SERVICE":            ProtectionSignature,
+	"android.permission.BIND_AUTOFILL_SERVICE":                 ProtectionSignature,
+	"android.permission.BIND_CALL_REDIRECTION_SERVICE":         ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BIND_CARRIER_MESSAGING_CLIENT_SERVICE": ProtectionSignature,
+	"android.permission.BIND_CARRIER_SERVICES":                 ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BIND_CHOOSER_TARGET_SERVICE":           ProtectionSignature,
+	"android.permission.BIND_COMPANION_DEVICE_SERVICE":         ProtectionSignature,
+	"android.permission.BIND_CONDITION_PROVIDER_SERVICE":       ProtectionSignature,
+	"android.permission.BIND_CONTROLS":                         ProtectionSignature,
+	"android.permission.BIND_CREDENTIAL_PROVIDER_SERVICE":      ProtectionSignature,
+	"android.permission.BIND_DEVICE_ADMIN":                     ProtectionSignature,
+	"android.permission.BIND_DREAM_SERVICE":                    ProtectionSignature,
+	"android.permission.BIND_INCALL_SERVICE":                   ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BIND_INPUT_METHOD":                     ProtectionSignature,
+	"android.permission.BIND_JOB_SERVICE":                      ProtectionSignature,
+	"android.permission.BIND_MIDI_DEVICE_SERVICE":              ProtectionSignature,
+	"android.permission.BIND_NFC_SERVICE":                      ProtectionSignature,
+	"android.permission.BIND_NOTIFICATION_LISTENER_SERVICE":    ProtectionSignature,
+	"android.permission.BIND_PRINT_SERVICE":                    ProtectionSignature,
+	"android.permission.BIND_QUICK_ACCESS_WALLET_SERVICE":      ProtectionSignature,
+	"android.permission.BIND_QUICK_SETTINGS_TILE":              ProtectionSignature,
+	"android.permission.BIND_REMOTEVIEWS":                      ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BIND_SCREENING_SERVICE":                ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BIND_TELECOM_CONNECTION_SERVICE":       ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BIND_TEXT_SERVICE":                     ProtectionSignature,
+	"android.permission.BIND_TV_INPUT":                         ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BIND_VISUAL_VOICEMAIL_SERVICE":         ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BIND_VOICE_INTERACTION":                ProtectionSignature,
+	"android.permission.BIND_VPN_SERVICE":                      ProtectionSignature,
+	"android.permission.BIND_VR_LISTENER_SERVICE":              ProtectionSignature,
+	"android.permission.BIND_WALLPAPER":                        ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BROADCAST_PACKAGE_REMOVED":             ProtectionSignature,
+	"android.permission.BROADCAST_SMS":                         ProtectionSignature,
+	"android.permission.BROADCAST_WAP_PUSH":                    ProtectionSignature,
+	"android.permission.FACTORY_TEST":                          ProtectionSignature,
+	"android.permission.INJECT_EVENTS":                         ProtectionSignature,
+	"android.permission.MANAGE_DOCUMENTS":                      ProtectionSignature,
+	"android.permission.SET_ALWAYS_FINISH":                     ProtectionSignature | ProtectionFlagDevelopment,
+	"android.permission.START_VIEW_PERMISSION_USAGE":           ProtectionSignature | ProtectionFlagInstaller,
+
+	// signature permissions for system applications
+	"android.permission.ACCESS_CHECKIN_PROPERTIES":      ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BATTERY_STATS":                  ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.BIND_APPWIDGET":                 ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.BLUETOOTH_PRIVILEGED":           ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.CALL_PRIVILEGED":                ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.CAPTURE_AUDIO_OUTPUT":           ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.CAPTURE_VIDEO_OUTPUT":           ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.CHANGE_COMPONENT_ENABLED_STATE": ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.CHANGE_CONFIGURATION":           ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.CLEAR_APP_CACHE":                ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.CONTROL_LOCATION_UPDATES":       ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.DELETE_CACHE_FILES":             ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.DELETE_PACKAGES":                ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.DIAGNOSTIC":                     ProtectionSignature,
+	"android.permission.DUMP":                           ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.GLOBAL_SEARCH":                  ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.INSTALL_LOCATION_PROVIDER":      ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.INSTALL_PACKAGES":               ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.LOCATION_HARDWARE":              ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.MASTER_CLEAR":                   ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.MEDIA_CONTENT_CONTROL":          ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.MODIFY_PHONE_STATE":             ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.MOUNT_FORMAT_FILESYSTEMS":       ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.MOUNT_UNMOUNT_FILESYSTEMS":      ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.READ_FRAME_BUFFER":              ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.READ_LOGS":                      ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.READ_PRIVILEGED_PHONE_STATE":    ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.READ_VOICEMAIL":                 ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagRole,
+	"android.permission.REBOOT":                         ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.SEND_RESPOND_VIA_MESSAGE":       ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.SET_ANIMATION_SCALE":            ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.SET_DEBUG_APP":                  ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.SET_PROCESS_LIMIT":              ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.SET_TIME":                       ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.SET_TIME_ZONE":                  ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.SIGNAL_PERSISTENT_PROCESSES":    ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.STATUS_BAR":                     ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.UPDATE_DEVICE_STATS":            ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.WRITE_APN_SETTINGS":             ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.WRITE_GSERVICES":                ProtectionSignature | ProtectionFlagPrivileged,
+	"android.permission.WRITE_SECURE_SETTINGS":          ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagDevelopment,
+	"android.permission.WRITE_VOICEMAIL":                ProtectionSignature | ProtectionFlagPrivileged | ProtectionFlagRole,
+}
@@ -0,0 +1,106 @@
+package apk
+
+import (
+	"testing"
+)
+
+const permissionTestManifest = `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.permission">
+	<uses-permission android:name="android.permission.INTERNET"/>
+	<uses-permission android:name="android.permission.CAMERA"/>
+	<uses-permission android:name="android.permission.WRITE_EXTERNAL_STORAGE" android:maxSdkVersion="28"/>
+	<uses-permission android:name="com.example.permission.ACCESS_DATA"/>
+	<uses-permission android:name="com.google.android.c2dm.permission.RECEIVE"/>
+	<uses-permission-sdk-23 android:name="android.permission.ACCESS_FINE_LOCATION"/>
+	<permission android:name="com.example.permission.ACCESS_DATA" android:protectionLevel="0x00000002"/>
+	<permission android:name="com.example.permission.DEBUG" android:protectionLevel="signature|development"/>
+</manifest>`
+
+func TestPermissions(t *testing.T) {
+	apk := loadTestManifest(t, permissionTestManifest)
+	report, err := apk.Permissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Requested) != 6 {
+		t.Fatalf("want 6 requested permissions, got %d", len(report.Requested))
+	}
+	requested := make(map[string]RequestedPermission)
+	for _, p := range report.Requested {
+		requested[p.Name] = p
+	}
+
+	if p := requested["android.permission.WRITE_EXTERNAL_STORAGE"]; p.MaxSDKVersion != 28 {
+		t.Errorf("want maxSdkVersion 28, got %d", p.MaxSDKVersion)
+	}
+	if p := requested["android.permission.ACCESS_FINE_LOCATION"]; !p.SDK23 {
+		t.Error("want ACCESS_FINE_LOCATION to be requested by uses-permission-sdk-23")
+	}
+	if p := requested["com.example.permission.ACCESS_DATA"]; !p.Known || !p.Custom || p.ProtectionLevel != ProtectionSignature {
+		t.Errorf("unexpected custom permission: %+v", p)
+	}
+
+	dangerous := report.Dangerous()
+	if len(dangerous) != 3 {
+		t.Errorf("want 3 dangerous permissions, got %v", dangerous)
+	}
+	unknown := report.Unknown()
+	if len(unknown) != 1 || unknown[0].Name != "com.google.android.c2dm.permission.RECEIVE" {
+		t.Errorf("unexpected unknown permissions: %v", unknown)
+	}
+
+	if len(report.Declared) != 2 {
+		t.Fatalf("want 2 declared permissions, got %d", len(report.Declared))
+	}
+	if level := report.Declared[1].ProtectionLevel; level != ProtectionSignature|ProtectionFlagDevelopment {
+		t.Errorf("want signature|development, got %s", level)
+	}
+
+	previous := &PermissionReport{
+		Requested: []RequestedPermission{
+			{Name: "android.permission.INTERNET"},
+			{Name: "android.permission.ACCESS_FINE_LOCATION"},
+		},
+	}
+	var added []string
+	for _, p := range report.Added(previous) {
+		if p.ProtectionLevel.Base() == ProtectionDangerous {
+			added = append(added, p.Name)
+		}
+	}
+	if len(added) != 2 || added[0] != "android.permission.CAMERA" || added[1] != "android.permission.WRITE_EXTERNAL_STORAGE" {
+		t.Errorf("unexpected newly added dangerous permissions: %v", added)
+	}
+}
+
+func TestProtectionLevel(t *testing.T) {
+	cases := []struct {
+		input string
+		want  ProtectionLevel
+		str   string
+	}{
+		{"", ProtectionNormal, "normal"},
+		{"dangerous", ProtectionDangerous, "dangerous"},
+		{"0x00000012", ProtectionSignature | ProtectionFlagPrivileged, "signature|privileged"},
+		{"signatureOrSystem", ProtectionSignatureOrSystem, "signatureOrSystem"},
+		{"signature|system", ProtectionSignature | ProtectionFlagPrivileged, "signature|privileged"},
+		{"signature|appop|pre23", ProtectionSignature | ProtectionFlagAppOp | ProtectionFlagPre23, "signature|appop|pre23"},
+	}
+	for _, c := range cases {
+		got, err := ParseProtectionLevel(c.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.input, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%q: want %#x, got %#x", c.input, c.want, got)
+		}
+		if got.String() != c.str {
+			t.Errorf("%q: want %q, got %q", c.input, c.str, got.String())
+		}
+	}
+
+	if _, err := ParseProtectionLevel("signature|unknown"); err == nil {
+		t.Error("want error, got nil")
+	}
+}
@@ -0,0 +1,199 @@
+package apk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha1" // register SHA-1
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The object identifiers used in PKCS #7 signatures of JAR signing.
+var (
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+)
+
+// pkcs7Hashes is the digest algorithms supported in PKCS #7 signatures.
+var pkcs7Hashes = []struct {
+	oid  asn1.ObjectIdentifier
+	hash crypto.Hash
+}{
+	{oidSHA1, crypto.SHA1},
+	{oidSHA256, crypto.SHA256},
+	{oidSHA384, crypto.SHA384},
+	{oidSHA512, crypto.SHA512},
+}
+
+func hashFromOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	for _, h := range pkcs7Hashes {
+		if h.oid.Equal(oid) {
+			return h.hash, nil
+		}
+	}
+	return 0, fmt.Errorf("apk: unsupported digest algorithm: %v", oid)
+}
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      pkcs7ContentInfo
+	Certificates     asn1.RawValue     `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue     `asn1:"optional,tag:1"`
+	SignerInfos      []pkcs7SignerInfo `asn1:"set"`
+}
+
+type pkcs7SignerInfo struct {
+	Version                   int
+	SignerIdentifier          asn1.RawValue
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+	UnauthenticatedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type pkcs7IssuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type pkcs7Attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// pkcs7Signature is a detached PKCS #7 signature.
+type pkcs7Signature struct {
+	signedData   pkcs7SignedData
+	certificates []*x509.Certificate
+}
+
+// parsePKCS7 parses a DER encoded PKCS #7 SignedData.
+func parsePKCS7(der []byte) (*pkcs7Signature, error) {
+	var info pkcs7ContentInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("apk: trailing data after PKCS #7 signature")
+	}
+	if !info.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("apk: unsupported PKCS #7 content type: %v", info.ContentType)
+	}
+
+	sig := new(pkcs7Signature)
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &sig.signedData); err != nil {
+		return nil, err
+	}
+	if len(sig.signedData.Certificates.Bytes) > 0 {
+		sig.certificates, err = x509.ParseCertificates(sig.signedData.Certificates.Bytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sig, nil
+}
+
+// signerCertificate finds the certificate of the signer.
+func (sig *pkcs7Signature) signerCertificate(si *pkcs7SignerInfo) (*x509.Certificate, error) {
+	id := si.SignerIdentifier
+	switch {
+	case id.Class == asn1.ClassUniversal && id.Tag == asn1.TagSequence:
+		var ias pkcs7IssuerAndSerial
+		if _, err := asn1.Unmarshal(id.FullBytes, &ias); err != nil {
+			return nil, err
+		}
+		for _, cert := range sig.certificates {
+			if cert.SerialNumber.Cmp(ias.SerialNumber) == 0 && bytes.Equal(cert.RawIssuer, ias.Issuer.FullBytes) {
+				return cert, nil
+			}
+		}
+	case id.Class == asn1.ClassContextSpecific && id.Tag == 0:
+		for _, cert := range sig.certificates {
+			if bytes.Equal(cert.SubjectKeyId, id.Bytes) {
+				return cert, nil
+			}
+		}
+	}
+	return nil, errors.New("apk: signer certificate not found")
+}
+
+// verify verifies the signer of the detached content and returns the signing certificate.
+func (sig *pkcs7Signature) verify(si *pkcs7SignerInfo, content []byte) (*x509.Certificate, error) {
+	cert, err := sig.signerCertificate(si)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hashFromOID(si.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write(content)
+	digest := h.Sum(nil)
+
+	signed := content
+	if len(si.AuthenticatedAttributes.FullBytes) > 0 {
+		// the signature is over the DER encoding of the attributes as SET OF, instead of [0] IMPLICIT.
+		signed = append([]byte(nil), si.AuthenticatedAttributes.FullBytes...)
+		signed[0] = 0x31
+		var attrs []pkcs7Attribute
+		if _, err := asn1.UnmarshalWithParams(signed, &attrs, "set"); err != nil {
+			return nil, err
+		}
+		var messageDigest []byte
+		for _, attr := range attrs {
+			if !attr.Type.Equal(oidMessageDigest) {
+				continue
+			}
+			if _, err := asn1.Unmarshal(attr.Values.Bytes, &messageDigest); err != nil {
+				return nil, err
+			}
+		}
+		if messageDigest == nil {
+			return nil, errors.New("apk: messageDigest attribute not found")
+		}
+		if !bytes.Equal(messageDigest, digest) {
+			return nil, errors.New("apk: messageDigest attribute doesn't match the content")
+		}
+		h.Reset()
+		h.Write(signed)
+		digest = h.Sum(nil)
+	}
+
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(pub, hash, digest, si.EncryptedDigest)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest, si.EncryptedDigest) {
+			err = errors.New("apk: ECDSA verification failure")
+		}
+	case *dsa.PublicKey:
+		err = verifyDSA(pub, digest, si.EncryptedDigest)
+	default:
+		err = fmt.Errorf("apk: unsupported public key: %T", pub)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
@@ -0,0 +1,119 @@
+package apk
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// XMLFiles returns the names of the compiled XML files in res/, such as "res/layout/activity_main.xml", in ascending order.
+// The files in res/raw/ are not included, because they are not compiled.
+func (k *Apk) XMLFiles() []string {
+	var names []string
+	for _, file := range k.zipreader.File {
+		name := file.Name
+		if !strings.HasPrefix(name, "res/") || strings.HasPrefix(name, "res/raw/") || strings.HasPrefix(name, "res/raw-") {
+			continue
+		}
+		if !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenXMLFile parses the compiled XML file in the APK, such as "res/xml/file_paths.xml".
+func (k *Apk) OpenXMLFile(name string) (*androidbinary.XMLFile, error) {
+	data, err := k.readZipFile(name)
+	if err != nil {
+		return nil, err
+	}
+	xmlfile, err := androidbinary.NewXMLFileWithOptions(bytes.NewReader(data), k.opts.ParseOptions)
+	if err != nil {
+		return nil, errorf("failed to parse %s: %w", name, err)
+	}
+	return xmlfile, nil
+}
+
+// DecodeXMLFile decodes the compiled XML file in the APK into v,
+// resolving the references with the resources of the APK.
+func (k *Apk) DecodeXMLFile(name string, v interface{}, resConfig *androidbinary.ResTableConfig) error {
+	if err := k.Parse(); err != nil {
+		return err
+	}
+	xmlfile, err := k.OpenXMLFile(name)
+	if err != nil {
+		return err
+	}
+	return xmlfile.Decode(v, k.table, resConfig)
+}
+
+// DecodeXMLResource decodes the XML resource referenced by id, such as @xml/file_paths, into v.
+// The file of the resource is chosen by resConfig.
+func (k *Apk) DecodeXMLResource(id androidbinary.ResID, v interface{}, resConfig *androidbinary.ResTableConfig) error {
+	name, err := k.xmlResourceFile(id, resConfig)
+	if err != nil {
+		return err
+	}
+	return k.DecodeXMLFile(name, v, resConfig)
+}
+
+// xmlResourceFile returns the name of the file of the XML resource referenced by id.
+func (k *Apk) xmlResourceFile(id androidbinary.ResID, resConfig *androidbinary.ResTableConfig) (string, error) {
+	if err := k.Parse(); err != nil {
+		return "", err
+	}
+	res, err := k.table.GetResource(id, resConfig)
+	if err != nil {
+		return "", err
+	}
+	name, ok := res.(string)
+	if !ok {
+		return "", errorf("apk: resource %v is not a file", id)
+	}
+	return name, nil
+}
+
+// decodeDeclaredXML decodes the XML resource referenced by the attribute of the manifest into v.
+// It returns ErrNotDeclared if the attribute is not set.
+func (k *Apk) decodeDeclaredXML(attr *androidbinary.String, v interface{}) error {
+	if attr == nil {
+		return ErrNotDeclared
+	}
+	id, ok := attr.ResID()
+	if !ok {
+		return ErrNotDeclared
+	}
+	return k.DecodeXMLResource(id, v, nil)
+}
+
+// BackupRules returns the rules of Auto Backup referenced by the fullBackupContent attribute of the application.
+// It returns ErrNotDeclared if the application doesn't set them, or disables Auto Backup with "false".
+func (k *Apk) BackupRules() (*BackupRules, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	var rules BackupRules
+	if err := k.decodeDeclaredXML(k.manifest.App.FullBackupContent, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// DataExtractionRules returns the rules referenced by the dataExtractionRules attribute of the application,
+// which replace the backup rules on Android 12 and later.
+// It returns ErrNotDeclared if the application doesn't set them.
+func (k *Apk) DataExtractionRules() (*DataExtractionRules, error) {
+	if err := k.Parse(); err != nil {
+		return nil, err
+	}
+	var rules DataExtractionRules
+	if err := k.decodeDeclaredXML(k.manifest.App.DataExtractionRules, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
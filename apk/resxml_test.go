@@ -0,0 +1,224 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+func TestXMLFiles(t *testing.T) {
+	apk, err := OpenFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer apk.Close()
+
+	names := apk.XMLFiles()
+	found := false
+	for _, name := range names {
+		if name == "AndroidManifest.xml" {
+			t.Error("AndroidManifest.xml is not a resource")
+		}
+		if name == "res/layout/abc_action_bar_title_item.xml" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("layout not found in %v", names)
+	}
+
+	var layout struct {
+		XMLName     xml.Name
+		Orientation androidbinary.String `xml:"http://schemas.android.com/apk/res/android orientation,attr"`
+	}
+	if err := apk.DecodeXMLResource(0x7F040000, &layout, nil); err != nil {
+		t.Fatal(err)
+	}
+	if layout.XMLName.Local != "LinearLayout" || layout.Orientation.MustString() != "1" {
+		t.Errorf("unexpected layout: %+v", layout)
+	}
+
+	if _, err := apk.OpenXMLFile("res/xml/not_found.xml"); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("want ErrFileNotFound, got %v", err)
+	}
+	if _, err := apk.BackupRules(); !errors.Is(err, ErrNotDeclared) {
+		t.Errorf("want ErrNotDeclared, got %v", err)
+	}
+}
+
+// newTestXMLResourcesAPK returns an APK with the resources of helloworld.apk and the XML files.
+// The first layouts of helloworld.apk, @0x7F040000 and @0x7F040001, are replaced with the backup rules and the data extraction rules.
+// readTestResources returns resources.arsc of testdata/helloworld.apk.
+func readTestResources(t *testing.T) []byte {
+	t.Helper()
+	r, err := zip.OpenReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var table []byte
+	for _, file := range r.File {
+		if file.Name != "resources.arsc" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		table, err = ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return table
+}
+
+func newTestXMLResourcesAPK(t *testing.T, files map[string]string) *Apk {
+	t.Helper()
+	contents := map[string][]byte{"resources.arsc": readTestResources(t)}
+	for name, doc := range files {
+		contents[name] = encodeTestXML(t, doc)
+	}
+	data := newTestZip(t, contents, zip.Deflate)
+	apk, err := OpenZipReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return apk
+}
+
+func TestDecodeXMLFile(t *testing.T) {
+	apk := newTestXMLResourcesAPK(t, map[string]string{
+		"AndroidManifest.xml": `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.helloworld">
+	<application android:fullBackupContent="@0x7F040000" android:dataExtractionRules="@0x7F040001"></application>
+</manifest>`,
+		"res/layout/abc_action_bar_title_item.xml": `<full-backup-content>
+	<include domain="sharedpref" path="." requireFlags="clientSideEncryption"/>
+	<exclude domain="sharedpref" path="device.xml"/>
+</full-backup-content>`,
+		"res/layout/abc_action_bar_up_container.xml": `<data-extraction-rules>
+	<cloud-backup disableIfNoEncryptionCapabilities="true">
+		<include domain="file" path="."/>
+		<exclude domain="file" path="cache"/>
+	</cloud-backup>
+	<device-transfer>
+		<include domain="root" path="."/>
+	</device-transfer>
+</data-extraction-rules>`,
+		"res/xml/file_paths.xml": `<paths>
+	<files-path name="images" path="images/"/>
+	<external-cache-path name="cache" path="."/>
+</paths>`,
+		"res/xml/shortcuts.xml": `<shortcuts xmlns:android="http://schemas.android.com/apk/res/android">
+	<shortcut android:shortcutId="compose" android:enabled="true" android:shortcutShortLabel="Compose">
+		<intent android:action="android.intent.action.VIEW" android:targetPackage="com.example.helloworld" android:targetClass="com.example.helloworld.ComposeActivity"/>
+		<categories android:name="android.shortcut.conversation"/>
+	</shortcut>
+</shortcuts>`,
+		"res/xml/network_security_config.xml": `<network-security-config>
+	<base-config cleartextTrafficPermitted="false"/>
+	<domain-config>
+		<domain includeSubdomains="true">example.com</domain>
+		<pin-set expiration="2030-01-01">
+			<pin digest="SHA-256">7HIpactkIAq2Y49orFOOQKurWxmmSFZhBCoQYcRhJ3Y=</pin>
+		</pin-set>
+	</domain-config>
+</network-security-config>`,
+	})
+
+	t.Run("backup rules", func(t *testing.T) {
+		rules, err := apk.BackupRules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rules.Includes) != 1 || len(rules.Excludes) != 1 {
+			t.Fatalf("unexpected rules: %+v", rules)
+		}
+		if rules.Includes[0].RequireFlags.MustString() != "clientSideEncryption" || rules.Excludes[0].Path.MustString() != "device.xml" {
+			t.Errorf("unexpected rules: %+v", rules)
+		}
+	})
+
+	t.Run("data extraction rules", func(t *testing.T) {
+		rules, err := apk.DataExtractionRules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rules.CloudBackup == nil || !rules.CloudBackup.DisableIfNoEncryptionCapabilities.MustBool() || len(rules.CloudBackup.Excludes) != 1 {
+			t.Errorf("unexpected cloud backup: %+v", rules.CloudBackup)
+		}
+		if rules.DeviceTransfer == nil || rules.DeviceTransfer.Includes[0].Domain.MustString() != "root" {
+			t.Errorf("unexpected device transfer: %+v", rules.DeviceTransfer)
+		}
+	})
+
+	t.Run("file paths", func(t *testing.T) {
+		var paths FilePaths
+		if err := apk.DecodeXMLFile("res/xml/file_paths.xml", &paths, nil); err != nil {
+			t.Fatal(err)
+		}
+		if len(paths.Paths) != 2 {
+			t.Fatalf("unexpected paths: %+v", paths)
+		}
+		if p := paths.Paths[0]; p.Type != "files-path" || p.Name.MustString() != "images" || p.Path.MustString() != "images/" {
+			t.Errorf("unexpected path: %+v", p)
+		}
+		if p := paths.Paths[1]; p.Type != "external-cache-path" {
+			t.Errorf("unexpected path: %+v", p)
+		}
+	})
+
+	t.Run("shortcuts", func(t *testing.T) {
+		var shortcuts Shortcuts
+		if err := apk.DecodeXMLFile("res/xml/shortcuts.xml", &shortcuts, nil); err != nil {
+			t.Fatal(err)
+		}
+		if len(shortcuts.Shortcuts) != 1 {
+			t.Fatalf("unexpected shortcuts: %+v", shortcuts)
+		}
+		s := shortcuts.Shortcuts[0]
+		if s.ShortcutID.MustString() != "compose" || s.Enabled == nil || !s.Enabled.MustBool() {
+			t.Errorf("unexpected shortcut: %+v", s)
+		}
+		if len(s.Intents) != 1 || s.Intents[0].TargetClass.MustString() != "com.example.helloworld.ComposeActivity" {
+			t.Errorf("unexpected intents: %+v", s.Intents)
+		}
+		if len(s.Categories) != 1 || s.Categories[0].Name.MustString() != "android.shortcut.conversation" {
+			t.Errorf("unexpected categories: %+v", s.Categories)
+		}
+	})
+
+	t.Run("network security config", func(t *testing.T) {
+		var config NetworkSecurityConfig
+		if err := apk.DecodeXMLFile("res/xml/network_security_config.xml", &config, nil); err != nil {
+			t.Fatal(err)
+		}
+		if config.BaseConfig == nil || config.BaseConfig.CleartextTrafficPermitted == nil || config.BaseConfig.CleartextTrafficPermitted.MustBool() {
+			t.Errorf("unexpected base config: %+v", config.BaseConfig)
+		}
+		if len(config.DomainConfigs) != 1 {
+			t.Fatalf("unexpected domain configs: %+v", config.DomainConfigs)
+		}
+		d := config.DomainConfigs[0]
+		if d.CleartextTrafficPermitted != nil {
+			t.Errorf("want nil, got %+v", d.CleartextTrafficPermitted)
+		}
+		if len(d.Domains) != 1 || d.Domains[0].Name != "example.com" || !d.Domains[0].IncludeSubdomains.MustBool() {
+			t.Errorf("unexpected domains: %+v", d.Domains)
+		}
+		if d.PinSet == nil || len(d.PinSet.Pins) != 1 || d.PinSet.Pins[0].Value != "7HIpactkIAq2Y49orFOOQKurWxmmSFZhBCoQYcRhJ3Y=" {
+			t.Errorf("unexpected pin set: %+v", d.PinSet)
+		}
+	})
+
+	names := apk.XMLFiles()
+	if len(names) != 5 {
+		t.Errorf("want 5 files, got %v", names)
+	}
+}
@@ -0,0 +1,254 @@
+package apk
+
+import (
+	"encoding/xml"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// NetworkSecurityConfig is the network security configuration in res/xml,
+// which is referenced by the networkSecurityConfig attribute of the application.
+// https://developer.android.com/privacy-and-security/security-config
+type NetworkSecurityConfig struct {
+	BaseConfig     *NetworkBaseConfig     `xml:"base-config"`
+	DomainConfigs  []NetworkDomainConfig  `xml:"domain-config"`
+	DebugOverrides *NetworkDebugOverrides `xml:"debug-overrides"`
+}
+
+// NetworkBaseConfig is the default configuration for the connections to the domains not covered by the domain configurations.
+type NetworkBaseConfig struct {
+	CleartextTrafficPermitted *androidbinary.Bool `xml:"cleartextTrafficPermitted,attr,omitempty"`
+	TrustAnchors              *TrustAnchors       `xml:"trust-anchors"`
+}
+
+// NetworkDomainConfig is the configuration for the specific domains.
+// The nested domain configurations inherit the settings that they don't set.
+type NetworkDomainConfig struct {
+	CleartextTrafficPermitted *androidbinary.Bool   `xml:"cleartextTrafficPermitted,attr,omitempty"`
+	Domains                   []NetworkDomain       `xml:"domain"`
+	TrustAnchors              *TrustAnchors         `xml:"trust-anchors"`
+	PinSet                    *PinSet               `xml:"pin-set"`
+	DomainConfigs             []NetworkDomainConfig `xml:"domain-config"`
+}
+
+// NetworkDomain is a domain of a domain configuration.
+type NetworkDomain struct {
+	IncludeSubdomains androidbinary.Bool `xml:"includeSubdomains,attr"`
+	Name              string             `xml:",chardata"`
+}
+
+// NetworkDebugOverrides is the overrides applied when the application is debuggable.
+type NetworkDebugOverrides struct {
+	TrustAnchors *TrustAnchors `xml:"trust-anchors"`
+}
+
+// TrustAnchors is the set of the trusted certificate authorities.
+type TrustAnchors struct {
+	Certificates []TrustCertificates `xml:"certificates"`
+}
+
+// TrustCertificates is a source of the trusted certificates.
+type TrustCertificates struct {
+	// Src is "system", "user" or a reference to a raw resource of the certificates.
+	Src          androidbinary.String `xml:"src,attr"`
+	OverridePins androidbinary.Bool   `xml:"overridePins,attr"`
+}
+
+// PinSet is the set of the public key pins.
+type PinSet struct {
+	// Expiration is the date in yyyy-MM-dd format, after which the pins are not enforced.
+	Expiration androidbinary.String `xml:"expiration,attr"`
+	Pins       []Pin                `xml:"pin"`
+}
+
+// Pin is a public key pin.
+type Pin struct {
+	// Digest is the digest algorithm, which is "SHA-256".
+	Digest androidbinary.String `xml:"digest,attr"`
+
+	// Value is the base64 encoded digest of the SubjectPublicKeyInfo.
+	Value string `xml:",chardata"`
+}
+
+// BackupRules is the rules of Auto Backup, which is referenced by the fullBackupContent attribute of the application.
+// https://developer.android.com/identity/data/autobackup
+type BackupRules struct {
+	Includes []BackupRule `xml:"include"`
+	Excludes []BackupRule `xml:"exclude"`
+}
+
+// BackupRule is a rule of the files to include or exclude.
+type BackupRule struct {
+	// Domain is "root", "file", "database", "sharedpref", "external" or their device_ variants.
+	Domain androidbinary.String `xml:"domain,attr"`
+	Path   androidbinary.String `xml:"path,attr"`
+
+	// RequireFlags is the conditions of the backup, such as "clientSideEncryption" and "deviceToDeviceTransfer".
+	RequireFlags androidbinary.String `xml:"requireFlags,attr"`
+}
+
+// DataExtractionRules is the rules of the cloud backup and the device transfer on Android 12 and later,
+// which is referenced by the dataExtractionRules attribute of the application.
+// https://developer.android.com/about/versions/12/backup-restore
+type DataExtractionRules struct {
+	CloudBackup            *DataExtractionRuleSet  `xml:"cloud-backup"`
+	DeviceTransfer         *DataExtractionRuleSet  `xml:"device-transfer"`
+	CrossPlatformTransfers []DataExtractionRuleSet `xml:"cross-platform-transfer"`
+}
+
+// DataExtractionRuleSet is the rules of a kind of the data extraction.
+type DataExtractionRuleSet struct {
+	// DisableIfNoEncryptionCapabilities is used only for the cloud backup.
+	DisableIfNoEncryptionCapabilities androidbinary.Bool `xml:"disableIfNoEncryptionCapabilities,attr"`
+
+	// Platform is used only for the cross platform transfer, such as "ios".
+	Platform androidbinary.String `xml:"platform,attr"`
+
+	Includes []BackupRule `xml:"include"`
+	Excludes []BackupRule `xml:"exclude"`
+}
+
+// FilePaths is the paths shared by FileProvider, which is referenced by
+// the android.support.FILE_PROVIDER_PATHS meta-data of the provider.
+// https://developer.android.com/reference/androidx/core/content/FileProvider
+type FilePaths struct {
+	Paths []FilePath `xml:",any"`
+}
+
+// FilePath is a shared path.
+type FilePath struct {
+	// Type is the name of the element, such as "files-path", "cache-path" and "external-path".
+	Type string `xml:"-"`
+
+	Name androidbinary.String `xml:"name,attr"`
+	Path androidbinary.String `xml:"path,attr"`
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (p *FilePath) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type filePath FilePath
+	var v filePath
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*p = FilePath(v)
+	p.Type = start.Name.Local
+	return nil
+}
+
+// Shortcuts is the static shortcuts of an activity, which is referenced by the android.app.shortcuts meta-data.
+// https://developer.android.com/develop/ui/views/launch/shortcuts/creating-shortcuts
+type Shortcuts struct {
+	Shortcuts    []Shortcut           `xml:"shortcut"`
+	Capabilities []ShortcutCapability `xml:"capability"`
+}
+
+// Shortcut is a static shortcut.
+type Shortcut struct {
+	ShortcutID              androidbinary.String `xml:"http://schemas.android.com/apk/res/android shortcutId,attr"`
+	Enabled                 *androidbinary.Bool  `xml:"http://schemas.android.com/apk/res/android enabled,attr,omitempty"`
+	Icon                    androidbinary.String `xml:"http://schemas.android.com/apk/res/android icon,attr"`
+	ShortcutShortLabel      androidbinary.String `xml:"http://schemas.android.com/apk/res/android shortcutShortLabel,attr"`
+	ShortcutLongLabel       androidbinary.String `xml:"http://schemas.android.com/apk/res/android shortcutLongLabel,attr"`
+	ShortcutDisabledMessage androidbinary.String `xml:"http://schemas.android.com/apk/res/android shortcutDisabledMessage,attr"`
+	Intents                 []ShortcutIntent     `xml:"intent"`
+	Categories              []ShortcutCategory   `xml:"categories"`
+	CapabilityBindings      []CapabilityBinding  `xml:"capability-binding"`
+}
+
+// ShortcutIntent is an intent launched by a shortcut.
+// If a shortcut has multiple intents, the last one is launched on the back stack of the others.
+type ShortcutIntent struct {
+	Action        androidbinary.String `xml:"http://schemas.android.com/apk/res/android action,attr"`
+	TargetPackage androidbinary.String `xml:"http://schemas.android.com/apk/res/android targetPackage,attr"`
+	TargetClass   androidbinary.String `xml:"http://schemas.android.com/apk/res/android targetClass,attr"`
+	Data          androidbinary.String `xml:"http://schemas.android.com/apk/res/android data,attr"`
+	Extras        []ShortcutExtra      `xml:"extra"`
+
+	// Parameters is used only for the intents of the capabilities.
+	Parameters []CapabilityParameter `xml:"parameter"`
+}
+
+// ShortcutExtra is an extra of a shortcut intent.
+type ShortcutExtra struct {
+	Name  androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	Value androidbinary.String `xml:"http://schemas.android.com/apk/res/android value,attr"`
+}
+
+// ShortcutCategory is a category of a shortcut, such as "android.shortcut.conversation".
+type ShortcutCategory struct {
+	Name androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
+}
+
+// CapabilityBinding binds a shortcut to an App Actions capability.
+type CapabilityBinding struct {
+	Key        androidbinary.String         `xml:"http://schemas.android.com/apk/res/android key,attr"`
+	Parameters []CapabilityBindingParameter `xml:"parameter-binding"`
+}
+
+// CapabilityBindingParameter binds a parameter of a capability to a shortcut.
+type CapabilityBindingParameter struct {
+	Key   androidbinary.String `xml:"http://schemas.android.com/apk/res/android key,attr"`
+	Value androidbinary.String `xml:"http://schemas.android.com/apk/res/android value,attr"`
+}
+
+// ShortcutCapability is an App Actions capability, such as "actions.intent.OPEN_APP_FEATURE".
+type ShortcutCapability struct {
+	Name    androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	Intents []ShortcutIntent     `xml:"intent"`
+}
+
+// CapabilityParameter maps a parameter of a capability to an extra of the intent.
+type CapabilityParameter struct {
+	Name     androidbinary.String `xml:"http://schemas.android.com/apk/res/android name,attr"`
+	Key      androidbinary.String `xml:"http://schemas.android.com/apk/res/android key,attr"`
+	Required androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android required,attr"`
+}
+
+// AppWidgetProviderInfo is the metadata of an app widget,
+// which is referenced by the android.appwidget.provider meta-data of the receiver.
+// The dimensions are formatted such as "40.0dip", and the flags are formatted in hexadecimal such as "0x00000003".
+// https://developer.android.com/develop/ui/views/appwidgets
+type AppWidgetProviderInfo struct {
+	MinWidth              androidbinary.String `xml:"http://schemas.android.com/apk/res/android minWidth,attr"`
+	MinHeight             androidbinary.String `xml:"http://schemas.android.com/apk/res/android minHeight,attr"`
+	MinResizeWidth        androidbinary.String `xml:"http://schemas.android.com/apk/res/android minResizeWidth,attr"`
+	MinResizeHeight       androidbinary.String `xml:"http://schemas.android.com/apk/res/android minResizeHeight,attr"`
+	MaxResizeWidth        androidbinary.String `xml:"http://schemas.android.com/apk/res/android maxResizeWidth,attr"`
+	MaxResizeHeight       androidbinary.String `xml:"http://schemas.android.com/apk/res/android maxResizeHeight,attr"`
+	TargetCellWidth       androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android targetCellWidth,attr"`
+	TargetCellHeight      androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android targetCellHeight,attr"`
+	UpdatePeriodMillis    androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android updatePeriodMillis,attr"`
+	InitialLayout         androidbinary.String `xml:"http://schemas.android.com/apk/res/android initialLayout,attr"`
+	InitialKeyguardLayout androidbinary.String `xml:"http://schemas.android.com/apk/res/android initialKeyguardLayout,attr"`
+	PreviewImage          androidbinary.String `xml:"http://schemas.android.com/apk/res/android previewImage,attr"`
+	PreviewLayout         androidbinary.String `xml:"http://schemas.android.com/apk/res/android previewLayout,attr"`
+	Configure             androidbinary.String `xml:"http://schemas.android.com/apk/res/android configure,attr"`
+	Description           androidbinary.String `xml:"http://schemas.android.com/apk/res/android description,attr"`
+	ResizeMode            androidbinary.String `xml:"http://schemas.android.com/apk/res/android resizeMode,attr"`
+	WidgetCategory        androidbinary.String `xml:"http://schemas.android.com/apk/res/android widgetCategory,attr"`
+	WidgetFeatures        androidbinary.String `xml:"http://schemas.android.com/apk/res/android widgetFeatures,attr"`
+}
+
+// AccessibilityServiceInfo is the configuration of an accessibility service,
+// which is referenced by the android.accessibilityservice meta-data of the service.
+// The flags are formatted in hexadecimal such as "0x00000003".
+// https://developer.android.com/reference/android/accessibilityservice/AccessibilityServiceInfo
+type AccessibilityServiceInfo struct {
+	AccessibilityEventTypes        androidbinary.String `xml:"http://schemas.android.com/apk/res/android accessibilityEventTypes,attr"`
+	AccessibilityFeedbackType      androidbinary.String `xml:"http://schemas.android.com/apk/res/android accessibilityFeedbackType,attr"`
+	AccessibilityFlags             androidbinary.String `xml:"http://schemas.android.com/apk/res/android accessibilityFlags,attr"`
+	PackageNames                   androidbinary.String `xml:"http://schemas.android.com/apk/res/android packageNames,attr"`
+	NotificationTimeout            androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android notificationTimeout,attr"`
+	NonInteractiveUITimeout        androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android nonInteractiveUiTimeout,attr"`
+	InteractiveUITimeout           androidbinary.Int32  `xml:"http://schemas.android.com/apk/res/android interactiveUiTimeout,attr"`
+	CanRetrieveWindowContent       androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android canRetrieveWindowContent,attr"`
+	CanRequestTouchExplorationMode androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android canRequestTouchExplorationMode,attr"`
+	CanRequestFilterKeyEvents      androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android canRequestFilterKeyEvents,attr"`
+	CanPerformGestures             androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android canPerformGestures,attr"`
+	CanTakeScreenshot              androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android canTakeScreenshot,attr"`
+	IsAccessibilityTool            androidbinary.Bool   `xml:"http://schemas.android.com/apk/res/android isAccessibilityTool,attr"`
+	Description                    androidbinary.String `xml:"http://schemas.android.com/apk/res/android description,attr"`
+	Summary                        androidbinary.String `xml:"http://schemas.android.com/apk/res/android summary,attr"`
+	SettingsActivity               androidbinary.String `xml:"http://schemas.android.com/apk/res/android settingsActivity,attr"`
+}
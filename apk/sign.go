@@ -0,0 +1,527 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The object identifiers used in PKCS #7 signatures generated by Sign.
+var (
+	oidData            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECDSAWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+// v3MinSDKVersion is the API level that introduced the APK Signature Scheme v3.
+const v3MinSDKVersion = 28
+
+// SignOptions is the options of Sign.
+type SignOptions struct {
+	// Key is the private key. RSA and ECDSA keys are supported.
+	Key crypto.Signer
+
+	// Certificates is the certificate chain. The first certificate is the signing certificate.
+	Certificates []*x509.Certificate
+
+	// Schemes is the signature schemes to sign with.
+	// The default is SignatureSchemeV1, SignatureSchemeV2 and SignatureSchemeV3.
+	Schemes []SignatureScheme
+
+	// SignerName is the name of the JAR signer, e.g. "CERT" for META-INF/CERT.SF. The default is "CERT".
+	SignerName string
+
+	// MinSDKVersion is the minimum API level of the v3 signer. The default is 28.
+	MinSDKVersion int32
+
+	// Alignment is the alignment of uncompressed entries. The default is DefaultAlignment.
+	Alignment int64
+
+	// LibraryAlignment is the alignment of uncompressed native libraries. The default is DefaultLibraryAlignment.
+	LibraryAlignment int64
+}
+
+func (opts *SignOptions) hasScheme(scheme SignatureScheme) bool {
+	if len(opts.Schemes) == 0 {
+		return scheme == SignatureSchemeV1 || scheme == SignatureSchemeV2 || scheme == SignatureSchemeV3
+	}
+	for _, s := range opts.Schemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// signatureAlgorithmOf returns the signature algorithm for the public key, same as apksigner.
+func signatureAlgorithmOf(pub crypto.PublicKey) (SignatureAlgorithm, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() <= 3072 {
+			return SignatureRSAPKCS1v15WithSHA256, nil
+		}
+		return SignatureRSAPKCS1v15WithSHA512, nil
+	case *ecdsa.PublicKey:
+		if pub.Curve.Params().BitSize <= 256 {
+			return SignatureECDSAWithSHA256, nil
+		}
+		return SignatureECDSAWithSHA512, nil
+	}
+	return 0, fmt.Errorf("apk: unsupported key type: %T", pub)
+}
+
+// jarDigestAlgorithmOf returns the digest algorithm of the JAR signing for the public key, same as apksigner.
+// Android verifies SHA-256 digests from API level 18, and ECDSA signatures with SHA-256 from API level 21.
+func jarDigestAlgorithmOf(pub crypto.PublicKey, minSDKVersion int32) (crypto.Hash, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		if minSDKVersion < 18 {
+			return crypto.SHA1, nil
+		}
+		return crypto.SHA256, nil
+	case *ecdsa.PublicKey:
+		if minSDKVersion < 18 {
+			return 0, fmt.Errorf("apk: ECDSA JAR signatures need minSdkVersion 18 or higher, but it is %d", minSDKVersion)
+		}
+		if minSDKVersion < 21 {
+			return crypto.SHA1, nil
+		}
+		return crypto.SHA256, nil
+	}
+	return 0, fmt.Errorf("apk: unsupported key type: %T", pub)
+}
+
+// jarDigestName returns the name of the digest algorithm in the JAR manifest, such as "SHA-256".
+func jarDigestName(hash crypto.Hash) string {
+	if hash == crypto.SHA1 {
+		return "SHA1"
+	}
+	return "SHA-256"
+}
+
+// minSDKVersionOf returns the minSdkVersion in the manifest of the APK.
+// The codenames of the preview platforms are treated as the latest API level.
+func minSDKVersionOf(r io.ReaderAt, size int64, zr *zip.Reader) (int32, error) {
+	k := &Apk{
+		r:         r,
+		size:      size,
+		zipreader: zr,
+		opts:      OpenOptions{AllowMissingResources: true},
+	}
+	if err := k.parseAll(); err != nil {
+		return 0, err
+	}
+	min, err := k.manifest.SDK.Min.Int32()
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) && numErr.Err == strconv.ErrSyntax {
+		return math.MaxInt32, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("apk: invalid minSdkVersion: %w", err)
+	}
+	if min == 0 {
+		min = 1
+	}
+	return min, nil
+}
+
+// Sign signs the APK read from r, and writes the signed APK to w.
+// The existing signatures are removed, and the uncompressed entries are aligned.
+// The JAR signing uses SHA-1 instead of SHA-256 if the minSdkVersion of the APK requires it, same as apksigner.
+// The entries are copied without recompression.
+func Sign(w io.Writer, r io.ReaderAt, size int64, opts *SignOptions) error {
+	if opts == nil || opts.Key == nil || len(opts.Certificates) == 0 {
+		return errors.New("apk: the key and the certificates are required to sign")
+	}
+	alg, err := signatureAlgorithmOf(opts.Key.Public())
+	if err != nil {
+		return err
+	}
+	alignment, libraryAlignment := (&AlignOptions{
+		Alignment:        opts.Alignment,
+		LibraryAlignment: opts.LibraryAlignment,
+	}).alignments()
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	// copy the entries, and sign them with the JAR signing
+	var buf bytes.Buffer
+	zw := newAlignedZipWriter(&buf, alignment, libraryAlignment)
+	v1 := opts.hasScheme(SignatureSchemeV1)
+	hash := crypto.SHA256
+	if v1 {
+		min, err := minSDKVersionOf(r, size, zr)
+		if err != nil {
+			return err
+		}
+		hash, err = jarDigestAlgorithmOf(opts.Key.Public(), min)
+		if err != nil {
+			return err
+		}
+	}
+	var manifest bytes.Buffer
+	writeJARAttribute(&manifest, "Manifest-Version", "1.0")
+	writeJARAttribute(&manifest, "Created-By", "1.0 (Android)")
+	manifest.WriteString("\r\n")
+	var sections []jarSection
+	for _, file := range zr.File {
+		if isJARSignatureFile(file.Name) {
+			continue
+		}
+		if err := zw.copyFile(file); err != nil {
+			return err
+		}
+		if !v1 || !needsJARDigest(file.Name) {
+			continue
+		}
+		digest, err := digestZipFile(file, hash)
+		if err != nil {
+			return err
+		}
+		start := manifest.Len()
+		writeJARAttribute(&manifest, "Name", file.Name)
+		writeJARAttribute(&manifest, jarDigestName(hash)+"-Digest", base64.StdEncoding.EncodeToString(digest))
+		manifest.WriteString("\r\n")
+		sections = append(sections, jarSection{
+			name: file.Name,
+			raw:  append([]byte(nil), manifest.Bytes()[start:]...),
+		})
+	}
+	if v1 {
+		if err := signJAR(zw, manifest.Bytes(), sections, hash, opts); err != nil {
+			return err
+		}
+	}
+	if err := zw.close(); err != nil {
+		return err
+	}
+
+	// insert the APK Signing Block before the central directory
+	data := buf.Bytes()
+	var pairs []signingBlockPair
+	if opts.hasScheme(SignatureSchemeV2) || opts.hasScheme(SignatureSchemeV3) {
+		pairs, err = signSchemes(data, alg, opts)
+		if err != nil {
+			return err
+		}
+	}
+	if len(pairs) == 0 {
+		_, err := w.Write(data)
+		return err
+	}
+	return writeSigningBlock(w, data, pairs)
+}
+
+// digestZipFile returns the digest of the uncompressed contents.
+func digestZipFile(file *zip.File, hash crypto.Hash) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	h := hash.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// writeJARAttribute writes an attribute of the manifest, wrapping lines at 72 bytes.
+func writeJARAttribute(buf *bytes.Buffer, name, value string) {
+	line := name + ": " + value
+	max := 72
+	for len(line) > max {
+		buf.WriteString(line[:max])
+		buf.WriteString("\r\n ")
+		line = line[max:]
+		max = 71
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// jarSection is a per-entry section of the manifest.
+type jarSection struct {
+	name string
+	raw  []byte
+}
+
+// signJAR adds the manifest, the signature file and the signature block file.
+func signJAR(zw *alignedZipWriter, manifest []byte, sections []jarSection, hash crypto.Hash, opts *SignOptions) error {
+	name := opts.SignerName
+	if name == "" {
+		name = "CERT"
+	}
+	name = strings.ToUpper(name)
+
+	digest := func(data []byte) string {
+		h := hash.New()
+		h.Write(data)
+		return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	var sf bytes.Buffer
+	writeJARAttribute(&sf, "Signature-Version", "1.0")
+	writeJARAttribute(&sf, "Created-By", "1.0 (Android)")
+	writeJARAttribute(&sf, jarDigestName(hash)+"-Digest-Manifest", digest(manifest))
+	var signed []string
+	if opts.hasScheme(SignatureSchemeV2) {
+		signed = append(signed, "2")
+	}
+	if opts.hasScheme(SignatureSchemeV3) {
+		signed = append(signed, "3")
+	}
+	if len(signed) > 0 {
+		writeJARAttribute(&sf, "X-Android-APK-Signed", strings.Join(signed, ", "))
+	}
+	sf.WriteString("\r\n")
+	for _, section := range sections {
+		writeJARAttribute(&sf, "Name", section.name)
+		writeJARAttribute(&sf, jarDigestName(hash)+"-Digest", digest(section.raw))
+		sf.WriteString("\r\n")
+	}
+
+	block, ext, err := signPKCS7(sf.Bytes(), hash, opts)
+	if err != nil {
+		return err
+	}
+	if err := zw.create(jarManifestPath, manifest); err != nil {
+		return err
+	}
+	if err := zw.create("META-INF/"+name+".SF", sf.Bytes()); err != nil {
+		return err
+	}
+	return zw.create("META-INF/"+name+ext, block)
+}
+
+// signPKCS7 generates the detached PKCS #7 signature of the content without authenticated attributes,
+// and returns it with the extension of the signature block file.
+func signPKCS7(content []byte, hash crypto.Hash, opts *SignOptions) ([]byte, string, error) {
+	cert := opts.Certificates[0]
+	var sigAlg pkix.AlgorithmIdentifier
+	var ext string
+	switch opts.Key.Public().(type) {
+	case *rsa.PublicKey:
+		sigAlg = pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}
+		ext = ".RSA"
+	case *ecdsa.PublicKey:
+		sigAlg = pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}
+		if hash == crypto.SHA1 {
+			sigAlg = pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA1}
+		}
+		ext = ".EC"
+	default:
+		return nil, "", fmt.Errorf("apk: unsupported key type: %T", opts.Key.Public())
+	}
+
+	h := hash.New()
+	h.Write(content)
+	sig, err := opts.Key.Sign(rand.Reader, h.Sum(nil), hash)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sid, err := asn1.Marshal(pkcs7IssuerAndSerial{
+		Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+		SerialNumber: cert.SerialNumber,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	var certs []byte
+	for _, c := range opts.Certificates {
+		certs = append(certs, c.Raw...)
+	}
+	digestAlg := pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+	if hash == crypto.SHA1 {
+		digestAlg.Algorithm = oidSHA1
+	}
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlg},
+		ContentInfo:      pkcs7ContentInfo{ContentType: oidData},
+		Certificates: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      certs,
+		},
+		SignerInfos: []pkcs7SignerInfo{{
+			Version:                   1,
+			SignerIdentifier:          asn1.RawValue{FullBytes: sid},
+			DigestAlgorithm:           digestAlg,
+			DigestEncryptionAlgorithm: sigAlg,
+			EncryptedDigest:           sig,
+		}},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	block, err := asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidSignedData,
+		Content: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      signedData,
+		},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return block, ext, nil
+}
+
+// signingBlockPair is an ID-value pair in the APK Signing Block.
+type signingBlockPair struct {
+	id    uint32
+	value []byte
+}
+
+// signSchemes generates the APK Signature Scheme v2 and v3 blocks of the zip file.
+func signSchemes(data []byte, alg SignatureAlgorithm, opts *SignOptions) ([]signingBlockPair, error) {
+	r := bytes.NewReader(data)
+	sections, err := findZipSections(r, int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	digest, err := computeChunkedDigest(alg.hash(), contentSections(r, sections, sections.cdOffset))
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []signingBlockPair
+	if opts.hasScheme(SignatureSchemeV2) {
+		var attrs [][]byte
+		if opts.hasScheme(SignatureSchemeV3) {
+			// protect the v3 signature from being stripped
+			attrs = append(attrs, appendUint32(appendUint32(nil, strippingProtectionAttrID), uint32(SignatureSchemeV3)))
+		}
+		signer, err := encodeSigner(SignatureSchemeV2, alg, digest, attrs, 0, 0, opts)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, signingBlockPair{signatureSchemeV2BlockID, signer})
+	}
+	if opts.hasScheme(SignatureSchemeV3) {
+		min := opts.MinSDKVersion
+		if min == 0 {
+			min = v3MinSDKVersion
+		}
+		signer, err := encodeSigner(SignatureSchemeV3, alg, digest, nil, uint32(min), 0x7fffffff, opts)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, signingBlockPair{signatureSchemeV3BlockID, signer})
+	}
+	return pairs, nil
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// appendLengthPrefixed appends the concatenation of values prefixed with its length.
+func appendLengthPrefixed(b []byte, values ...[]byte) []byte {
+	size := 0
+	for _, v := range values {
+		size += len(v)
+	}
+	b = appendUint32(b, uint32(size))
+	for _, v := range values {
+		b = append(b, v...)
+	}
+	return b
+}
+
+// encodeSigner encodes the value of the v2 or v3 block with a signer.
+func encodeSigner(scheme SignatureScheme, alg SignatureAlgorithm, digest []byte, attrs [][]byte, minSDK, maxSDK uint32, opts *SignOptions) ([]byte, error) {
+	var sdk []byte
+	if scheme != SignatureSchemeV2 {
+		sdk = appendUint32(appendUint32(nil, minSDK), maxSDK)
+	}
+
+	var digests, certs, attributes []byte
+	digests = appendLengthPrefixed(nil, appendLengthPrefixed(appendUint32(nil, uint32(alg)), digest))
+	for _, cert := range opts.Certificates {
+		certs = appendLengthPrefixed(certs, cert.Raw)
+	}
+	for _, attr := range attrs {
+		attributes = appendLengthPrefixed(attributes, attr)
+	}
+	signedData := appendLengthPrefixed(nil, digests)
+	signedData = appendLengthPrefixed(signedData, certs)
+	signedData = append(signedData, sdk...)
+	signedData = appendLengthPrefixed(signedData, attributes)
+
+	hash := alg.hash()
+	h := hash.New()
+	h.Write(signedData)
+	sig, err := opts.Key.Sign(rand.Reader, h.Sum(nil), hash)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(opts.Key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	signer := appendLengthPrefixed(nil, signedData)
+	signer = append(signer, sdk...)
+	signatures := appendLengthPrefixed(nil, appendLengthPrefixed(appendUint32(nil, uint32(alg)), sig))
+	signer = appendLengthPrefixed(signer, signatures)
+	signer = appendLengthPrefixed(signer, publicKey)
+	return appendLengthPrefixed(nil, appendLengthPrefixed(nil, signer)), nil
+}
+
+// writeSigningBlock writes the zip file with the APK Signing Block inserted before the central directory.
+func writeSigningBlock(w io.Writer, data []byte, pairs []signingBlockPair) error {
+	sections, err := findZipSections(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	var block []byte
+	for _, p := range pairs {
+		var size [8]byte
+		binary.LittleEndian.PutUint64(size[:], uint64(len(p.value)+4))
+		block = append(block, size[:]...)
+		block = appendUint32(block, p.id)
+		block = append(block, p.value...)
+	}
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(block)+signingBlockFooterSize))
+	block = append(size[:], block...)
+	block = append(block, size[:]...)
+	block = append(block, signingBlockMagic...)
+
+	eocd := make([]byte, len(sections.eocd))
+	copy(eocd, sections.eocd)
+	binary.LittleEndian.PutUint32(eocd[eocdCDOffsetOffset:], uint32(sections.cdOffset)+uint32(len(block)))
+
+	for _, b := range [][]byte{data[:sections.cdOffset], block, data[sections.cdOffset:sections.eocdOffset], eocd} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"debug/elf"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRSASigner(t *testing.T) *SignOptions {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "rsa signer"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SignOptions{Key: key, Certificates: []*x509.Certificate{cert}}
+}
+
+// newTestUnsignedAPK returns an unsigned APK with the resources of testdata/helloworld.apk and the minSdkVersion.
+func newTestUnsignedAPK(t *testing.T, minSDKVersion string) []byte {
+	t.Helper()
+	manifest := `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.helloworld">
+	<uses-sdk android:minSdkVersion="` + minSDKVersion + `"/>
+</manifest>`
+	return newTestZip(t, map[string][]byte{
+		"AndroidManifest.xml": encodeTestXML(t, manifest),
+		"resources.arsc":      readTestResources(t),
+	}, zip.Deflate)
+}
+
+func TestSign(t *testing.T) {
+	ecdsaSigner := newTestSigner(t, "ecdsa signer")
+	ecdsaOpts := &SignOptions{Key: ecdsaSigner.key, Certificates: []*x509.Certificate{ecdsaSigner.cert}}
+	cases := []struct {
+		name     string
+		unsigned []byte
+		opts     *SignOptions
+		digest   string
+	}{
+		// the minSdkVersion of helloworld.apk is 15.
+		{"rsa", readHelloWorld(t), newTestRSASigner(t), "SHA1-Digest"},
+		{"rsa api 18", newTestUnsignedAPK(t, "18"), newTestRSASigner(t), "SHA-256-Digest"},
+		{"ecdsa api 18", newTestUnsignedAPK(t, "18"), ecdsaOpts, "SHA1-Digest"},
+		{"ecdsa api 21", newTestUnsignedAPK(t, "21"), ecdsaOpts, "SHA-256-Digest"},
+		{"ecdsa preview", newTestUnsignedAPK(t, "Tiramisu"), ecdsaOpts, "SHA-256-Digest"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			unsigned := c.unsigned
+			var buf bytes.Buffer
+			if err := Sign(&buf, bytes.NewReader(unsigned), int64(len(unsigned)), c.opts); err != nil {
+				t.Fatal(err)
+			}
+			apk := openTestAPK(t, buf.Bytes())
+
+			result, err := apk.VerifySignatures()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Verified() || len(result.V2) != 1 || len(result.V3) != 1 {
+				t.Errorf("want v2 and v3 verified, got %+v", result)
+			}
+			if min := result.V3[0].MinSDKVersion; min != 28 {
+				t.Errorf("want v3 min SDK version 28, got %d", min)
+			}
+
+			jar, err := apk.VerifyJARSignature()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !jar.Verified() {
+				t.Errorf("want v1 verified, got %+v", jar)
+			}
+			if s := jar.Signers[0]; len(s.APKSigned) != 2 || !s.Verified() {
+				t.Errorf("unexpected v1 signer: %+v", s)
+			}
+			manifest, err := apk.readZipFile(jarManifestPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Contains(manifest, []byte("\r\n"+c.digest+": ")) {
+				t.Errorf("want %s in the manifest, got %s", c.digest, manifest)
+			}
+
+			certs, err := apk.SigningCertificates()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(certs) != 1 || !certs[0].Certificate.Equal(c.opts.Certificates[0]) {
+				t.Errorf("unexpected certificates: %+v", certs)
+			}
+
+			for _, file := range apk.zipreader.File {
+				if file.Method != zip.Store || strings.HasSuffix(file.Name, "/") {
+					continue
+				}
+				offset, err := file.DataOffset()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if offset%DefaultAlignment != 0 {
+					t.Errorf("%s: offset %d is not aligned", file.Name, offset)
+				}
+			}
+		})
+	}
+}
+
+func TestSignECDSAOldSDK(t *testing.T) {
+	// Android can't verify the JAR signatures of ECDSA before API level 18.
+	signer := newTestSigner(t, "signer")
+	unsigned := readHelloWorld(t)
+	err := Sign(ioutil.Discard, bytes.NewReader(unsigned), int64(len(unsigned)), &SignOptions{
+		Key:          signer.key,
+		Certificates: []*x509.Certificate{signer.cert},
+	})
+	if err == nil || !strings.Contains(err.Error(), "minSdkVersion") {
+		t.Errorf("want minSdkVersion error, got %v", err)
+	}
+
+	// it is fine without the JAR signing.
+	err = Sign(ioutil.Discard, bytes.NewReader(unsigned), int64(len(unsigned)), &SignOptions{
+		Key:          signer.key,
+		Certificates: []*x509.Certificate{signer.cert},
+		Schemes:      []SignatureScheme{SignatureSchemeV2, SignatureSchemeV3},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSignSchemes(t *testing.T) {
+	signer := newTestSigner(t, "signer")
+	unsigned := readHelloWorld(t)
+	var buf bytes.Buffer
+	err := Sign(&buf, bytes.NewReader(unsigned), int64(len(unsigned)), &SignOptions{
+		Key:          signer.key,
+		Certificates: []*x509.Certificate{signer.cert},
+		Schemes:      []SignatureScheme{SignatureSchemeV2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apk := openTestAPK(t, buf.Bytes())
+	result, err := apk.VerifySignatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Verified() || len(result.V2) != 1 || len(result.V3) != 0 {
+		t.Errorf("want only v2 verified, got %+v", result)
+	}
+	if _, err := apk.VerifyJARSignature(); err != ErrNoJARSignature {
+		t.Errorf("want ErrNoJARSignature, got %v", err)
+	}
+}
+
+func TestSignLibraryAlignment(t *testing.T) {
+	var in bytes.Buffer
+	w := zip.NewWriter(&in)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"AndroidManifest.xml", encodeTestXML(t, `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.native">
+	<uses-sdk android:minSdkVersion="24"/>
+</manifest>`)},
+		{"a.txt", []byte("abc")},
+		{"lib/arm64-v8a/libfoo.so", newTestELF64(t, elf.EM_AARCH64, PageSize16K)},
+		{"lib/arm64-v8a/libbar.so", newTestELF64(t, elf.EM_AARCH64, PageSize16K)},
+	}
+	for _, file := range files {
+		f, err := w.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(file.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	signer := newTestSigner(t, "signer")
+	var out bytes.Buffer
+	err := Sign(&out, bytes.NewReader(in.Bytes()), int64(in.Len()), &SignOptions{
+		Key:          signer.key,
+		Certificates: []*x509.Certificate{signer.cert},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apk := &Apk{r: bytes.NewReader(out.Bytes()), size: int64(out.Len()), zipreader: r}
+	libs, err := apk.NativeLibraries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lib := range libs["arm64-v8a"] {
+		if !lib.IsPageAligned(PageSize16K) {
+			t.Errorf("%s: offset %d is not aligned on 16 KB", lib.Path, lib.Offset)
+		}
+	}
+	if len(libs["arm64-v8a"]) != 2 {
+		t.Errorf("want 2 libraries, got %v", libs)
+	}
+	result, err := apk.VerifySignatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Verified() {
+		t.Errorf("want verified, got %+v", result)
+	}
+}
@@ -0,0 +1,735 @@
+package apk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256" // register SHA-256
+	_ "crypto/sha512" // register SHA-512
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+)
+
+// SignatureScheme is a version of the APK signature scheme.
+type SignatureScheme int
+
+// The constants for SignatureScheme.
+const (
+	SignatureSchemeV1  SignatureScheme = 1
+	SignatureSchemeV2  SignatureScheme = 2
+	SignatureSchemeV3  SignatureScheme = 3
+	SignatureSchemeV31 SignatureScheme = 31
+	SignatureSchemeV4  SignatureScheme = 4
+)
+
+func (s SignatureScheme) String() string {
+	switch s {
+	case SignatureSchemeV1:
+		return "v1"
+	case SignatureSchemeV2:
+		return "v2"
+	case SignatureSchemeV3:
+		return "v3"
+	case SignatureSchemeV31:
+		return "v3.1"
+	case SignatureSchemeV4:
+		return "v4"
+	}
+	return "SignatureScheme(" + strconv.Itoa(int(s)) + ")"
+}
+
+// SignatureAlgorithm is an algorithm ID of the APK Signature Scheme v2 and later.
+type SignatureAlgorithm uint32
+
+// The constants for SignatureAlgorithm.
+const (
+	SignatureRSAPSSWithSHA256            SignatureAlgorithm = 0x0101
+	SignatureRSAPSSWithSHA512            SignatureAlgorithm = 0x0102
+	SignatureRSAPKCS1v15WithSHA256       SignatureAlgorithm = 0x0103
+	SignatureRSAPKCS1v15WithSHA512       SignatureAlgorithm = 0x0104
+	SignatureECDSAWithSHA256             SignatureAlgorithm = 0x0201
+	SignatureECDSAWithSHA512             SignatureAlgorithm = 0x0202
+	SignatureDSAWithSHA256               SignatureAlgorithm = 0x0301
+	SignatureVerityRSAPKCS1v15WithSHA256 SignatureAlgorithm = 0x0421
+	SignatureVerityECDSAWithSHA256       SignatureAlgorithm = 0x0423
+	SignatureVerityDSAWithSHA256         SignatureAlgorithm = 0x0425
+)
+
+var signatureAlgorithmNames = map[SignatureAlgorithm]string{
+	SignatureRSAPSSWithSHA256:            "RSASSA-PSS with SHA2-256",
+	SignatureRSAPSSWithSHA512:            "RSASSA-PSS with SHA2-512",
+	SignatureRSAPKCS1v15WithSHA256:       "RSASSA-PKCS1-v1_5 with SHA2-256",
+	SignatureRSAPKCS1v15WithSHA512:       "RSASSA-PKCS1-v1_5 with SHA2-512",
+	SignatureECDSAWithSHA256:             "ECDSA with SHA2-256",
+	SignatureECDSAWithSHA512:             "ECDSA with SHA2-512",
+	SignatureDSAWithSHA256:               "DSA with SHA2-256",
+	SignatureVerityRSAPKCS1v15WithSHA256: "RSASSA-PKCS1-v1_5 with SHA2-256 (verity)",
+	SignatureVerityECDSAWithSHA256:       "ECDSA with SHA2-256 (verity)",
+	SignatureVerityDSAWithSHA256:         "DSA with SHA2-256 (verity)",
+}
+
+func (a SignatureAlgorithm) String() string {
+	if name, ok := signatureAlgorithmNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("SignatureAlgorithm(%#04x)", uint32(a))
+}
+
+// hash returns the hash function used for signing.
+func (a SignatureAlgorithm) hash() crypto.Hash {
+	switch a {
+	case SignatureRSAPSSWithSHA256, SignatureRSAPKCS1v15WithSHA256, SignatureECDSAWithSHA256, SignatureDSAWithSHA256,
+		SignatureVerityRSAPKCS1v15WithSHA256, SignatureVerityECDSAWithSHA256, SignatureVerityDSAWithSHA256:
+		return crypto.SHA256
+	case SignatureRSAPSSWithSHA512, SignatureRSAPKCS1v15WithSHA512, SignatureECDSAWithSHA512:
+		return crypto.SHA512
+	}
+	return 0
+}
+
+// isVerity reports whether the content digest of the algorithm is the verity digest.
+func (a SignatureAlgorithm) isVerity() bool {
+	switch a {
+	case SignatureVerityRSAPKCS1v15WithSHA256, SignatureVerityECDSAWithSHA256, SignatureVerityDSAWithSHA256:
+		return true
+	}
+	return false
+}
+
+// verify verifies the signature of data.
+func (a SignatureAlgorithm) verify(pub crypto.PublicKey, data, sig []byte) error {
+	hash := a.hash()
+	if hash == 0 {
+		return fmt.Errorf("apk: unsupported signature algorithm: %v", a)
+	}
+	h := hash.New()
+	h.Write(data)
+	digest := h.Sum(nil)
+
+	switch a {
+	case SignatureRSAPSSWithSHA256, SignatureRSAPSSWithSHA512:
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("apk: %v requires an RSA key, got %T", a, pub)
+		}
+		return rsa.VerifyPSS(key, hash, digest, sig, &rsa.PSSOptions{SaltLength: hash.Size()})
+	case SignatureRSAPKCS1v15WithSHA256, SignatureRSAPKCS1v15WithSHA512, SignatureVerityRSAPKCS1v15WithSHA256:
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("apk: %v requires an RSA key, got %T", a, pub)
+		}
+		return rsa.VerifyPKCS1v15(key, hash, digest, sig)
+	case SignatureECDSAWithSHA256, SignatureECDSAWithSHA512, SignatureVerityECDSAWithSHA256:
+		key, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("apk: %v requires an ECDSA key, got %T", a, pub)
+		}
+		if !ecdsa.VerifyASN1(key, digest, sig) {
+			return errors.New("apk: ECDSA verification failure")
+		}
+		return nil
+	case SignatureDSAWithSHA256, SignatureVerityDSAWithSHA256:
+		key, ok := pub.(*dsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("apk: %v requires a DSA key, got %T", a, pub)
+		}
+		return verifyDSA(key, digest, sig)
+	}
+	return fmt.Errorf("apk: unsupported signature algorithm: %v", a)
+}
+
+// verifyDSA verifies an ASN.1 encoded DSA signature.
+func verifyDSA(key *dsa.PublicKey, digest, sig []byte) error {
+	var s struct {
+		R, S *big.Int
+	}
+	rest, err := asn1.Unmarshal(sig, &s)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("apk: trailing data after DSA signature")
+	}
+	// FIPS 186-3 section 4.6: the hash is truncated to the byte-length of the subgroup order.
+	if n := (key.Q.BitLen() + 7) / 8; len(digest) > n {
+		digest = digest[:n]
+	}
+	if !dsa.Verify(key, digest, s.R, s.S) {
+		return errors.New("apk: DSA verification failure")
+	}
+	return nil
+}
+
+// Digest is a content digest of a signer.
+type Digest struct {
+	Algorithm SignatureAlgorithm
+	Value     []byte
+}
+
+// Signature is a signature of a signer.
+type Signature struct {
+	Algorithm SignatureAlgorithm
+	Value     []byte
+}
+
+// SignerAttribute is an additional attribute in the signed data of a signer.
+type SignerAttribute struct {
+	ID    uint32
+	Value []byte
+}
+
+// The IDs of the additional attributes.
+const (
+	// strippingProtectionAttrID is the ID of the attribute in v2 signers
+	// that records the newer signature schemes the APK was signed with.
+	strippingProtectionAttrID = 0xbeeff00d
+
+	// proofOfRotationAttrID is the ID of the attribute in v3 signers that has the signing certificate lineage.
+	proofOfRotationAttrID = 0x3ba06f8c
+)
+
+// LineageFlags is the capabilities granted to a previous signing certificate in the lineage.
+type LineageFlags uint32
+
+// The constants for LineageFlags.
+const (
+	LineageInstalledData LineageFlags = 1 << iota
+	LineageSharedUID
+	LineagePermission
+	LineageRollback
+	LineageAuth
+)
+
+// LineageNode is a signing certificate in the proof-of-rotation lineage of the APK Signature Scheme v3.
+type LineageNode struct {
+	// Certificate is the signing certificate.
+	Certificate *x509.Certificate
+
+	// ParentSignatureAlgorithm is the algorithm that the previous certificate used to sign this node.
+	// It is zero for the first node.
+	ParentSignatureAlgorithm SignatureAlgorithm
+
+	// SignatureAlgorithm is the algorithm that this certificate uses to sign the next node.
+	SignatureAlgorithm SignatureAlgorithm
+
+	// Flags is the capabilities granted to the certificate.
+	Flags LineageFlags
+}
+
+// Signer is a signer of the APK Signature Scheme v2, v3 or v3.1.
+type Signer struct {
+	// Scheme is the signature scheme of the signer.
+	Scheme SignatureScheme
+
+	// Certificates is the certificate chain. The first certificate is the signing certificate.
+	Certificates []*x509.Certificate
+
+	// PublicKey is the public key of the signer.
+	PublicKey crypto.PublicKey
+
+	// Digests is the content digests of the APK.
+	Digests []Digest
+
+	// Signatures is the signatures over the signed data.
+	Signatures []Signature
+
+	// Attributes is the additional attributes in the signed data.
+	Attributes []SignerAttribute
+
+	// MinSDKVersion and MaxSDKVersion is the range of the API levels that the signer targets.
+	// They are zero for v2 signers.
+	MinSDKVersion int32
+	MaxSDKVersion int32
+
+	// Lineage is the proof-of-rotation lineage from the oldest certificate to the current one.
+	// It is nil if the signing key has never rotated.
+	Lineage []LineageNode
+
+	// Errors is the errors found in verifying the signer.
+	Errors []error
+}
+
+// Verified returns whether the signer is verified.
+func (s *Signer) Verified() bool {
+	return len(s.Errors) == 0
+}
+
+func (s *Signer) errorf(format string, args ...interface{}) {
+	s.Errors = append(s.Errors, fmt.Errorf(format, args...))
+}
+
+// SignatureVerification is the result of verifying the APK Signature Scheme v2, v3 and v3.1.
+type SignatureVerification struct {
+	// V2, V3 and V31 is the signers of each scheme.
+	V2  []*Signer
+	V3  []*Signer
+	V31 []*Signer
+
+	// Errors is the errors that are not specific to a signer.
+	Errors []error
+}
+
+// Schemes returns the signature schemes that the APK is signed with.
+func (v *SignatureVerification) Schemes() []SignatureScheme {
+	var schemes []SignatureScheme
+	if len(v.V2) > 0 {
+		schemes = append(schemes, SignatureSchemeV2)
+	}
+	if len(v.V3) > 0 {
+		schemes = append(schemes, SignatureSchemeV3)
+	}
+	if len(v.V31) > 0 {
+		schemes = append(schemes, SignatureSchemeV31)
+	}
+	return schemes
+}
+
+// Signers returns the signers of all schemes.
+func (v *SignatureVerification) Signers() []*Signer {
+	signers := make([]*Signer, 0, len(v.V2)+len(v.V3)+len(v.V31))
+	signers = append(signers, v.V2...)
+	signers = append(signers, v.V3...)
+	signers = append(signers, v.V31...)
+	return signers
+}
+
+// Verified returns whether the APK is signed and all signers are verified.
+func (v *SignatureVerification) Verified() bool {
+	if len(v.Errors) != 0 {
+		return false
+	}
+	signers := v.Signers()
+	if len(signers) == 0 {
+		return false
+	}
+	for _, s := range signers {
+		if !s.Verified() {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifySignatures verifies the APK Signature Scheme v2, v3 and v3.1 signatures.
+// It returns ErrNoSigningBlock if the APK has no APK Signing Block.
+// Verification failures are reported in the result, not as an error.
+//
+// The verity digests are not verified, while their signatures are verified.
+func (k *Apk) VerifySignatures() (*SignatureVerification, error) {
+	if k.r == nil {
+		return nil, ErrNoReader
+	}
+	sections, err := findZipSections(k.r, k.size)
+	if err != nil {
+		return nil, err
+	}
+	block, err := findSigningBlock(k.r, sections)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(SignatureVerification)
+	schemes := []struct {
+		scheme  SignatureScheme
+		id      uint32
+		signers *[]*Signer
+	}{
+		{SignatureSchemeV2, signatureSchemeV2BlockID, &result.V2},
+		{SignatureSchemeV3, signatureSchemeV3BlockID, &result.V3},
+		{SignatureSchemeV31, signatureSchemeV31BlockID, &result.V31},
+	}
+	for _, s := range schemes {
+		value, ok := block.pairs[s.id]
+		if !ok {
+			continue
+		}
+		signers, err := parseSigners(s.scheme, value)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("apk: failed to parse %v signers: %w", s.scheme, err))
+			continue
+		}
+		*s.signers = signers
+	}
+	if len(result.Signers()) == 0 && len(result.Errors) == 0 {
+		return nil, ErrNoSigningBlock
+	}
+
+	result.checkStripping()
+	if err := result.verifyDigests(k.r, sections, block); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkStripping checks whether the signatures of newer schemes recorded in the v2 signers are removed.
+func (v *SignatureVerification) checkStripping() {
+	for _, s := range v.V2 {
+		for _, attr := range s.Attributes {
+			if attr.ID != strippingProtectionAttrID || len(attr.Value) < 4 {
+				continue
+			}
+			id := binary.LittleEndian.Uint32(attr.Value)
+			if id == uint32(SignatureSchemeV3) && len(v.V3) == 0 {
+				s.errorf("apk: the APK was signed with %v, but the signature was stripped", SignatureSchemeV3)
+			}
+		}
+	}
+}
+
+// verifyDigests verifies the content digests of all signers.
+func (v *SignatureVerification) verifyDigests(r io.ReaderAt, sections *zipSections, block *signingBlock) error {
+	computed := make(map[crypto.Hash][]byte)
+	for _, s := range v.Signers() {
+		verified := false
+		for _, d := range s.Digests {
+			if d.Algorithm.isVerity() {
+				continue
+			}
+			hash := d.Algorithm.hash()
+			if hash == 0 {
+				continue
+			}
+			digest, ok := computed[hash]
+			if !ok {
+				var err error
+				digest, err = computeChunkedDigest(hash, contentSections(r, sections, block.offset))
+				if err != nil {
+					return err
+				}
+				computed[hash] = digest
+			}
+			if !bytes.Equal(digest, d.Value) {
+				s.errorf("apk: %v content digest mismatch", d.Algorithm)
+			}
+			verified = true
+		}
+		if !verified {
+			s.errorf("apk: no supported content digest")
+		}
+	}
+	return nil
+}
+
+// signatureReader reads the length-prefixed values in the signature scheme blocks.
+type signatureReader struct {
+	data []byte
+}
+
+var errTruncatedSignature = errors.New("apk: truncated signature data")
+
+func (r *signatureReader) len() int {
+	return len(r.data)
+}
+
+func (r *signatureReader) uint32() (uint32, error) {
+	if len(r.data) < 4 {
+		return 0, errTruncatedSignature
+	}
+	v := binary.LittleEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return v, nil
+}
+
+func (r *signatureReader) bytes() ([]byte, error) {
+	size, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(size) > uint64(len(r.data)) {
+		return nil, errTruncatedSignature
+	}
+	v := r.data[:size]
+	r.data = r.data[size:]
+	return v, nil
+}
+
+func (r *signatureReader) reader() (*signatureReader, error) {
+	v, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &signatureReader{data: v}, nil
+}
+
+// parseSigners parses the value of the signature scheme block and verifies the signatures of the signers.
+func parseSigners(scheme SignatureScheme, value []byte) ([]*Signer, error) {
+	r := &signatureReader{data: value}
+	list, err := r.reader()
+	if err != nil {
+		return nil, err
+	}
+	var signers []*Signer
+	for list.len() > 0 {
+		data, err := list.bytes()
+		if err != nil {
+			return nil, err
+		}
+		signer := &Signer{Scheme: scheme}
+		if err := signer.parse(data); err != nil {
+			signer.errorf("apk: failed to parse the signer: %w", err)
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) == 0 {
+		return nil, errors.New("apk: no signers")
+	}
+	return signers, nil
+}
+
+// parse parses a signer and verifies its signatures.
+func (s *Signer) parse(data []byte) error {
+	r := &signatureReader{data: data}
+	signedData, err := r.bytes()
+	if err != nil {
+		return err
+	}
+	if s.Scheme != SignatureSchemeV2 {
+		min, err := r.uint32()
+		if err != nil {
+			return err
+		}
+		max, err := r.uint32()
+		if err != nil {
+			return err
+		}
+		s.MinSDKVersion, s.MaxSDKVersion = int32(min), int32(max)
+	}
+	signatures, err := r.reader()
+	if err != nil {
+		return err
+	}
+	publicKey, err := r.bytes()
+	if err != nil {
+		return err
+	}
+
+	s.PublicKey, err = x509.ParsePKIXPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	// verify the signatures over the signed data
+	for signatures.len() > 0 {
+		sr, err := signatures.reader()
+		if err != nil {
+			return err
+		}
+		alg, err := sr.uint32()
+		if err != nil {
+			return err
+		}
+		value, err := sr.bytes()
+		if err != nil {
+			return err
+		}
+		s.Signatures = append(s.Signatures, Signature{
+			Algorithm: SignatureAlgorithm(alg),
+			Value:     value,
+		})
+	}
+	verified := 0
+	for _, sig := range s.Signatures {
+		if sig.Algorithm.hash() == 0 {
+			continue
+		}
+		if err := sig.Algorithm.verify(s.PublicKey, signedData, sig.Value); err != nil {
+			s.errorf("apk: failed to verify the %v signature: %w", sig.Algorithm, err)
+		}
+		verified++
+	}
+	if verified == 0 {
+		s.errorf("apk: no supported signatures")
+	}
+
+	if err := s.parseSignedData(signedData); err != nil {
+		return err
+	}
+
+	// the signed data must be consistent with the unsigned data
+	if len(s.Digests) != len(s.Signatures) {
+		s.errorf("apk: the signature algorithms of the digests and the signatures don't match")
+	} else {
+		for i := range s.Digests {
+			if s.Digests[i].Algorithm != s.Signatures[i].Algorithm {
+				s.errorf("apk: the signature algorithms of the digests and the signatures don't match")
+				break
+			}
+		}
+	}
+	if len(s.Certificates) == 0 {
+		s.errorf("apk: no certificates")
+	} else if !bytes.Equal(s.Certificates[0].RawSubjectPublicKeyInfo, publicKey) {
+		s.errorf("apk: the public key doesn't match the signing certificate")
+	}
+	if s.Lineage != nil && len(s.Certificates) > 0 {
+		last := s.Lineage[len(s.Lineage)-1]
+		if !last.Certificate.Equal(s.Certificates[0]) {
+			s.errorf("apk: the last certificate in the lineage doesn't match the signing certificate")
+		}
+	}
+	return nil
+}
+
+func (s *Signer) parseSignedData(data []byte) error {
+	r := &signatureReader{data: data}
+	digests, err := r.reader()
+	if err != nil {
+		return err
+	}
+	for digests.len() > 0 {
+		dr, err := digests.reader()
+		if err != nil {
+			return err
+		}
+		alg, err := dr.uint32()
+		if err != nil {
+			return err
+		}
+		value, err := dr.bytes()
+		if err != nil {
+			return err
+		}
+		s.Digests = append(s.Digests, Digest{
+			Algorithm: SignatureAlgorithm(alg),
+			Value:     value,
+		})
+	}
+
+	certs, err := r.reader()
+	if err != nil {
+		return err
+	}
+	for certs.len() > 0 {
+		der, err := certs.bytes()
+		if err != nil {
+			return err
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return err
+		}
+		s.Certificates = append(s.Certificates, cert)
+	}
+
+	if s.Scheme != SignatureSchemeV2 {
+		min, err := r.uint32()
+		if err != nil {
+			return err
+		}
+		max, err := r.uint32()
+		if err != nil {
+			return err
+		}
+		if int32(min) != s.MinSDKVersion || int32(max) != s.MaxSDKVersion {
+			s.errorf("apk: the SDK versions in the signed data (%d-%d) don't match the signer (%d-%d)",
+				min, max, s.MinSDKVersion, s.MaxSDKVersion)
+		}
+	}
+
+	attrs, err := r.reader()
+	if err != nil {
+		return err
+	}
+	for attrs.len() > 0 {
+		ar, err := attrs.reader()
+		if err != nil {
+			return err
+		}
+		id, err := ar.uint32()
+		if err != nil {
+			return err
+		}
+		attr := SignerAttribute{ID: id, Value: ar.data}
+		s.Attributes = append(s.Attributes, attr)
+		if id == proofOfRotationAttrID && s.Scheme != SignatureSchemeV2 {
+			lineage, err := parseLineage(attr.Value)
+			if err != nil {
+				s.errorf("apk: invalid proof-of-rotation lineage: %w", err)
+				continue
+			}
+			s.Lineage = lineage
+		}
+	}
+	return nil
+}
+
+// parseLineage parses the proof-of-rotation lineage and verifies that
+// each certificate is signed by the previous one.
+func parseLineage(data []byte) ([]LineageNode, error) {
+	r := &signatureReader{data: data}
+	version, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	if version != 1 {
+		return nil, fmt.Errorf("unsupported version: %d", version)
+	}
+
+	var lineage []LineageNode
+	var last *x509.Certificate
+	var lastAlgorithm SignatureAlgorithm
+	for r.len() > 0 {
+		nr, err := r.reader()
+		if err != nil {
+			return nil, err
+		}
+		signedData, err := nr.bytes()
+		if err != nil {
+			return nil, err
+		}
+		flags, err := nr.uint32()
+		if err != nil {
+			return nil, err
+		}
+		alg, err := nr.uint32()
+		if err != nil {
+			return nil, err
+		}
+		sig, err := nr.bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		sr := &signatureReader{data: signedData}
+		der, err := sr.bytes()
+		if err != nil {
+			return nil, err
+		}
+		parentAlg, err := sr.uint32()
+		if err != nil {
+			return nil, err
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+
+		if last != nil {
+			if SignatureAlgorithm(parentAlg) != lastAlgorithm {
+				return nil, fmt.Errorf("signature algorithm mismatch: %v and %v", SignatureAlgorithm(parentAlg), lastAlgorithm)
+			}
+			if err := lastAlgorithm.verify(last.PublicKey, signedData, sig); err != nil {
+				return nil, fmt.Errorf("failed to verify the certificate %q: %w", cert.Subject, err)
+			}
+		}
+		lineage = append(lineage, LineageNode{
+			Certificate:              cert,
+			ParentSignatureAlgorithm: SignatureAlgorithm(parentAlg),
+			SignatureAlgorithm:       SignatureAlgorithm(alg),
+			Flags:                    LineageFlags(flags),
+		})
+		last, lastAlgorithm = cert, SignatureAlgorithm(alg)
+	}
+	if len(lineage) == 0 {
+		return nil, errors.New("empty lineage")
+	}
+	return lineage, nil
+}
@@ -0,0 +1,348 @@
+package apk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type testSigner struct {
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestSigner(t *testing.T, name string) *testSigner {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testSigner{key: key, cert: cert}
+}
+
+func (s *testSigner) sign(t *testing.T, data []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, s.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func testUint32(v uint32) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return b[:]
+}
+
+// testLP returns the length-prefixed concatenation of b.
+func testLP(b ...[]byte) []byte {
+	data := bytes.Join(b, nil)
+	return append(testUint32(uint32(len(data))), data...)
+}
+
+// encodeTestSigner encodes a signer of the APK Signature Scheme v2 or v3.
+func encodeTestSigner(t *testing.T, s *testSigner, scheme SignatureScheme, digest []byte, attrs ...[]byte) []byte {
+	t.Helper()
+	alg := testUint32(uint32(SignatureECDSAWithSHA256))
+	var sdk []byte
+	if scheme != SignatureSchemeV2 {
+		sdk = append(testUint32(24), testUint32(0x7fffffff)...)
+	}
+	var encodedAttrs [][]byte
+	for _, attr := range attrs {
+		encodedAttrs = append(encodedAttrs, testLP(attr))
+	}
+	signedData := bytes.Join([][]byte{
+		testLP(testLP(alg, testLP(digest))),
+		testLP(testLP(s.cert.Raw)),
+		sdk,
+		testLP(encodedAttrs...),
+	}, nil)
+	return testLP(testLP(
+		testLP(signedData),
+		sdk,
+		testLP(testLP(alg, testLP(s.sign(t, signedData)))),
+		testLP(s.cert.RawSubjectPublicKeyInfo),
+	))
+}
+
+// encodeTestLineage encodes the proof-of-rotation attribute in which each signer signs the next one.
+func encodeTestLineage(t *testing.T, signers ...*testSigner) []byte {
+	t.Helper()
+	alg := uint32(SignatureECDSAWithSHA256)
+	nodes := [][]byte{testUint32(proofOfRotationAttrID), testUint32(1)}
+	for i, s := range signers {
+		var parentAlg uint32
+		var sig []byte
+		if i > 0 {
+			parentAlg = alg
+		}
+		signedData := append(testLP(s.cert.Raw), testUint32(parentAlg)...)
+		if i > 0 {
+			sig = signers[i-1].sign(t, signedData)
+		}
+		nodes = append(nodes, testLP(testLP(signedData), testUint32(uint32(LineageInstalledData)), testUint32(alg), testLP(sig)))
+	}
+	return bytes.Join(nodes, nil)
+}
+
+// signTestAPK inserts an APK Signing Block into the unsigned APK.
+// build returns the ID-value pairs for the content digest of the APK.
+func signTestAPK(t *testing.T, unsigned []byte, build func(digest []byte) map[uint32][]byte) []byte {
+	t.Helper()
+	r := bytes.NewReader(unsigned)
+	sections, err := findZipSections(r, int64(len(unsigned)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := computeChunkedDigest(crypto.SHA256, contentSections(r, sections, sections.cdOffset))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pairs []byte
+	for id, value := range build(digest) {
+		var size [8]byte
+		binary.LittleEndian.PutUint64(size[:], uint64(len(value)+4))
+		pairs = append(pairs, size[:]...)
+		pairs = append(pairs, testUint32(id)...)
+		pairs = append(pairs, value...)
+	}
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(pairs)+signingBlockFooterSize))
+	block := bytes.Join([][]byte{size[:], pairs, size[:], []byte(signingBlockMagic)}, nil)
+
+	eocd := append([]byte(nil), sections.eocd...)
+	binary.LittleEndian.PutUint32(eocd[eocdCDOffsetOffset:], uint32(sections.cdOffset)+uint32(len(block)))
+	return bytes.Join([][]byte{
+		unsigned[:sections.cdOffset],
+		block,
+		unsigned[sections.cdOffset:sections.eocdOffset],
+		eocd,
+	}, nil)
+}
+
+func openTestAPK(t *testing.T, data []byte) *Apk {
+	t.Helper()
+	apk, err := OpenZipReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return apk
+}
+
+func readHelloWorld(t *testing.T) []byte {
+	t.Helper()
+	data, err := ioutil.ReadFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestVerifySignatures(t *testing.T) {
+	oldSigner := newTestSigner(t, "old")
+	newSigner := newTestSigner(t, "new")
+	data := signTestAPK(t, readHelloWorld(t), func(digest []byte) map[uint32][]byte {
+		stripping := append(testUint32(strippingProtectionAttrID), testUint32(uint32(SignatureSchemeV3))...)
+		return map[uint32][]byte{
+			signatureSchemeV2BlockID: encodeTestSigner(t, oldSigner, SignatureSchemeV2, digest, stripping),
+			signatureSchemeV3BlockID: encodeTestSigner(t, newSigner, SignatureSchemeV3, digest, encodeTestLineage(t, oldSigner, newSigner)),
+		}
+	})
+
+	result, err := openTestAPK(t, data).VerifySignatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range result.Signers() {
+		for _, err := range s.Errors {
+			t.Errorf("%v: %v", s.Scheme, err)
+		}
+	}
+	if !result.Verified() {
+		t.Fatalf("want verified, got %v", result.Errors)
+	}
+	if schemes := result.Schemes(); len(schemes) != 2 || schemes[0] != SignatureSchemeV2 || schemes[1] != SignatureSchemeV3 {
+		t.Errorf("unexpected schemes: %v", schemes)
+	}
+
+	v3 := result.V3[0]
+	if v3.MinSDKVersion != 24 || v3.MaxSDKVersion != 0x7fffffff {
+		t.Errorf("unexpected SDK range: %d-%d", v3.MinSDKVersion, v3.MaxSDKVersion)
+	}
+	if len(v3.Lineage) != 2 || !v3.Lineage[0].Certificate.Equal(oldSigner.cert) || !v3.Lineage[1].Certificate.Equal(newSigner.cert) {
+		t.Errorf("unexpected lineage: %+v", v3.Lineage)
+	}
+	if v3.Lineage[1].Flags&LineageInstalledData == 0 {
+		t.Errorf("want LineageInstalledData, got %#x", v3.Lineage[1].Flags)
+	}
+}
+
+func TestVerifySignaturesFailure(t *testing.T) {
+	signer := newTestSigner(t, "signer")
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		data := signTestAPK(t, readHelloWorld(t), func(digest []byte) map[uint32][]byte {
+			digest = append([]byte(nil), digest...)
+			digest[0] ^= 0xff
+			return map[uint32][]byte{
+				signatureSchemeV2BlockID: encodeTestSigner(t, signer, SignatureSchemeV2, digest),
+			}
+		})
+		result, err := openTestAPK(t, data).VerifySignatures()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Verified() {
+			t.Error("want not verified")
+		}
+	})
+
+	t.Run("stripped v3", func(t *testing.T) {
+		data := signTestAPK(t, readHelloWorld(t), func(digest []byte) map[uint32][]byte {
+			stripping := append(testUint32(strippingProtectionAttrID), testUint32(uint32(SignatureSchemeV3))...)
+			return map[uint32][]byte{
+				signatureSchemeV2BlockID: encodeTestSigner(t, signer, SignatureSchemeV2, digest, stripping),
+			}
+		})
+		result, err := openTestAPK(t, data).VerifySignatures()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Verified() {
+			t.Error("want not verified")
+		}
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		_, err := openTestAPK(t, readHelloWorld(t)).VerifySignatures()
+		if !errors.Is(err, ErrNoSigningBlock) {
+			t.Errorf("want ErrNoSigningBlock, got %v", err)
+		}
+	})
+}
+
+func TestVerifyV4Signature(t *testing.T) {
+	signer := newTestSigner(t, "signer")
+	var contentDigest []byte
+	data := signTestAPK(t, readHelloWorld(t), func(digest []byte) map[uint32][]byte {
+		contentDigest = digest
+		return map[uint32][]byte{
+			signatureSchemeV3BlockID: encodeTestSigner(t, signer, SignatureSchemeV3, digest),
+		}
+	})
+	apk := openTestAPK(t, data)
+
+	salt := []byte("salt")
+	root, err := computeMerkleRootHash(crypto.SHA256, bytes.NewReader(data), 4096, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := &V4Signature{
+		Version:            v4SignatureVersion,
+		HashAlgorithm:      v4HashSHA256,
+		Log2BlockSize:      12,
+		Salt:               salt,
+		RootHash:           root,
+		APKDigest:          contentDigest,
+		SignatureAlgorithm: SignatureECDSAWithSHA256,
+		rawCertificate:     signer.cert.Raw,
+	}
+	signature := signer.sign(t, sig.signedData(int64(len(data))))
+
+	hashingInfo := bytes.Join([][]byte{testUint32(v4HashSHA256), {12}, testLP(salt), testLP(root)}, nil)
+	signingInfo := bytes.Join([][]byte{
+		testLP(contentDigest),
+		testLP(signer.cert.Raw),
+		testLP(),
+		testLP(signer.cert.RawSubjectPublicKeyInfo),
+		testUint32(uint32(SignatureECDSAWithSHA256)),
+		testLP(signature),
+	}, nil)
+	idsig := bytes.Join([][]byte{testUint32(v4SignatureVersion), testLP(hashingInfo), testLP(signingInfo)}, nil)
+
+	parsed, err := ParseV4Signature(bytes.NewReader(idsig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Certificate.Equal(signer.cert) {
+		t.Error("unexpected certificate")
+	}
+	if err := apk.VerifyV4Signature(parsed); err != nil {
+		t.Errorf("want verified, got %v", err)
+	}
+
+	parsed.RootHash = append([]byte(nil), root...)
+	parsed.RootHash[0] ^= 0xff
+	if err := apk.VerifyV4Signature(parsed); err == nil {
+		t.Error("want error for the tampered root hash")
+	}
+}
+
+func TestComputeMerkleRootHash(t *testing.T) {
+	// a file smaller than a block has a single leaf, so the root is the digest of the padded leaf digest.
+	data := []byte("hello")
+	leaf := make([]byte, 4096)
+	copy(leaf, data)
+	leafDigest := sha256.Sum256(leaf)
+	top := make([]byte, 4096)
+	copy(top, leafDigest[:])
+	want := sha256.Sum256(top)
+
+	got, err := computeMerkleRootHash(crypto.SHA256, bytes.NewReader(data), 4096, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[:]) {
+		t.Errorf("want %x, got %x", want, got)
+	}
+
+	// 129 blocks need two levels because their digests don't fit in a block.
+	zero := sha256.Sum256(make([]byte, 4096))
+	level1 := make([]byte, 2*4096)
+	for i := 0; i < 129; i++ {
+		copy(level1[i*sha256.Size:], zero[:])
+	}
+	first := sha256.Sum256(level1[:4096])
+	second := sha256.Sum256(level1[4096:])
+	level2 := make([]byte, 4096)
+	copy(level2, first[:])
+	copy(level2[sha256.Size:], second[:])
+	want = sha256.Sum256(level2)
+
+	got, err = computeMerkleRootHash(crypto.SHA256, bytes.NewReader(make([]byte, 129*4096)), 4096, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[:]) {
+		t.Errorf("want %x, got %x", want, got)
+	}
+}
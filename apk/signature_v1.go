@@ -0,0 +1,396 @@
+package apk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const jarManifestPath = "META-INF/MANIFEST.MF"
+
+// jarDigestAlgorithms is the digest algorithms in the names of the digest attributes, e.g. "SHA-256-Digest".
+var jarDigestAlgorithms = map[string]crypto.Hash{
+	"sha1":    crypto.SHA1,
+	"sha-1":   crypto.SHA1,
+	"sha-256": crypto.SHA256,
+	"sha-384": crypto.SHA384,
+	"sha-512": crypto.SHA512,
+}
+
+// jarManifestSection is a section of MANIFEST.MF or a signature file.
+type jarManifestSection struct {
+	// raw is the bytes of the section including the terminating empty line.
+	raw []byte
+
+	// attrs is the attributes of the section. The keys are in lower case.
+	attrs map[string]string
+}
+
+func (s *jarManifestSection) get(name string) string {
+	return s.attrs[strings.ToLower(name)]
+}
+
+// parseJARManifest parses the main section and the per-entry sections of the manifest or a signature file.
+// The per-entry sections are indexed by the Name attribute.
+func parseJARManifest(data []byte) (*jarManifestSection, map[string]*jarManifestSection, error) {
+	var sections []*jarManifestSection
+	var section *jarManifestSection
+	var lastKey string
+	start := 0
+	for pos := 0; pos < len(data); {
+		// find the end of the line
+		end := pos
+		for end < len(data) && data[end] != '\r' && data[end] != '\n' {
+			end++
+		}
+		line := string(data[pos:end])
+		next := end
+		if next < len(data) && data[next] == '\r' {
+			next++
+		}
+		if next < len(data) && data[next] == '\n' {
+			next++
+		}
+
+		switch {
+		case line == "":
+			if section != nil {
+				section.raw = data[start:next]
+				sections = append(sections, section)
+				section = nil
+			}
+			start = next
+		case line[0] == ' ':
+			if section == nil || lastKey == "" {
+				return nil, nil, errors.New("apk: unexpected continuation line in the manifest")
+			}
+			section.attrs[lastKey] += line[1:]
+		default:
+			idx := strings.Index(line, ": ")
+			if idx <= 0 {
+				return nil, nil, fmt.Errorf("apk: invalid manifest line: %q", line)
+			}
+			if section == nil {
+				section = &jarManifestSection{attrs: make(map[string]string)}
+			}
+			lastKey = strings.ToLower(line[:idx])
+			section.attrs[lastKey] = line[idx+2:]
+		}
+		pos = next
+	}
+	if section != nil {
+		section.raw = data[start:]
+		sections = append(sections, section)
+	}
+
+	if len(sections) == 0 {
+		return nil, nil, errors.New("apk: empty manifest")
+	}
+	entries := make(map[string]*jarManifestSection, len(sections)-1)
+	for _, s := range sections[1:] {
+		name := s.get("Name")
+		if name == "" {
+			return nil, nil, errors.New("apk: manifest section without Name attribute")
+		}
+		if _, ok := entries[name]; ok {
+			return nil, nil, fmt.Errorf("apk: duplicate manifest section: %s", name)
+		}
+		entries[name] = s
+	}
+	return sections[0], entries, nil
+}
+
+// verifyJARDigests verifies the digest attributes that end with suffix, e.g. "-Digest".
+// It returns false if the section has no digest attributes of supported algorithms.
+func verifyJARDigests(section *jarManifestSection, suffix string, data []byte) (supported, matched bool) {
+	suffix = strings.ToLower(suffix)
+	matched = true
+	for key, value := range section.attrs {
+		if !strings.HasSuffix(key, suffix) {
+			continue
+		}
+		hash, ok := jarDigestAlgorithms[strings.TrimSuffix(key, suffix)]
+		if !ok {
+			continue
+		}
+		want, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return true, false
+		}
+		h := hash.New()
+		h.Write(data)
+		supported = true
+		matched = matched && bytes.Equal(h.Sum(nil), want)
+	}
+	return supported, supported && matched
+}
+
+// isJARSignatureBlock reports whether name is a signature block file, e.g. "META-INF/CERT.RSA".
+func isJARSignatureBlock(name string) bool {
+	if !strings.HasPrefix(name, "META-INF/") || strings.Contains(name[len("META-INF/"):], "/") {
+		return false
+	}
+	return strings.HasSuffix(name, ".RSA") || strings.HasSuffix(name, ".DSA") || strings.HasSuffix(name, ".EC")
+}
+
+// isJARSignatureFile reports whether name is a file of the JAR signing itself,
+// e.g. "META-INF/MANIFEST.MF" and "META-INF/CERT.SF".
+func isJARSignatureFile(name string) bool {
+	if !strings.HasPrefix(name, "META-INF/") || strings.Contains(name[len("META-INF/"):], "/") {
+		return false
+	}
+	lower := strings.ToLower(name[len("META-INF/"):])
+	return lower == "manifest.mf" ||
+		strings.HasSuffix(lower, ".sf") ||
+		strings.HasSuffix(lower, ".rsa") ||
+		strings.HasSuffix(lower, ".dsa") ||
+		strings.HasSuffix(lower, ".ec") ||
+		strings.HasPrefix(lower, "sig-")
+}
+
+// needsJARDigest reports whether the entry must be listed in the manifest.
+// Directories and the files of the JAR signing itself are not listed.
+func needsJARDigest(name string) bool {
+	return !strings.HasSuffix(name, "/") && !isJARSignatureFile(name)
+}
+
+// JARSigner is a signer of the JAR signing.
+type JARSigner struct {
+	// Name is the name of the signer, e.g. "CERT".
+	Name string
+
+	// SignatureFile is the path of the signature file, e.g. "META-INF/CERT.SF".
+	SignatureFile string
+
+	// SignatureBlock is the path of the signature block file, e.g. "META-INF/CERT.RSA".
+	SignatureBlock string
+
+	// Certificates is the certificates in the signature block. The first certificate is the signing certificate.
+	Certificates []*x509.Certificate
+
+	// APKSigned is the newer signature schemes listed in the X-Android-APK-Signed attribute.
+	APKSigned []SignatureScheme
+
+	// Errors is the errors found in verifying the signer.
+	Errors []error
+
+	// covered is the entries the signature file covers. It is nil if the signature file covers the whole manifest.
+	covered map[string]bool
+}
+
+// Verified returns whether the signer is verified.
+func (s *JARSigner) Verified() bool {
+	return len(s.Errors) == 0
+}
+
+func (s *JARSigner) errorf(format string, args ...interface{}) {
+	s.Errors = append(s.Errors, fmt.Errorf(format, args...))
+}
+
+func (s *JARSigner) covers(name string) bool {
+	return s.covered == nil || s.covered[name]
+}
+
+// JARVerification is the result of verifying the JAR signing.
+type JARVerification struct {
+	Signers []*JARSigner
+
+	// UnsignedEntries is the entries that are not covered by all signers.
+	UnsignedEntries []string
+
+	// TamperedEntries is the entries whose contents don't match the digests in the manifest.
+	TamperedEntries []string
+
+	// MissingEntries is the entries listed in the manifest but not found in the APK.
+	MissingEntries []string
+}
+
+// Verified returns whether all signers are verified and all entries are signed.
+func (v *JARVerification) Verified() bool {
+	if len(v.Signers) == 0 || len(v.UnsignedEntries) != 0 || len(v.TamperedEntries) != 0 || len(v.MissingEntries) != 0 {
+		return false
+	}
+	for _, s := range v.Signers {
+		if !s.Verified() {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyJARSignature verifies the JAR signing (APK Signature Scheme v1) in META-INF/.
+// It returns ErrNoJARSignature if the APK has no signature block files.
+// Verification failures are reported in the result, not as an error.
+func (k *Apk) VerifyJARSignature() (*JARVerification, error) {
+	manifestData, err := k.readZipFile(jarManifestPath)
+	if err != nil {
+		return nil, ErrNoJARSignature
+	}
+	_, entries, err := parseJARManifest(manifestData)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(JARVerification)
+	for _, file := range k.zipreader.File {
+		if !isJARSignatureBlock(file.Name) {
+			continue
+		}
+		signer, err := k.verifyJARSigner(file.Name, manifestData, entries)
+		if err != nil {
+			return nil, err
+		}
+		result.Signers = append(result.Signers, signer)
+	}
+	if len(result.Signers) == 0 {
+		return nil, ErrNoJARSignature
+	}
+
+	found := make(map[string]bool, len(k.zipreader.File))
+	for _, file := range k.zipreader.File {
+		found[file.Name] = true
+		if !needsJARDigest(file.Name) {
+			continue
+		}
+		section, ok := entries[file.Name]
+		if !ok {
+			result.UnsignedEntries = append(result.UnsignedEntries, file.Name)
+			continue
+		}
+		data, err := k.readZipFile(file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, matched := verifyJARDigests(section, "-Digest", data); !matched {
+			result.TamperedEntries = append(result.TamperedEntries, file.Name)
+			continue
+		}
+		for _, s := range result.Signers {
+			if !s.covers(file.Name) {
+				result.UnsignedEntries = append(result.UnsignedEntries, file.Name)
+				break
+			}
+		}
+	}
+	for name := range entries {
+		if !found[name] {
+			result.MissingEntries = append(result.MissingEntries, name)
+		}
+	}
+	sort.Strings(result.MissingEntries)
+	return result, nil
+}
+
+// verifyJARSigner verifies the signature block file and its signature file.
+func (k *Apk) verifyJARSigner(blockName string, manifestData []byte, entries map[string]*jarManifestSection) (*JARSigner, error) {
+	base := blockName[:strings.LastIndex(blockName, ".")]
+	signer := &JARSigner{
+		Name:           base[len("META-INF/"):],
+		SignatureFile:  base + ".SF",
+		SignatureBlock: blockName,
+	}
+
+	sf, err := k.readZipFile(signer.SignatureFile)
+	if err != nil {
+		signer.errorf("apk: signature file %s not found", signer.SignatureFile)
+		return signer, nil
+	}
+	block, err := k.readZipFile(blockName)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the signature of the signature file
+	sig, err := parsePKCS7(block)
+	if err != nil {
+		signer.errorf("apk: failed to parse %s: %w", blockName, err)
+		return signer, nil
+	}
+	if len(sig.signedData.SignerInfos) == 0 {
+		signer.errorf("apk: no signers in %s", blockName)
+		return signer, nil
+	}
+	cert, err := sig.verify(&sig.signedData.SignerInfos[0], sf)
+	if err != nil {
+		signer.errorf("apk: failed to verify %s: %w", blockName, err)
+		return signer, nil
+	}
+	signer.Certificates = append(signer.Certificates, cert)
+	for _, c := range sig.certificates {
+		if c != cert {
+			signer.Certificates = append(signer.Certificates, c)
+		}
+	}
+
+	// verify the digests of the manifest in the signature file
+	main, sections, err := parseJARManifest(sf)
+	if err != nil {
+		signer.errorf("apk: failed to parse %s: %w", signer.SignatureFile, err)
+		return signer, nil
+	}
+	k.checkAPKSigned(signer, main.get("X-Android-APK-Signed"))
+	if _, matched := verifyJARDigests(main, "-Digest-Manifest", manifestData); matched {
+		return signer, nil
+	}
+
+	// the manifest was modified after signing, e.g. entries were added.
+	// fall back to the digests of the individual sections.
+	signer.covered = make(map[string]bool, len(sections))
+	for name, section := range sections {
+		entry, ok := entries[name]
+		if !ok {
+			continue
+		}
+		if _, matched := verifyJARDigests(section, "-Digest", entry.raw); !matched {
+			signer.errorf("apk: the digest of %s in %s doesn't match the manifest", name, signer.SignatureFile)
+			continue
+		}
+		signer.covered[name] = true
+	}
+	return signer, nil
+}
+
+// checkAPKSigned parses the X-Android-APK-Signed attribute,
+// and checks whether the signatures of the newer schemes are stripped.
+func (k *Apk) checkAPKSigned(signer *JARSigner, attr string) {
+	if attr == "" {
+		return
+	}
+	for _, v := range strings.Split(attr, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		signer.APKSigned = append(signer.APKSigned, SignatureScheme(id))
+	}
+	if k.r == nil {
+		return
+	}
+
+	var pairs map[uint32][]byte
+	if sections, err := findZipSections(k.r, k.size); err == nil {
+		if block, err := findSigningBlock(k.r, sections); err == nil {
+			pairs = block.pairs
+		}
+	}
+	for _, scheme := range signer.APKSigned {
+		var id uint32
+		switch scheme {
+		case SignatureSchemeV2:
+			id = signatureSchemeV2BlockID
+		case SignatureSchemeV3:
+			id = signatureSchemeV3BlockID
+		default:
+			continue
+		}
+		if _, ok := pairs[id]; !ok {
+			signer.errorf("apk: the APK was signed with %v, but the signature was stripped", scheme)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+const longEntryName = "res/raw/a_very_long_file_name_that_needs_a_continuation_line_in_the_manifest.txt"
+
+// openTestJAR opens testdata/v1signed.zip, which is signed by openssl cms.
+// modify rewrites the contents of the entries, and extra is added to the zip.
+func openTestJAR(t *testing.T, modify func(name string, data []byte) []byte, extra map[string][]byte) *Apk {
+	t.Helper()
+	r, err := zip.OpenReader("testdata/v1signed.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	write := func(name string, data []byte) {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range r.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if modify != nil {
+			data = modify(file.Name, data)
+		}
+		if data != nil {
+			write(file.Name, data)
+		}
+	}
+	for name, data := range extra {
+		write(name, data)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ra := bytes.NewReader(buf.Bytes())
+	zr, err := zip.NewReader(ra, int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Apk{r: ra, size: int64(buf.Len()), zipreader: zr}
+}
+
+func TestVerifyJARSignature(t *testing.T) {
+	result, err := openTestJAR(t, nil, nil).VerifyJARSignature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Verified() {
+		for _, s := range result.Signers {
+			t.Log(s.Errors)
+		}
+		t.Fatalf("want verified, got %+v", result)
+	}
+	if len(result.Signers) != 1 {
+		t.Fatalf("want 1 signer, got %d", len(result.Signers))
+	}
+	signer := result.Signers[0]
+	if signer.Name != "CERT" || signer.SignatureFile != "META-INF/CERT.SF" {
+		t.Errorf("unexpected signer: %+v", signer)
+	}
+	if len(signer.Certificates) != 1 || signer.Certificates[0].Subject.CommonName != "v1 test" {
+		t.Errorf("unexpected certificates: %v", signer.Certificates)
+	}
+}
+
+func TestVerifyJARSignatureFailure(t *testing.T) {
+	t.Run("tampered", func(t *testing.T) {
+		apk := openTestJAR(t, func(name string, data []byte) []byte {
+			if name == "hello.txt" {
+				return []byte("Hello, Gopher!\n")
+			}
+			return data
+		}, nil)
+		result, err := apk.VerifyJARSignature()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"hello.txt"}; !reflect.DeepEqual(result.TamperedEntries, want) {
+			t.Errorf("want %v, got %v", want, result.TamperedEntries)
+		}
+		if result.Verified() {
+			t.Error("want not verified")
+		}
+	})
+
+	t.Run("added", func(t *testing.T) {
+		apk := openTestJAR(t, nil, map[string][]byte{"evil.dex": []byte("evil")})
+		result, err := apk.VerifyJARSignature()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"evil.dex"}; !reflect.DeepEqual(result.UnsignedEntries, want) {
+			t.Errorf("want %v, got %v", want, result.UnsignedEntries)
+		}
+	})
+
+	t.Run("added to manifest", func(t *testing.T) {
+		// the digest of the whole manifest no longer matches,
+		// so the signature file is verified section by section.
+		apk := openTestJAR(t, func(name string, data []byte) []byte {
+			if name == jarManifestPath {
+				digest := sha256.Sum256([]byte("evil"))
+				section := "Name: evil.dex\r\nSHA-256-Digest: " + base64.StdEncoding.EncodeToString(digest[:]) + "\r\n\r\n"
+				return append(data, section...)
+			}
+			return data
+		}, map[string][]byte{"evil.dex": []byte("evil")})
+		result, err := apk.VerifyJARSignature()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.TamperedEntries) != 0 {
+			t.Errorf("want no tampered entries, got %v", result.TamperedEntries)
+		}
+		if want := []string{"evil.dex"}; !reflect.DeepEqual(result.UnsignedEntries, want) {
+			t.Errorf("want %v, got %v", want, result.UnsignedEntries)
+		}
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		apk := openTestJAR(t, func(name string, data []byte) []byte {
+			if name == longEntryName {
+				return nil
+			}
+			return data
+		}, nil)
+		result, err := apk.VerifyJARSignature()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{longEntryName}; !reflect.DeepEqual(result.MissingEntries, want) {
+			t.Errorf("want %v, got %v", want, result.MissingEntries)
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		apk := openTestJAR(t, func(name string, data []byte) []byte {
+			if name == "META-INF/CERT.SF" {
+				return bytes.Replace(data, []byte("Created-By: 1.0"), []byte("Created-By: 2.0"), 1)
+			}
+			return data
+		}, nil)
+		result, err := apk.VerifyJARSignature()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Signers[0].Verified() {
+			t.Error("want the signer not verified")
+		}
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		apk := openTestJAR(t, func(name string, data []byte) []byte {
+			if name == "META-INF/CERT.RSA" {
+				return nil
+			}
+			return data
+		}, nil)
+		if _, err := apk.VerifyJARSignature(); !errors.Is(err, ErrNoJARSignature) {
+			t.Errorf("want ErrNoJARSignature, got %v", err)
+		}
+	})
+}
+
+func TestParseJARManifest(t *testing.T) {
+	data := "Manifest-Version: 1.0\r\n\r\nName: res/raw/a_very_long_file_name_that_needs_a_continuation_line_in_th\r\n e_manifest.txt\r\nSHA-256-Digest: abc\r\n\r\nName: b\nsha-256-digest: def\n"
+	main, entries, err := parseJARManifest([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if main.get("Manifest-Version") != "1.0" {
+		t.Errorf("unexpected main section: %v", main.attrs)
+	}
+	long, ok := entries[longEntryName]
+	if !ok {
+		t.Fatalf("section %s not found: %v", longEntryName, entries)
+	}
+	if long.get("SHA-256-Digest") != "abc" {
+		t.Errorf("unexpected section: %v", long.attrs)
+	}
+	if !bytes.HasSuffix(long.raw, []byte("SHA-256-Digest: abc\r\n\r\n")) {
+		t.Errorf("unexpected raw section: %q", long.raw)
+	}
+	if b := entries["b"]; b == nil || b.get("SHA-256-Digest") != "def" || string(b.raw) != "Name: b\nsha-256-digest: def\n" {
+		t.Errorf("unexpected section: %+v", b)
+	}
+}
@@ -0,0 +1,260 @@
+package apk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// v4SignatureVersion is the supported version of the .idsig file format.
+	v4SignatureVersion = 2
+
+	// v4HashSHA256 is the hash algorithm ID of SHA-256 in the hashing info.
+	v4HashSHA256 = 1
+
+	// maxV4FieldSize is the limit of the size of a field in the .idsig file.
+	maxV4FieldSize = 1 << 20
+)
+
+// V4Signature is an APK Signature Scheme v4 signature, stored in a .idsig file next to the APK.
+type V4Signature struct {
+	// Version is the version of the file format.
+	Version uint32
+
+	// HashAlgorithm is the hash algorithm of the Merkle tree. Only SHA-256 (1) is defined.
+	HashAlgorithm uint32
+
+	// Log2BlockSize is the base-2 logarithm of the block size of the Merkle tree.
+	Log2BlockSize uint8
+
+	// Salt is the salt of the Merkle tree.
+	Salt []byte
+
+	// RootHash is the root hash of the Merkle tree over the whole APK.
+	RootHash []byte
+
+	// APKDigest is a content digest of the APK Signature Scheme v2 or v3 signer.
+	APKDigest []byte
+
+	// Certificate is the signing certificate.
+	Certificate *x509.Certificate
+
+	// AdditionalData is the additional data of the signature.
+	AdditionalData []byte
+
+	// PublicKey is the public key of the signer.
+	PublicKey crypto.PublicKey
+
+	// SignatureAlgorithm is the algorithm of the signature.
+	SignatureAlgorithm SignatureAlgorithm
+
+	// Signature is the signature over the hashing info and the signing info.
+	Signature []byte
+
+	rawCertificate []byte
+	rawPublicKey   []byte
+}
+
+// ParseV4Signature parses a .idsig file of the APK Signature Scheme v4.
+func ParseV4Signature(r io.Reader) (*V4Signature, error) {
+	var version uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != v4SignatureVersion {
+		return nil, fmt.Errorf("apk: unsupported v4 signature version: %d", version)
+	}
+	hashingInfo, err := readV4Field(r)
+	if err != nil {
+		return nil, err
+	}
+	signingInfo, err := readV4Field(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := &V4Signature{Version: version}
+
+	hr := &signatureReader{data: hashingInfo}
+	if sig.HashAlgorithm, err = hr.uint32(); err != nil {
+		return nil, err
+	}
+	if hr.len() < 1 {
+		return nil, errTruncatedSignature
+	}
+	sig.Log2BlockSize = hr.data[0]
+	hr.data = hr.data[1:]
+	if sig.Salt, err = hr.bytes(); err != nil {
+		return nil, err
+	}
+	if sig.RootHash, err = hr.bytes(); err != nil {
+		return nil, err
+	}
+
+	// The signing info may be followed by additional signing info blocks, which are ignored.
+	sr := &signatureReader{data: signingInfo}
+	if sig.APKDigest, err = sr.bytes(); err != nil {
+		return nil, err
+	}
+	if sig.rawCertificate, err = sr.bytes(); err != nil {
+		return nil, err
+	}
+	if sig.AdditionalData, err = sr.bytes(); err != nil {
+		return nil, err
+	}
+	if sig.rawPublicKey, err = sr.bytes(); err != nil {
+		return nil, err
+	}
+	alg, err := sr.uint32()
+	if err != nil {
+		return nil, err
+	}
+	sig.SignatureAlgorithm = SignatureAlgorithm(alg)
+	if sig.Signature, err = sr.bytes(); err != nil {
+		return nil, err
+	}
+
+	if sig.Certificate, err = x509.ParseCertificate(sig.rawCertificate); err != nil {
+		return nil, err
+	}
+	if sig.PublicKey, err = x509.ParsePKIXPublicKey(sig.rawPublicKey); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func readV4Field(r io.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return nil, err
+	}
+	if size > maxV4FieldSize {
+		return nil, fmt.Errorf("apk: too large v4 signature field: %d bytes", size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// signedData returns the data that the v4 signature signs.
+func (sig *V4Signature) signedData(fileSize int64) []byte {
+	var buf bytes.Buffer
+	writeBytes := func(b []byte) {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(b)))
+		buf.Write(b)
+	}
+	size := 4 + 8 + 4 + 1 +
+		4 + len(sig.Salt) + 4 + len(sig.RootHash) +
+		4 + len(sig.APKDigest) + 4 + len(sig.rawCertificate) + 4 + len(sig.AdditionalData)
+	binary.Write(&buf, binary.LittleEndian, uint32(size))
+	binary.Write(&buf, binary.LittleEndian, uint64(fileSize))
+	binary.Write(&buf, binary.LittleEndian, sig.HashAlgorithm)
+	buf.WriteByte(sig.Log2BlockSize)
+	writeBytes(sig.Salt)
+	writeBytes(sig.RootHash)
+	writeBytes(sig.APKDigest)
+	writeBytes(sig.rawCertificate)
+	writeBytes(sig.AdditionalData)
+	return buf.Bytes()
+}
+
+// VerifyV4Signature verifies the APK Signature Scheme v4 signature of the APK.
+// It verifies the signature, the root hash of the Merkle tree over the APK,
+// and that the signature belongs to a verified v3 or v2 signer of the APK.
+func (k *Apk) VerifyV4Signature(sig *V4Signature) error {
+	if k.r == nil {
+		return ErrNoReader
+	}
+	if !bytes.Equal(sig.Certificate.RawSubjectPublicKeyInfo, sig.rawPublicKey) {
+		return errors.New("apk: the public key doesn't match the v4 signing certificate")
+	}
+	if err := sig.SignatureAlgorithm.verify(sig.PublicKey, sig.signedData(k.size), sig.Signature); err != nil {
+		return fmt.Errorf("apk: failed to verify the v4 signature: %w", err)
+	}
+
+	if sig.HashAlgorithm != v4HashSHA256 {
+		return fmt.Errorf("apk: unsupported v4 hash algorithm: %d", sig.HashAlgorithm)
+	}
+	if sig.Log2BlockSize < 9 || sig.Log2BlockSize > 16 {
+		return fmt.Errorf("apk: unsupported v4 block size: 2^%d", sig.Log2BlockSize)
+	}
+	root, err := computeMerkleRootHash(crypto.SHA256, io.NewSectionReader(k.r, 0, k.size), 1<<sig.Log2BlockSize, sig.Salt)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, sig.RootHash) {
+		return errors.New("apk: the root hash of the v4 signature doesn't match the APK")
+	}
+
+	verification, err := k.VerifySignatures()
+	if err != nil {
+		return err
+	}
+	signers := append(append([]*Signer{}, verification.V3...), verification.V2...)
+	for _, s := range signers {
+		if !s.Verified() || len(s.Certificates) == 0 || !s.Certificates[0].Equal(sig.Certificate) {
+			continue
+		}
+		for _, d := range s.Digests {
+			if bytes.Equal(d.Value, sig.APKDigest) {
+				return nil
+			}
+		}
+	}
+	return errors.New("apk: the v4 signature doesn't match any verified v3 or v2 signer")
+}
+
+// computeMerkleRootHash computes the root hash of the fs-verity compatible Merkle tree over r.
+// Each level is the digests of the blocks of the lower level, zero-padded to the block size.
+func computeMerkleRootHash(hash crypto.Hash, r io.Reader, blockSize int, salt []byte) ([]byte, error) {
+	h := hash.New()
+	digestBlock := func(block []byte) []byte {
+		h.Reset()
+		h.Write(salt)
+		h.Write(block)
+		return h.Sum(nil)
+	}
+
+	// the bottom level is the digests of the data
+	var level []byte
+	buf := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			for i := n; i < len(buf); i++ {
+				buf[i] = 0
+			}
+			level = append(level, digestBlock(buf)...)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for len(level) > blockSize {
+		level = padToBlockSize(level, blockSize)
+		next := make([]byte, 0, len(level)/blockSize*hash.Size())
+		for i := 0; i < len(level); i += blockSize {
+			next = append(next, digestBlock(level[i:i+blockSize])...)
+		}
+		level = next
+	}
+	return digestBlock(padToBlockSize(level, blockSize)), nil
+}
+
+func padToBlockSize(b []byte, blockSize int) []byte {
+	if rem := len(b) % blockSize; rem != 0 || len(b) == 0 {
+		b = append(b, make([]byte, blockSize-rem)...)
+	}
+	return b
+}
@@ -0,0 +1,192 @@
+package apk
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	eocdSignature      = 0x06054b50
+	eocdMinSize        = 22
+	eocdMaxCommentSize = 0xffff
+	eocdCDSizeOffset   = 12
+	eocdCDOffsetOffset = 16
+
+	signingBlockMagic      = "APK Sig Block 42"
+	signingBlockFooterSize = 8 + len(signingBlockMagic)
+	signingBlockMinSize    = 8 + signingBlockFooterSize
+
+	// IDs of the ID-value pairs in the APK Signing Block.
+	signatureSchemeV2BlockID  = 0x7109871a
+	signatureSchemeV3BlockID  = 0xf05368c0
+	signatureSchemeV31BlockID = 0x1b93ad61
+	verityPaddingBlockID      = 0x42726577
+
+	contentDigestChunkSize = 1 << 20
+)
+
+// zipSections is the location of the sections of a zip file.
+type zipSections struct {
+	// cdOffset and cdSize are the offset and the size of the central directory.
+	cdOffset int64
+	cdSize   int64
+
+	// eocdOffset is the offset of the end of central directory record.
+	eocdOffset int64
+
+	// eocd is the end of central directory record.
+	eocd []byte
+}
+
+// findZipSections finds the end of central directory record and the central directory.
+func findZipSections(r io.ReaderAt, size int64) (*zipSections, error) {
+	if size < eocdMinSize {
+		return nil, errors.New("apk: too small zip file")
+	}
+
+	// the end of central directory record is at the end of the file,
+	// followed by a comment of up to 65535 bytes.
+	bufSize := int64(eocdMinSize + eocdMaxCommentSize)
+	if bufSize > size {
+		bufSize = size
+	}
+	buf := make([]byte, bufSize)
+	if _, err := r.ReadAt(buf, size-bufSize); err != nil && err != io.EOF {
+		return nil, err
+	}
+	for i := len(buf) - eocdMinSize; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(buf[i:]) != eocdSignature {
+			continue
+		}
+		commentSize := int(binary.LittleEndian.Uint16(buf[i+eocdMinSize-2:]))
+		if i+eocdMinSize+commentSize != len(buf) {
+			continue
+		}
+		eocd := buf[i:]
+		s := &zipSections{
+			cdOffset:   int64(binary.LittleEndian.Uint32(eocd[eocdCDOffsetOffset:])),
+			cdSize:     int64(binary.LittleEndian.Uint32(eocd[eocdCDSizeOffset:])),
+			eocdOffset: size - bufSize + int64(i),
+			eocd:       eocd,
+		}
+		if s.cdOffset+s.cdSize != s.eocdOffset {
+			return nil, fmt.Errorf("apk: the central directory (offset %d, size %d) is not followed by the end of central directory record (offset %d)", s.cdOffset, s.cdSize, s.eocdOffset)
+		}
+		return s, nil
+	}
+	return nil, errors.New("apk: end of central directory record not found")
+}
+
+// signingBlock is an APK Signing Block.
+type signingBlock struct {
+	// offset is the offset of the APK Signing Block.
+	offset int64
+
+	// pairs is the ID-value pairs in the block.
+	pairs map[uint32][]byte
+}
+
+// findSigningBlock finds the APK Signing Block immediately before the central directory.
+func findSigningBlock(r io.ReaderAt, sections *zipSections) (*signingBlock, error) {
+	if sections.cdOffset < int64(signingBlockMinSize) {
+		return nil, ErrNoSigningBlock
+	}
+
+	footer := make([]byte, signingBlockFooterSize)
+	if _, err := r.ReadAt(footer, sections.cdOffset-int64(signingBlockFooterSize)); err != nil {
+		return nil, err
+	}
+	if string(footer[8:]) != signingBlockMagic {
+		return nil, ErrNoSigningBlock
+	}
+	blockSize := binary.LittleEndian.Uint64(footer)
+	if blockSize < uint64(signingBlockFooterSize) || blockSize > uint64(sections.cdOffset-8) {
+		return nil, fmt.Errorf("apk: invalid APK Signing Block size: %d", blockSize)
+	}
+
+	offset := sections.cdOffset - int64(blockSize) - 8
+	block := make([]byte, blockSize+8)
+	if _, err := r.ReadAt(block, offset); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint64(block) != blockSize {
+		return nil, errors.New("apk: APK Signing Block sizes in header and footer do not match")
+	}
+
+	pairs := make(map[uint32][]byte)
+	data := block[8 : len(block)-signingBlockFooterSize]
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, errors.New("apk: insufficient data to read the size of an ID-value pair")
+		}
+		size := binary.LittleEndian.Uint64(data)
+		data = data[8:]
+		if size < 4 || size > uint64(len(data)) {
+			return nil, fmt.Errorf("apk: invalid ID-value pair size: %d", size)
+		}
+		id := binary.LittleEndian.Uint32(data)
+		pairs[id] = data[4:size]
+		data = data[size:]
+	}
+	return &signingBlock{
+		offset: offset,
+		pairs:  pairs,
+	}, nil
+}
+
+// contentSections returns the sections of the APK that are protected by the APK Signature Scheme v2 and v3.
+// These are the zip entries, the central directory and the end of central directory record.
+// The offset of the central directory in the end of central directory record is replaced with
+// the offset of the APK Signing Block, as if the APK had no signing block.
+func contentSections(r io.ReaderAt, sections *zipSections, signingBlockOffset int64) []io.Reader {
+	eocd := make([]byte, len(sections.eocd))
+	copy(eocd, sections.eocd)
+	binary.LittleEndian.PutUint32(eocd[eocdCDOffsetOffset:], uint32(signingBlockOffset))
+	return []io.Reader{
+		io.NewSectionReader(r, 0, signingBlockOffset),
+		io.NewSectionReader(r, sections.cdOffset, sections.cdSize),
+		bytes.NewReader(eocd),
+	}
+}
+
+// computeChunkedDigest computes the digest of the sections with 1 MB chunks.
+// Each chunk is digested separately, and the top-level digest is computed over the chunk digests.
+func computeChunkedDigest(hash crypto.Hash, sections []io.Reader) ([]byte, error) {
+	var digests bytes.Buffer
+	var count uint32
+	buf := make([]byte, contentDigestChunkSize)
+	h := hash.New()
+	for _, section := range sections {
+		for {
+			n, err := io.ReadFull(section, buf)
+			if n > 0 {
+				var prefix [5]byte
+				prefix[0] = 0xa5
+				binary.LittleEndian.PutUint32(prefix[1:], uint32(n))
+				h.Reset()
+				h.Write(prefix[:])
+				h.Write(buf[:n])
+				digests.Write(h.Sum(nil))
+				count++
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var prefix [5]byte
+	prefix[0] = 0x5a
+	binary.LittleEndian.PutUint32(prefix[1:], count)
+	h.Reset()
+	h.Write(prefix[:])
+	h.Write(digests.Bytes())
+	return h.Sum(nil), nil
+}
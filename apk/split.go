@@ -0,0 +1,405 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// splitOpenOptions is the options for opening split APKs.
+// The split APKs of native libraries have no resources.arsc.
+var splitOpenOptions = &OpenOptions{AllowMissingResources: true}
+
+// SplitSet is a base APK and its split APKs, which are installed together.
+// The resource tables of the APKs are merged, and the components of the feature splits
+// are merged into the manifest of the base APK, in the same way as PackageManager does.
+type SplitSet struct {
+	// Base is the base APK.
+	Base *Apk
+
+	// Splits is the split APKs, sorted by their split names.
+	Splits []*Apk
+
+	closer   io.Closer
+	manifest Manifest
+	table    *androidbinary.TableFile
+}
+
+// OpenSplitSet opens the split APKs specified by name.
+// name is a directory that contains the APKs, a .apks file created by bundletool,
+// or a .xapk file.
+//
+// The split APKs in the splits directory of a .apks file are opened, and the standalone APKs are ignored.
+// The other files are opened if they are in the top level of the archive and have the .apk extension.
+func OpenSplitSet(name string) (set *SplitSet, err error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return openSplitDir(name)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+		}
+	}()
+	set, err = OpenSplitSetZipReader(f, fi.Size())
+	if err != nil {
+		return nil, err
+	}
+	set.closer = f
+	return
+}
+
+// OpenSplitSetZipReader opens the split APKs in the archive, such as .apks and .xapk files.
+// It has same arguments like zip.NewReader.
+func OpenSplitSetZipReader(r io.ReaderAt, size int64) (*SplitSet, error) {
+	zipreader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var splits, others []*zip.File
+	for _, file := range zipreader.File {
+		if path.Ext(file.Name) != ".apk" {
+			continue
+		}
+		switch path.Dir(file.Name) {
+		case "splits":
+			splits = append(splits, file)
+		case ".":
+			others = append(others, file)
+		}
+	}
+	if len(splits) == 0 {
+		splits = others
+	}
+
+	apks := make([]*Apk, 0, len(splits))
+	for _, file := range splits {
+		apk, err := openNestedAPK(r, file)
+		if err != nil {
+			return nil, errorf("apk: failed to open %s: %w", file.Name, err)
+		}
+		apks = append(apks, apk)
+	}
+	return NewSplitSet(apks...)
+}
+
+// openNestedAPK opens the APK in an archive.
+// The APK is read directly from r if it is not compressed.
+func openNestedAPK(r io.ReaderAt, file *zip.File) (*Apk, error) {
+	if file.Method == zip.Store {
+		offset, err := file.DataOffset()
+		if err != nil {
+			return nil, err
+		}
+		size := int64(file.UncompressedSize64)
+		return OpenZipReaderWithOptions(io.NewSectionReader(r, offset, size), size, splitOpenOptions)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	return OpenZipReaderWithOptions(bytes.NewReader(data), int64(len(data)), splitOpenOptions)
+}
+
+func openSplitDir(dir string) (*SplitSet, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.apk"))
+	if err != nil {
+		return nil, err
+	}
+
+	apks := make([]*Apk, 0, len(names))
+	closeAll := func() {
+		for _, apk := range apks {
+			apk.Close()
+		}
+	}
+	for _, name := range names {
+		apk, err := OpenFileWithOptions(name, splitOpenOptions)
+		if err != nil {
+			closeAll()
+			return nil, errorf("apk: failed to open %s: %w", name, err)
+		}
+		apks = append(apks, apk)
+	}
+	set, err := NewSplitSet(apks...)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return set, nil
+}
+
+// NewSplitSet returns a new SplitSet of the APKs.
+// One of the APKs must be the base APK, and the others must be the split APKs of the same package and version.
+// The APKs are closed when the SplitSet is closed.
+func NewSplitSet(apks ...*Apk) (*SplitSet, error) {
+	set := new(SplitSet)
+	names := make(map[string]bool, len(apks))
+	for _, apk := range apks {
+		if err := apk.Parse(); err != nil {
+			return nil, err
+		}
+		split := apk.SplitName()
+		if split == "" {
+			if set.Base != nil {
+				return nil, ErrMultipleBaseAPKs
+			}
+			set.Base = apk
+			continue
+		}
+		if names[split] {
+			return nil, errorf("%w: %s", ErrDuplicateSplit, split)
+		}
+		names[split] = true
+		set.Splits = append(set.Splits, apk)
+	}
+	if set.Base == nil {
+		return nil, ErrNoBaseAPK
+	}
+	sort.Slice(set.Splits, func(i, j int) bool {
+		return set.Splits[i].SplitName() < set.Splits[j].SplitName()
+	})
+
+	packageName := set.Base.PackageName()
+	versionCode, err := set.Base.manifest.VersionCode.Int32()
+	if err != nil {
+		return nil, err
+	}
+	for _, split := range set.Splits {
+		name := split.SplitName()
+		if got := split.PackageName(); got != packageName {
+			return nil, errorf("%w: split %s has package %s, expected %s", ErrInconsistentSplit, name, got, packageName)
+		}
+		got, err := split.manifest.VersionCode.Int32()
+		if err != nil {
+			return nil, err
+		}
+		if got != versionCode {
+			return nil, errorf("%w: split %s has version code %d, expected %d", ErrInconsistentSplit, name, got, versionCode)
+		}
+	}
+
+	if err := set.merge(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// merge merges the resource tables and the manifests.
+func (s *SplitSet) merge() error {
+	tables := []*androidbinary.TableFile{s.Base.table}
+	for _, split := range s.Splits {
+		tables = append(tables, split.table)
+	}
+	s.table = androidbinary.MergeTableFiles(tables...)
+
+	if err := s.Base.decodeManifest(&s.manifest, s.table); err != nil {
+		return errorf("parse-manifest: %w", err)
+	}
+	for _, split := range s.Splits {
+		if !split.IsFeatureSplit() {
+			continue
+		}
+		var manifest Manifest
+		if err := split.decodeManifest(&manifest, s.table); err != nil {
+			return errorf("parse-manifest: split %s: %w", split.SplitName(), err)
+		}
+		mergeSplitApplication(&s.manifest.App, &manifest.App)
+	}
+	return nil
+}
+
+// mergeSplitApplication merges the components of the feature split into the application.
+func mergeSplitApplication(app, split *Application) {
+	app.Activities = append(app.Activities, split.Activities...)
+	app.ActivityAliases = append(app.ActivityAliases, split.ActivityAliases...)
+	app.Services = append(app.Services, split.Services...)
+	app.Receivers = append(app.Receivers, split.Receivers...)
+	app.UsesLibraries = append(app.UsesLibraries, split.UsesLibraries...)
+}
+
+// Close closes the APKs.
+func (s *SplitSet) Close() error {
+	var err error
+	for _, apk := range append([]*Apk{s.Base}, s.Splits...) {
+		if e := apk.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	if s.closer != nil {
+		if e := s.closer.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Manifest returns the manifest of the base APK, which the components of the feature splits are merged into.
+func (s *SplitSet) Manifest() Manifest {
+	return s.manifest
+}
+
+// Table returns the merged resource table.
+func (s *SplitSet) Table() *androidbinary.TableFile {
+	return s.table
+}
+
+// PackageName returns the package name.
+func (s *SplitSet) PackageName() string {
+	return s.manifest.Package.MustString()
+}
+
+// Label returns the label of the application.
+func (s *SplitSet) Label(resConfig *androidbinary.ResTableConfig) (label string, err error) {
+	label, err = s.manifest.App.Label.WithResTableConfig(resConfig).String()
+	if err != nil {
+		return
+	}
+	if androidbinary.IsResID(label) {
+		err = ErrLabelNotResolved
+	}
+	return
+}
+
+// Icon returns the icon image of the application.
+// The icon may be in a configuration split, such as split_config.xxhdpi.apk.
+func (s *SplitSet) Icon(resConfig *androidbinary.ResTableConfig) (image.Image, error) {
+	iconPath, err := s.manifest.App.Icon.WithResTableConfig(resConfig).String()
+	if err != nil {
+		return nil, err
+	}
+	if androidbinary.IsResID(iconPath) {
+		return nil, ErrIconNotResolved
+	}
+	for _, apk := range append([]*Apk{s.Base}, s.Splits...) {
+		if !apk.hasZipFile(iconPath) {
+			continue
+		}
+		imgData, err := apk.readZipFile(iconPath)
+		if err != nil {
+			return nil, err
+		}
+		m, _, err := image.Decode(bytes.NewReader(imgData))
+		return m, err
+	}
+	return nil, errorf("%w: %s", ErrFileNotFound, iconPath)
+}
+
+// SplitNames returns the names of the split APKs.
+func (s *SplitSet) SplitNames() []string {
+	names := make([]string, 0, len(s.Splits))
+	for _, split := range s.Splits {
+		names = append(names, split.SplitName())
+	}
+	return names
+}
+
+// Split returns the split APK of the name, or nil if not found.
+func (s *SplitSet) Split(name string) *Apk {
+	for _, split := range s.Splits {
+		if split.SplitName() == name {
+			return split
+		}
+	}
+	return nil
+}
+
+// MissingSplitTypes returns the split types that are required by the APKs but are provided by none of the split APKs.
+// PackageManager refuses to install the split set if it is not empty.
+func (s *SplitSet) MissingSplitTypes() []string {
+	provided := make(map[string]bool)
+	for _, split := range s.Splits {
+		for _, t := range split.SplitTypes() {
+			provided[t] = true
+		}
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, apk := range append([]*Apk{s.Base}, s.Splits...) {
+		for _, t := range apk.RequiredSplitTypes() {
+			if !provided[t] && !seen[t] {
+				missing = append(missing, t)
+				seen[t] = true
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// SplitName returns the split name of the APK, or the empty string if it is a base APK.
+func (k *Apk) SplitName() string {
+	return optionalString(k.manifest.Split)
+}
+
+// IsFeatureSplit returns whether the APK is a feature split.
+func (k *Apk) IsFeatureSplit() bool {
+	return k.manifest.IsFeatureSplit != nil && k.manifest.IsFeatureSplit.MustBool()
+}
+
+// ConfigForSplit returns the name of the feature split that the configuration split is for.
+// It returns the empty string if the APK is a configuration split for the base APK, or not a configuration split.
+func (k *Apk) ConfigForSplit() string {
+	return optionalString(k.manifest.ConfigForSplit)
+}
+
+// RequiredSplitTypes returns the split types that the APK requires.
+func (k *Apk) RequiredSplitTypes() []string {
+	return splitTypes(k.manifest.RequiredSplitTypes)
+}
+
+// SplitTypes returns the split types that the APK provides.
+func (k *Apk) SplitTypes() []string {
+	return splitTypes(k.manifest.SplitTypes)
+}
+
+func optionalString(v *androidbinary.String) string {
+	if v == nil {
+		return ""
+	}
+	s, err := v.String()
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// splitTypes parses the comma separated list of split types.
+func splitTypes(v *androidbinary.String) []string {
+	s := optionalString(v)
+	if s == "" {
+		return nil
+	}
+	var types []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
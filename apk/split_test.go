@@ -0,0 +1,358 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// encodeTestXML encodes the XML document into the binary XML format.
+// All attributes are encoded as raw strings.
+func encodeTestXML(t testing.TB, doc string) []byte {
+	t.Helper()
+	var strs []string
+	index := make(map[string]uint32)
+	ref := func(s string) uint32 {
+		if s == "" {
+			return 0xFFFFFFFF
+		}
+		if i, ok := index[s]; ok {
+			return i
+		}
+		index[s] = uint32(len(strs))
+		strs = append(strs, s)
+		return index[s]
+	}
+	u16 := func(buf *bytes.Buffer, v uint16) { binary.Write(buf, binary.LittleEndian, v) }
+	u32 := func(buf *bytes.Buffer, v uint32) { binary.Write(buf, binary.LittleEndian, v) }
+	node := func(buf *bytes.Buffer, typ androidbinary.ChunkType, size uint32) {
+		u16(buf, uint16(typ))
+		u16(buf, 16)
+		u32(buf, size)
+		u32(buf, 0)          // line number
+		u32(buf, 0xFFFFFFFF) // comment
+	}
+
+	var body bytes.Buffer
+	var namespaces []xml.Attr
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			var attrs []xml.Attr
+			for _, attr := range token.Attr {
+				if attr.Name.Space == "xmlns" {
+					namespaces = append(namespaces, attr)
+					// the reader treats the prefix of index 0 as undefined, so add the uri first.
+					uri := ref(attr.Value)
+					node(&body, androidbinary.ResXMLStartNamespaceType, 24)
+					u32(&body, ref(attr.Name.Local))
+					u32(&body, uri)
+					continue
+				}
+				attrs = append(attrs, attr)
+			}
+			uri := func(prefix string) string {
+				for _, ns := range namespaces {
+					if ns.Name.Local == prefix {
+						return ns.Value
+					}
+				}
+				return ""
+			}
+			node(&body, androidbinary.ResXMLStartElementType, uint32(36+20*len(attrs)))
+			u32(&body, ref(uri(token.Name.Space)))
+			u32(&body, ref(token.Name.Local))
+			u16(&body, 20) // attribute start
+			u16(&body, 20) // attribute size
+			u16(&body, uint16(len(attrs)))
+			u16(&body, 0)
+			u16(&body, 0)
+			u16(&body, 0)
+			for _, attr := range attrs {
+				value := ref(attr.Value)
+				u32(&body, ref(uri(attr.Name.Space)))
+				u32(&body, ref(attr.Name.Local))
+				u32(&body, value)
+				u16(&body, 8)
+				body.WriteByte(0)
+				body.WriteByte(byte(androidbinary.TypeString))
+				u32(&body, value)
+			}
+		case xml.CharData:
+			text := strings.TrimSpace(string(token))
+			if text == "" {
+				continue
+			}
+			node(&body, androidbinary.ResXMLCDataType, 28)
+			u32(&body, ref(text))
+			u16(&body, 8)
+			body.WriteByte(0)
+			body.WriteByte(byte(androidbinary.TypeNull))
+			u32(&body, 0)
+		case xml.EndElement:
+			node(&body, androidbinary.ResXMLEndElementType, 24)
+			u32(&body, 0xFFFFFFFF)
+			u32(&body, ref(token.Name.Local))
+		}
+	}
+
+	// the string pool in UTF-8
+	var data bytes.Buffer
+	for _, s := range strs {
+		data.WriteByte(byte(len(s)))
+		data.WriteByte(byte(len(s)))
+		data.WriteString(s)
+		data.WriteByte(0)
+	}
+	for data.Len()%4 != 0 {
+		data.WriteByte(0)
+	}
+	var pool bytes.Buffer
+	stringsStart := 28 + 4*len(strs)
+	u16(&pool, uint16(androidbinary.ResStringPoolChunkType))
+	u16(&pool, 28)
+	u32(&pool, uint32(stringsStart+data.Len()))
+	u32(&pool, uint32(len(strs)))
+	u32(&pool, 0)
+	u32(&pool, uint32(androidbinary.UTF8Flag))
+	u32(&pool, uint32(stringsStart))
+	u32(&pool, 0)
+	offset := 0
+	for _, s := range strs {
+		u32(&pool, uint32(offset))
+		offset += len(s) + 3
+	}
+	pool.Write(data.Bytes())
+
+	var buf bytes.Buffer
+	u16(&buf, uint16(androidbinary.ResXMLChunkType))
+	u16(&buf, 8)
+	u32(&buf, uint32(8+pool.Len()+body.Len()))
+	buf.Write(pool.Bytes())
+	buf.Write(body.Bytes())
+	return buf.Bytes()
+}
+
+func newTestZip(t testing.TB, files map[string][]byte, method uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const xxhdpiIcon = "res/mipmap-xxhdpi-v4/ic_launcher.png"
+
+// newTestSplits returns the split APKs of helloworld.apk.
+// The xxhdpi icon is moved into the configuration split.
+func newTestSplits(t *testing.T) map[string][]byte {
+	t.Helper()
+	r, err := zip.OpenReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	base := make(map[string][]byte)
+	for _, file := range r.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		base[file.Name] = data
+	}
+	icon := base[xxhdpiIcon]
+	delete(base, xxhdpiIcon)
+
+	const ns = `xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.helloworld" android:versionCode="1"`
+	return map[string][]byte{
+		"base-master.apk": newTestZip(t, base, zip.Deflate),
+		"base-xxhdpi.apk": newTestZip(t, map[string][]byte{
+			"AndroidManifest.xml": encodeTestXML(t, `<manifest `+ns+` split="config.xxhdpi" android:splitTypes="density"></manifest>`),
+			xxhdpiIcon:            icon,
+		}, zip.Store),
+		"feature-master.apk": newTestZip(t, map[string][]byte{
+			"AndroidManifest.xml": encodeTestXML(t, `<manifest `+ns+` split="feature" android:isFeatureSplit="true" android:requiredSplitTypes="density,abi">
+	<uses-split android:name="base"></uses-split>
+	<application>
+		<activity android:name="com.example.helloworld.FeatureActivity"></activity>
+		<service android:name="com.example.helloworld.FeatureService"></service>
+	</application>
+</manifest>`),
+		}, zip.Store),
+	}
+}
+
+func checkTestSplitSet(t *testing.T, set *SplitSet) {
+	t.Helper()
+	if got, want := set.SplitNames(), []string{"config.xxhdpi", "feature"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if set.PackageName() != "com.example.helloworld" {
+		t.Errorf("unexpected package name: %s", set.PackageName())
+	}
+	if label, err := set.Label(nil); err != nil || label != "HelloWorld" {
+		t.Errorf("want HelloWorld, got %q, %v", label, err)
+	}
+
+	feature := set.Split("feature")
+	if feature == nil {
+		t.Fatal("feature split not found")
+	}
+	if !feature.IsFeatureSplit() || len(feature.Manifest().UsesSplits) != 1 {
+		t.Errorf("unexpected feature split: %+v", feature.Manifest())
+	}
+	config := set.Split("config.xxhdpi")
+	if config.IsFeatureSplit() || config.ConfigForSplit() != "" || !reflect.DeepEqual(config.SplitTypes(), []string{"density"}) {
+		t.Errorf("unexpected config split: %+v", config.Manifest())
+	}
+
+	// the components of the feature split are merged.
+	var activities []string
+	for _, activity := range set.Manifest().App.Activities {
+		activities = append(activities, activity.Name.MustString())
+	}
+	if want := []string{"com.example.helloworld.MainActivity", "com.example.helloworld.FeatureActivity"}; !reflect.DeepEqual(activities, want) {
+		t.Errorf("want %v, got %v", want, activities)
+	}
+	if services := set.Manifest().App.Services; len(services) != 1 {
+		t.Errorf("want 1 service, got %d", len(services))
+	}
+
+	// the icon of xxhdpi is in the configuration split.
+	icon, err := set.Icon(&androidbinary.ResTableConfig{Density: 480})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if icon.Bounds().Dx() != 144 {
+		t.Errorf("want the xxhdpi icon, got %v", icon.Bounds())
+	}
+
+	if got, want := set.MissingSplitTypes(), []string{"abi"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestOpenSplitSet(t *testing.T) {
+	t.Run("directory", func(t *testing.T) {
+		dir := t.TempDir()
+		for name, data := range newTestSplits(t) {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		set, err := OpenSplitSet(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer set.Close()
+		checkTestSplitSet(t, set)
+	})
+
+	t.Run("apks", func(t *testing.T) {
+		files := map[string][]byte{
+			"toc.pb":                            nil,
+			"standalones/standalone-xxhdpi.apk": []byte("ignored"),
+		}
+		for name, data := range newTestSplits(t) {
+			files["splits/"+name] = data
+		}
+		name := filepath.Join(t.TempDir(), "app.apks")
+		if err := ioutil.WriteFile(name, newTestZip(t, files, zip.Store), 0644); err != nil {
+			t.Fatal(err)
+		}
+		set, err := OpenSplitSet(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer set.Close()
+		checkTestSplitSet(t, set)
+	})
+
+	t.Run("xapk", func(t *testing.T) {
+		files := map[string][]byte{
+			"manifest.json": []byte("{}"),
+		}
+		for name, data := range newTestSplits(t) {
+			files[name] = data
+		}
+		data := newTestZip(t, files, zip.Deflate)
+		set, err := OpenSplitSetZipReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer set.Close()
+		checkTestSplitSet(t, set)
+	})
+}
+
+func TestNewSplitSetFailure(t *testing.T) {
+	splits := newTestSplits(t)
+	open := func(name string) *Apk {
+		data := splits[name]
+		apk, err := OpenZipReaderWithOptions(bytes.NewReader(data), int64(len(data)), splitOpenOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return apk
+	}
+
+	if _, err := NewSplitSet(open("feature-master.apk")); !errors.Is(err, ErrNoBaseAPK) {
+		t.Errorf("want ErrNoBaseAPK, got %v", err)
+	}
+	if _, err := NewSplitSet(open("base-master.apk"), open("feature-master.apk"), open("feature-master.apk")); !errors.Is(err, ErrDuplicateSplit) {
+		t.Errorf("want ErrDuplicateSplit, got %v", err)
+	}
+	if _, err := NewSplitSet(open("base-master.apk"), open("base-master.apk")); !errors.Is(err, ErrMultipleBaseAPKs) {
+		t.Errorf("want ErrMultipleBaseAPKs, got %v", err)
+	}
+
+	other := newTestZip(t, map[string][]byte{
+		"AndroidManifest.xml": encodeTestXML(t, `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.other" android:versionCode="1" split="other"></manifest>`),
+	}, zip.Deflate)
+	apk, err := OpenZipReaderWithOptions(bytes.NewReader(other), int64(len(other)), splitOpenOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSplitSet(open("base-master.apk"), apk); !errors.Is(err, ErrInconsistentSplit) {
+		t.Errorf("want ErrInconsistentSplit, got %v", err)
+	}
+
+	if _, err := OpenSplitSet(filepath.Join(t.TempDir(), "not-found")); !os.IsNotExist(err) {
+		t.Errorf("want not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,1060 @@
+// Code generated by internal/gen/attrs from android-16/android.jar. DO NOT EDIT.
+
+package androidbinary
+
+// frameworkAttrNames is the names of the attributes of the Android framework, keyed by the resource IDs.
+var frameworkAttrNames = map[ResID]string{
+	0x01010000: "theme",
+	0x01010001: "label",
+	0x01010002: "icon",
+	0x01010003: "name",
+	0x01010004: "manageSpaceActivity",
+	0x01010005: "allowClearUserData",
+	0x01010006: "permission",
+	0x01010007: "readPermission",
+	0x01010008: "writePermission",
+	0x01010009: "protectionLevel",
+	0x0101000A: "permissionGroup",
+	0x0101000B: "sharedUserId",
+	0x0101000C: "hasCode",
+	0x0101000D: "persistent",
+	0x0101000E: "enabled",
+	0x0101000F: "debuggable",
+	0x01010010: "exported",
+	0x01010011: "process",
+	0x01010012: "taskAffinity",
+	0x01010013: "multiprocess",
+	0x01010014: "finishOnTaskLaunch",
+	0x01010015: "clearTaskOnLaunch",
+	0x01010016: "stateNotNeeded",
+	0x01010017: "excludeFromRecents",
+	0x01010018: "authorities",
+	0x01010019: "syncable",
+	0x0101001A: "initOrder",
+	0x0101001B: "grantUriPermissions",
+	0x0101001C: "priority",
+	0x0101001D: "launchMode",
+	0x0101001E: "screenOrientation",
+	0x0101001F: "configChanges",
+	0x01010020: "description",
+	0x01010021: "targetPackage",
+	0x01010022: "handleProfiling",
+	0x01010023: "functionalTest",
+	0x01010024: "value",
+	0x01010025: "resource",
+	0x01010026: "mimeType",
+	0x01010027: "scheme",
+	0x01010028: "host",
+	0x01010029: "port",
+	0x0101002A: "path",
+	0x0101002B: "pathPrefix",
+	0x0101002C: "pathPattern",
+	0x0101002D: "action",
+	0x0101002E: "data",
+	0x0101002F: "targetClass",
+	0x01010030: "colorForeground",
+	0x01010031: "colorBackground",
+	0x01010032: "backgroundDimAmount",
+	0x01010033: "disabledAlpha",
+	0x01010034: "textAppearance",
+	0x01010035: "textAppearanceInverse",
+	0x01010036: "textColorPrimary",
+	0x01010037: "textColorPrimaryDisableOnly",
+	0x01010038: "textColorSecondary",
+	0x01010039: "textColorPrimaryInverse",
+	0x0101003A: "textColorSecondaryInverse",
+	0x0101003B: "textColorPrimaryNoDisable",
+	0x0101003C: "textColorSecondaryNoDisable",
+	0x0101003D: "textColorPrimaryInverseNoDisable",
+	0x0101003E: "textColorSecondaryInverseNoDisable",
+	0x0101003F: "textColorHintInverse",
+	0x01010040: "textAppearanceLarge",
+	0x01010041: "textAppearanceMedium",
+	0x01010042: "textAppearanceSmall",
+	0x01010043: "textAppearanceLargeInverse",
+	0x01010044: "textAppearanceMediumInverse",
+	0x01010045: "textAppearanceSmallInverse",
+	0x01010046: "textCheckMark",
+	0x01010047: "textCheckMarkInverse",
+	0x01010048: "buttonStyle",
+	0x01010049: "buttonStyleSmall",
+	0x0101004A: "buttonStyleInset",
+	0x0101004B: "buttonStyleToggle",
+	0x0101004C: "galleryItemBackground",
+	0x0101004D: "listPreferredItemHeight",
+	0x0101004E: "expandableListPreferredItemPaddingLeft",
+	0x0101004F: "expandableListPreferredChildPaddingLeft",
+	0x01010050: "expandableListPreferredItemIndicatorLeft",
+	0x01010051: "expandableListPreferredItemIndicatorRight",
+	0x01010052: "expandableListPreferredChildIndicatorLeft",
+	0x01010053: "expandableListPreferredChildIndicatorRight",
+	0x01010054: "windowBackground",
+	0x01010055: "windowFrame",
+	0x01010056: "windowNoTitle",
+	0x01010057: "windowIsFloating",
+	0x01010058: "windowIsTranslucent",
+	0x01010059: "windowContentOverlay",
+	0x0101005A: "windowTitleSize",
+	0x0101005B: "windowTitleStyle",
+	0x0101005C: "windowTitleBackgroundStyle",
+	0x0101005D: "alertDialogStyle",
+	0x0101005E: "panelBackground",
+	0x0101005F: "panelFullBackground",
+	0x01010060: "panelColorForeground",
+	0x01010061: "panelColorBackground",
+	0x01010062: "panelTextAppearance",
+	0x01010063: "scrollbarSize",
+	0x01010064: "scrollbarThumbHorizontal",
+	0x01010065: "scrollbarThumbVertical",
+	0x01010066: "scrollbarTrackHorizontal",
+	0x01010067: "scrollbarTrackVertical",
+	0x01010068: "scrollbarAlwaysDrawHorizontalTrack",
+	0x01010069: "scrollbarAlwaysDrawVerticalTrack",
+	0x0101006A: "absListViewStyle",
+	0x0101006B: "autoCompleteTextViewStyle",
+	0x0101006C: "checkboxStyle",
+	0x0101006D: "dropDownListViewStyle",
+	0x0101006E: "editTextStyle",
+	0x0101006F: "expandableListViewStyle",
+	0x01010070: "galleryStyle",
+	0x01010071: "gridViewStyle",
+	0x01010072: "imageButtonStyle",
+	0x01010073: "imageWellStyle",
+	0x01010074: "listViewStyle",
+	0x01010075: "listViewWhiteStyle",
+	0x01010076: "popupWindowStyle",
+	0x01010077: "progressBarStyle",
+	0x01010078: "progressBarStyleHorizontal",
+	0x01010079: "progressBarStyleSmall",
+	0x0101007A: "progressBarStyleLarge",
+	0x0101007B: "seekBarStyle",
+	0x0101007C: "ratingBarStyle",
+	0x0101007D: "ratingBarStyleSmall",
+	0x0101007E: "radioButtonStyle",
+	0x0101007F: "scrollbarStyle",
+	0x01010080: "scrollViewStyle",
+	0x01010081: "spinnerStyle",
+	0x01010082: "starStyle",
+	0x01010083: "tabWidgetStyle",
+	0x01010084: "textViewStyle",
+	0x01010085: "webViewStyle",
+	0x01010086: "dropDownItemStyle",
+	0x01010087: "spinnerDropDownItemStyle",
+	0x01010088: "dropDownHintAppearance",
+	0x01010089: "spinnerItemStyle",
+	0x0101008A: "mapViewStyle",
+	0x0101008B: "preferenceScreenStyle",
+	0x0101008C: "preferenceCategoryStyle",
+	0x0101008D: "preferenceInformationStyle",
+	0x0101008E: "preferenceStyle",
+	0x0101008F: "checkBoxPreferenceStyle",
+	0x01010090: "yesNoPreferenceStyle",
+	0x01010091: "dialogPreferenceStyle",
+	0x01010092: "editTextPreferenceStyle",
+	0x01010093: "ringtonePreferenceStyle",
+	0x01010094: "preferenceLayoutChild",
+	0x01010095: "textSize",
+	0x01010096: "typeface",
+	0x01010097: "textStyle",
+	0x01010098: "textColor",
+	0x01010099: "textColorHighlight",
+	0x0101009A: "textColorHint",
+	0x0101009B: "textColorLink",
+	0x0101009C: "state_focused",
+	0x0101009D: "state_window_focused",
+	0x0101009E: "state_enabled",
+	0x0101009F: "state_checkable",
+	0x010100A0: "state_checked",
+	0x010100A1: "state_selected",
+	0x010100A2: "state_active",
+	0x010100A3: "state_single",
+	0x010100A4: "state_first",
+	0x010100A5: "state_middle",
+	0x010100A6: "state_last",
+	0x010100A7: "state_pressed",
+	0x010100A8: "state_expanded",
+	0x010100A9: "state_empty",
+	0x010100AA: "state_above_anchor",
+	0x010100AB: "ellipsize",
+	0x010100AC: "x",
+	0x010100AD: "y",
+	0x010100AE: "windowAnimationStyle",
+	0x010100AF: "gravity",
+	0x010100B0: "autoLink",
+	0x010100B1: "linksClickable",
+	0x010100B2: "entries",
+	0x010100B3: "layout_gravity",
+	0x010100B4: "windowEnterAnimation",
+	0x010100B5: "windowExitAnimation",
+	0x010100B6: "windowShowAnimation",
+	0x010100B7: "windowHideAnimation",
+	0x010100B8: "activityOpenEnterAnimation",
+	0x010100B9: "activityOpenExitAnimation",
+	0x010100BA: "activityCloseEnterAnimation",
+	0x010100BB: "activityCloseExitAnimation",
+	0x010100BC: "taskOpenEnterAnimation",
+	0x010100BD: "taskOpenExitAnimation",
+	0x010100BE: "taskCloseEnterAnimation",
+	0x010100BF: "taskCloseExitAnimation",
+	0x010100C0: "taskToFrontEnterAnimation",
+	0x010100C1: "taskToFrontExitAnimation",
+	0x010100C2: "taskToBackEnterAnimation",
+	0x010100C3: "taskToBackExitAnimation",
+	0x010100C4: "orientation",
+	0x010100C5: "keycode",
+	0x010100C6: "fullDark",
+	0x010100C7: "topDark",
+	0x010100C8: "centerDark",
+	0x010100C9: "bottomDark",
+	0x010100CA: "fullBright",
+	0x010100CB: "topBright",
+	0x010100CC: "centerBright",
+	0x010100CD: "bottomBright",
+	0x010100CE: "bottomMedium",
+	0x010100CF: "centerMedium",
+	0x010100D0: "id",
+	0x010100D1: "tag",
+	0x010100D2: "scrollX",
+	0x010100D3: "scrollY",
+	0x010100D4: "background",
+	0x010100D5: "padding",
+	0x010100D6: "paddingLeft",
+	0x010100D7: "paddingTop",
+	0x010100D8: "paddingRight",
+	0x010100D9: "paddingBottom",
+	0x010100DA: "focusable",
+	0x010100DB: "focusableInTouchMode",
+	0x010100DC: "visibility",
+	0x010100DD: "fitsSystemWindows",
+	0x010100DE: "scrollbars",
+	0x010100DF: "fadingEdge",
+	0x010100E0: "fadingEdgeLength",
+	0x010100E1: "nextFocusLeft",
+	0x010100E2: "nextFocusRight",
+	0x010100E3: "nextFocusUp",
+	0x010100E4: "nextFocusDown",
+	0x010100E5: "clickable",
+	0x010100E6: "longClickable",
+	0x010100E7: "saveEnabled",
+	0x010100E8: "drawingCacheQuality",
+	0x010100E9: "duplicateParentState",
+	0x010100EA: "clipChildren",
+	0x010100EB: "clipToPadding",
+	0x010100EC: "layoutAnimation",
+	0x010100ED: "animationCache",
+	0x010100EE: "persistentDrawingCache",
+	0x010100EF: "alwaysDrawnWithCache",
+	0x010100F0: "addStatesFromChildren",
+	0x010100F1: "descendantFocusability",
+	0x010100F2: "layout",
+	0x010100F3: "inflatedId",
+	0x010100F4: "layout_width",
+	0x010100F5: "layout_height",
+	0x010100F6: "layout_margin",
+	0x010100F7: "layout_marginLeft",
+	0x010100F8: "layout_marginTop",
+	0x010100F9: "layout_marginRight",
+	0x010100FA: "layout_marginBottom",
+	0x010100FB: "listSelector",
+	0x010100FC: "drawSelectorOnTop",
+	0x010100FD: "stackFromBottom",
+	0x010100FE: "scrollingCache",
+	0x010100FF: "textFilterEnabled",
+	0x01010100: "transcriptMode",
+	0x01010101: "cacheColorHint",
+	0x01010102: "dial",
+	0x01010103: "hand_hour",
+	0x01010104: "hand_minute",
+	0x01010105: "format",
+	0x01010106: "checked",
+	0x01010107: "button",
+	0x01010108: "checkMark",
+	0x01010109: "foreground",
+	0x0101010A: "measureAllChildren",
+	0x0101010B: "groupIndicator",
+	0x0101010C: "childIndicator",
+	0x0101010D: "indicatorLeft",
+	0x0101010E: "indicatorRight",
+	0x0101010F: "childIndicatorLeft",
+	0x01010110: "childIndicatorRight",
+	0x01010111: "childDivider",
+	0x01010112: "animationDuration",
+	0x01010113: "spacing",
+	0x01010114: "horizontalSpacing",
+	0x01010115: "verticalSpacing",
+	0x01010116: "stretchMode",
+	0x01010117: "columnWidth",
+	0x01010118: "numColumns",
+	0x01010119: "src",
+	0x0101011A: "antialias",
+	0x0101011B: "filter",
+	0x0101011C: "dither",
+	0x0101011D: "scaleType",
+	0x0101011E: "adjustViewBounds",
+	0x0101011F: "maxWidth",
+	0x01010120: "maxHeight",
+	0x01010121: "tint",
+	0x01010122: "baselineAlignBottom",
+	0x01010123: "cropToPadding",
+	0x01010124: "textOn",
+	0x01010125: "textOff",
+	0x01010126: "baselineAligned",
+	0x01010127: "baselineAlignedChildIndex",
+	0x01010128: "weightSum",
+	0x01010129: "divider",
+	0x0101012A: "dividerHeight",
+	0x0101012B: "choiceMode",
+	0x0101012C: "itemTextAppearance",
+	0x0101012D: "horizontalDivider",
+	0x0101012E: "verticalDivider",
+	0x0101012F: "headerBackground",
+	0x01010130: "itemBackground",
+	0x01010131: "itemIconDisabledAlpha",
+	0x01010132: "rowHeight",
+	0x01010133: "maxRows",
+	0x01010134: "maxItemsPerRow",
+	0x01010135: "moreIcon",
+	0x01010136: "max",
+	0x01010137: "progress",
+	0x01010138: "secondaryProgress",
+	0x01010139: "indeterminate",
+	0x0101013A: "indeterminateOnly",
+	0x0101013B: "indeterminateDrawable",
+	0x0101013C: "progressDrawable",
+	0x0101013D: "indeterminateDuration",
+	0x0101013E: "indeterminateBehavior",
+	0x0101013F: "minWidth",
+	0x01010140: "minHeight",
+	0x01010141: "interpolator",
+	0x01010142: "thumb",
+	0x01010143: "thumbOffset",
+	0x01010144: "numStars",
+	0x01010145: "rating",
+	0x01010146: "stepSize",
+	0x01010147: "isIndicator",
+	0x01010148: "checkedButton",
+	0x01010149: "stretchColumns",
+	0x0101014A: "shrinkColumns",
+	0x0101014B: "collapseColumns",
+	0x0101014C: "layout_column",
+	0x0101014D: "layout_span",
+	0x0101014E: "bufferType",
+	0x0101014F: "text",
+	0x01010150: "hint",
+	0x01010151: "textScaleX",
+	0x01010152: "cursorVisible",
+	0x01010153: "maxLines",
+	0x01010154: "lines",
+	0x01010155: "height",
+	0x01010156: "minLines",
+	0x01010157: "maxEms",
+	0x01010158: "ems",
+	0x01010159: "width",
+	0x0101015A: "minEms",
+	0x0101015B: "scrollHorizontally",
+	0x0101015C: "password",
+	0x0101015D: "singleLine",
+	0x0101015E: "selectAllOnFocus",
+	0x0101015F: "includeFontPadding",
+	0x01010160: "maxLength",
+	0x01010161: "shadowColor",
+	0x01010162: "shadowDx",
+	0x01010163: "shadowDy",
+	0x01010164: "shadowRadius",
+	0x01010165: "numeric",
+	0x01010166: "digits",
+	0x01010167: "phoneNumber",
+	0x01010169: "capitalize",
+	0x0101016A: "autoText",
+	0x0101016B: "editable",
+	0x0101016C: "freezesText",
+	0x0101016D: "drawableTop",
+	0x0101016E: "drawableBottom",
+	0x0101016F: "drawableLeft",
+	0x01010170: "drawableRight",
+	0x01010171: "drawablePadding",
+	0x01010172: "completionHint",
+	0x01010173: "completionHintView",
+	0x01010174: "completionThreshold",
+	0x01010175: "dropDownSelector",
+	0x01010176: "popupBackground",
+	0x01010177: "inAnimation",
+	0x01010178: "outAnimation",
+	0x01010179: "flipInterval",
+	0x0101017A: "fillViewport",
+	0x0101017B: "prompt",
+	0x0101017C: "startYear",
+	0x0101017D: "endYear",
+	0x0101017E: "mode",
+	0x0101017F: "layout_x",
+	0x01010180: "layout_y",
+	0x01010181: "layout_weight",
+	0x01010182: "layout_toLeftOf",
+	0x01010183: "layout_toRightOf",
+	0x01010184: "layout_above",
+	0x01010185: "layout_below",
+	0x01010186: "layout_alignBaseline",
+	0x01010187: "layout_alignLeft",
+	0x01010188: "layout_alignTop",
+	0x01010189: "layout_alignRight",
+	0x0101018A: "layout_alignBottom",
+	0x0101018B: "layout_alignParentLeft",
+	0x0101018C: "layout_alignParentTop",
+	0x0101018D: "layout_alignParentRight",
+	0x0101018E: "layout_alignParentBottom",
+	0x0101018F: "layout_centerInParent",
+	0x01010190: "layout_centerHorizontal",
+	0x01010191: "layout_centerVertical",
+	0x01010192: "layout_alignWithParentIfMissing",
+	0x01010193: "layout_scale",
+	0x01010194: "visible",
+	0x01010195: "variablePadding",
+	0x01010196: "constantSize",
+	0x01010197: "oneshot",
+	0x01010198: "duration",
+	0x01010199: "drawable",
+	0x0101019A: "shape",
+	0x0101019B: "innerRadiusRatio",
+	0x0101019C: "thicknessRatio",
+	0x0101019D: "startColor",
+	0x0101019E: "endColor",
+	0x0101019F: "useLevel",
+	0x010101A0: "angle",
+	0x010101A1: "type",
+	0x010101A2: "centerX",
+	0x010101A3: "centerY",
+	0x010101A4: "gradientRadius",
+	0x010101A5: "color",
+	0x010101A6: "dashWidth",
+	0x010101A7: "dashGap",
+	0x010101A8: "radius",
+	0x010101A9: "topLeftRadius",
+	0x010101AA: "topRightRadius",
+	0x010101AB: "bottomLeftRadius",
+	0x010101AC: "bottomRightRadius",
+	0x010101AD: "left",
+	0x010101AE: "top",
+	0x010101AF: "right",
+	0x010101B0: "bottom",
+	0x010101B1: "minLevel",
+	0x010101B2: "maxLevel",
+	0x010101B3: "fromDegrees",
+	0x010101B4: "toDegrees",
+	0x010101B5: "pivotX",
+	0x010101B6: "pivotY",
+	0x010101B7: "insetLeft",
+	0x010101B8: "insetRight",
+	0x010101B9: "insetTop",
+	0x010101BA: "insetBottom",
+	0x010101BB: "shareInterpolator",
+	0x010101BC: "fillBefore",
+	0x010101BD: "fillAfter",
+	0x010101BE: "startOffset",
+	0x010101BF: "repeatCount",
+	0x010101C0: "repeatMode",
+	0x010101C1: "zAdjustment",
+	0x010101C2: "fromXScale",
+	0x010101C3: "toXScale",
+	0x010101C4: "fromYScale",
+	0x010101C5: "toYScale",
+	0x010101C6: "fromXDelta",
+	0x010101C7: "toXDelta",
+	0x010101C8: "fromYDelta",
+	0x010101C9: "toYDelta",
+	0x010101CA: "fromAlpha",
+	0x010101CB: "toAlpha",
+	0x010101CC: "delay",
+	0x010101CD: "animation",
+	0x010101CE: "animationOrder",
+	0x010101CF: "columnDelay",
+	0x010101D0: "rowDelay",
+	0x010101D1: "direction",
+	0x010101D2: "directionPriority",
+	0x010101D3: "factor",
+	0x010101D4: "cycles",
+	0x010101D5: "searchMode",
+	0x010101D6: "searchSuggestAuthority",
+	0x010101D7: "searchSuggestPath",
+	0x010101D8: "searchSuggestSelection",
+	0x010101D9: "searchSuggestIntentAction",
+	0x010101DA: "searchSuggestIntentData",
+	0x010101DB: "queryActionMsg",
+	0x010101DC: "suggestActionMsg",
+	0x010101DD: "suggestActionMsgColumn",
+	0x010101DE: "menuCategory",
+	0x010101DF: "orderInCategory",
+	0x010101E0: "checkableBehavior",
+	0x010101E1: "title",
+	0x010101E2: "titleCondensed",
+	0x010101E3: "alphabeticShortcut",
+	0x010101E4: "numericShortcut",
+	0x010101E5: "checkable",
+	0x010101E6: "selectable",
+	0x010101E7: "orderingFromXml",
+	0x010101E8: "key",
+	0x010101E9: "summary",
+	0x010101EA: "order",
+	0x010101EB: "widgetLayout",
+	0x010101EC: "dependency",
+	0x010101ED: "defaultValue",
+	0x010101EE: "shouldDisableView",
+	0x010101EF: "summaryOn",
+	0x010101F0: "summaryOff",
+	0x010101F1: "disableDependentsState",
+	0x010101F2: "dialogTitle",
+	0x010101F3: "dialogMessage",
+	0x010101F4: "dialogIcon",
+	0x010101F5: "positiveButtonText",
+	0x010101F6: "negativeButtonText",
+	0x010101F7: "dialogLayout",
+	0x010101F8: "entryValues",
+	0x010101F9: "ringtoneType",
+	0x010101FA: "showDefault",
+	0x010101FB: "showSilent",
+	0x010101FC: "scaleWidth",
+	0x010101FD: "scaleHeight",
+	0x010101FE: "scaleGravity",
+	0x010101FF: "ignoreGravity",
+	0x01010200: "foregroundGravity",
+	0x01010201: "tileMode",
+	0x01010202: "targetActivity",
+	0x01010203: "alwaysRetainTaskState",
+	0x01010204: "allowTaskReparenting",
+	0x01010205: "searchButtonText",
+	0x01010206: "colorForegroundInverse",
+	0x01010207: "textAppearanceButton",
+	0x01010208: "listSeparatorTextViewStyle",
+	0x01010209: "streamType",
+	0x0101020A: "clipOrientation",
+	0x0101020B: "centerColor",
+	0x0101020C: "minSdkVersion",
+	0x0101020D: "windowFullscreen",
+	0x0101020E: "unselectedAlpha",
+	0x0101020F: "progressBarStyleSmallTitle",
+	0x01010210: "ratingBarStyleIndicator",
+	0x01010211: "apiKey",
+	0x01010212: "textColorTertiary",
+	0x01010213: "textColorTertiaryInverse",
+	0x01010214: "listDivider",
+	0x01010215: "soundEffectsEnabled",
+	0x01010216: "keepScreenOn",
+	0x01010217: "lineSpacingExtra",
+	0x01010218: "lineSpacingMultiplier",
+	0x01010219: "listChoiceIndicatorSingle",
+	0x0101021A: "listChoiceIndicatorMultiple",
+	0x0101021B: "versionCode",
+	0x0101021C: "versionName",
+	0x0101021D: "marqueeRepeatLimit",
+	0x0101021E: "windowNoDisplay",
+	0x0101021F: "backgroundDimEnabled",
+	0x01010220: "inputType",
+	0x01010221: "isDefault",
+	0x01010222: "windowDisablePreview",
+	0x01010223: "privateImeOptions",
+	0x01010224: "editorExtras",
+	0x01010225: "settingsActivity",
+	0x01010226: "fastScrollEnabled",
+	0x01010227: "reqTouchScreen",
+	0x01010228: "reqKeyboardType",
+	0x01010229: "reqHardKeyboard",
+	0x0101022A: "reqNavigation",
+	0x0101022B: "windowSoftInputMode",
+	0x0101022C: "imeFullscreenBackground",
+	0x0101022D: "noHistory",
+	0x0101022E: "headerDividersEnabled",
+	0x0101022F: "footerDividersEnabled",
+	0x01010230: "candidatesTextStyleSpans",
+	0x01010231: "smoothScrollbar",
+	0x01010232: "reqFiveWayNav",
+	0x01010233: "keyBackground",
+	0x01010234: "keyTextSize",
+	0x01010235: "labelTextSize",
+	0x01010236: "keyTextColor",
+	0x01010237: "keyPreviewLayout",
+	0x01010238: "keyPreviewOffset",
+	0x01010239: "keyPreviewHeight",
+	0x0101023A: "verticalCorrection",
+	0x0101023B: "popupLayout",
+	0x0101023C: "state_long_pressable",
+	0x0101023D: "keyWidth",
+	0x0101023E: "keyHeight",
+	0x0101023F: "horizontalGap",
+	0x01010240: "verticalGap",
+	0x01010241: "rowEdgeFlags",
+	0x01010242: "codes",
+	0x01010243: "popupKeyboard",
+	0x01010244: "popupCharacters",
+	0x01010245: "keyEdgeFlags",
+	0x01010246: "isModifier",
+	0x01010247: "isSticky",
+	0x01010248: "isRepeatable",
+	0x01010249: "iconPreview",
+	0x0101024A: "keyOutputText",
+	0x0101024B: "keyLabel",
+	0x0101024C: "keyIcon",
+	0x0101024D: "keyboardMode",
+	0x0101024E: "isScrollContainer",
+	0x0101024F: "fillEnabled",
+	0x01010250: "updatePeriodMillis",
+	0x01010251: "initialLayout",
+	0x01010252: "voiceSearchMode",
+	0x01010253: "voiceLanguageModel",
+	0x01010254: "voicePromptText",
+	0x01010255: "voiceLanguage",
+	0x01010256: "voiceMaxResults",
+	0x01010257: "bottomOffset",
+	0x01010258: "topOffset",
+	0x01010259: "allowSingleTap",
+	0x0101025A: "handle",
+	0x0101025B: "content",
+	0x0101025C: "animateOnClick",
+	0x0101025D: "configure",
+	0x0101025E: "hapticFeedbackEnabled",
+	0x0101025F: "innerRadius",
+	0x01010260: "thickness",
+	0x01010261: "sharedUserLabel",
+	0x01010262: "dropDownWidth",
+	0x01010263: "dropDownAnchor",
+	0x01010264: "imeOptions",
+	0x01010265: "imeActionLabel",
+	0x01010266: "imeActionId",
+	0x01010267: "textColorSearchUrl",
+	0x01010268: "imeExtractEnterAnimation",
+	0x01010269: "imeExtractExitAnimation",
+	0x0101026A: "tension",
+	0x0101026B: "extraTension",
+	0x0101026C: "anyDensity",
+	0x0101026D: "searchSuggestThreshold",
+	0x0101026E: "includeInGlobalSearch",
+	0x0101026F: "onClick",
+	0x01010270: "targetSdkVersion",
+	0x01010271: "maxSdkVersion",
+	0x01010272: "testOnly",
+	0x01010273: "contentDescription",
+	0x01010274: "gestureStrokeWidth",
+	0x01010275: "gestureColor",
+	0x01010276: "uncertainGestureColor",
+	0x01010277: "fadeOffset",
+	0x01010278: "fadeDuration",
+	0x01010279: "gestureStrokeType",
+	0x0101027A: "gestureStrokeLengthThreshold",
+	0x0101027B: "gestureStrokeSquarenessThreshold",
+	0x0101027C: "gestureStrokeAngleThreshold",
+	0x0101027D: "eventsInterceptionEnabled",
+	0x0101027E: "fadeEnabled",
+	0x0101027F: "backupAgent",
+	0x01010280: "allowBackup",
+	0x01010281: "glEsVersion",
+	0x01010282: "queryAfterZeroResults",
+	0x01010283: "dropDownHeight",
+	0x01010284: "smallScreens",
+	0x01010285: "normalScreens",
+	0x01010286: "largeScreens",
+	0x01010287: "progressBarStyleInverse",
+	0x01010288: "progressBarStyleSmallInverse",
+	0x01010289: "progressBarStyleLargeInverse",
+	0x0101028A: "searchSettingsDescription",
+	0x0101028B: "textColorPrimaryInverseDisableOnly",
+	0x0101028C: "autoUrlDetect",
+	0x0101028D: "resizeable",
+	0x0101028E: "required",
+	0x0101028F: "accountType",
+	0x01010290: "contentAuthority",
+	0x01010291: "userVisible",
+	0x01010292: "windowShowWallpaper",
+	0x01010293: "wallpaperOpenEnterAnimation",
+	0x01010294: "wallpaperOpenExitAnimation",
+	0x01010295: "wallpaperCloseEnterAnimation",
+	0x01010296: "wallpaperCloseExitAnimation",
+	0x01010297: "wallpaperIntraOpenEnterAnimation",
+	0x01010298: "wallpaperIntraOpenExitAnimation",
+	0x01010299: "wallpaperIntraCloseEnterAnimation",
+	0x0101029A: "wallpaperIntraCloseExitAnimation",
+	0x0101029B: "supportsUploading",
+	0x0101029C: "killAfterRestore",
+	0x0101029D: "restoreNeedsApplication",
+	0x0101029E: "smallIcon",
+	0x0101029F: "accountPreferences",
+	0x010102A0: "textAppearanceSearchResultSubtitle",
+	0x010102A1: "textAppearanceSearchResultTitle",
+	0x010102A2: "summaryColumn",
+	0x010102A3: "detailColumn",
+	0x010102A4: "detailSocialSummary",
+	0x010102A5: "thumbnail",
+	0x010102A6: "detachWallpaper",
+	0x010102A7: "finishOnCloseSystemDialogs",
+	0x010102A8: "scrollbarFadeDuration",
+	0x010102A9: "scrollbarDefaultDelayBeforeFade",
+	0x010102AA: "fadeScrollbars",
+	0x010102AB: "colorBackgroundCacheHint",
+	0x010102AC: "dropDownHorizontalOffset",
+	0x010102AD: "dropDownVerticalOffset",
+	0x010102AE: "quickContactBadgeStyleWindowSmall",
+	0x010102AF: "quickContactBadgeStyleWindowMedium",
+	0x010102B0: "quickContactBadgeStyleWindowLarge",
+	0x010102B1: "quickContactBadgeStyleSmallWindowSmall",
+	0x010102B2: "quickContactBadgeStyleSmallWindowMedium",
+	0x010102B3: "quickContactBadgeStyleSmallWindowLarge",
+	0x010102B4: "author",
+	0x010102B5: "autoStart",
+	0x010102B6: "expandableListViewWhiteStyle",
+	0x010102B7: "installLocation",
+	0x010102B8: "vmSafeMode",
+	0x010102B9: "webTextViewStyle",
+	0x010102BA: "restoreAnyVersion",
+	0x010102BB: "tabStripLeft",
+	0x010102BC: "tabStripRight",
+	0x010102BD: "tabStripEnabled",
+	0x010102BE: "logo",
+	0x010102BF: "xlargeScreens",
+	0x010102C0: "immersive",
+	0x010102C1: "overScrollMode",
+	0x010102C2: "overScrollHeader",
+	0x010102C3: "overScrollFooter",
+	0x010102C4: "filterTouchesWhenObscured",
+	0x010102C5: "textSelectHandleLeft",
+	0x010102C6: "textSelectHandleRight",
+	0x010102C7: "textSelectHandle",
+	0x010102C8: "textSelectHandleWindowStyle",
+	0x010102C9: "popupAnimationStyle",
+	0x010102CA: "screenSize",
+	0x010102CB: "screenDensity",
+	0x010102CC: "allContactsName",
+	0x010102CD: "windowActionBar",
+	0x010102CE: "actionBarStyle",
+	0x010102CF: "navigationMode",
+	0x010102D0: "displayOptions",
+	0x010102D1: "subtitle",
+	0x010102D2: "customNavigationLayout",
+	0x010102D3: "hardwareAccelerated",
+	0x010102D4: "measureWithLargestChild",
+	0x010102D5: "animateFirstView",
+	0x010102D6: "dropDownSpinnerStyle",
+	0x010102D7: "actionDropDownStyle",
+	0x010102D8: "actionButtonStyle",
+	0x010102D9: "showAsAction",
+	0x010102DA: "previewImage",
+	0x010102DB: "actionModeBackground",
+	0x010102DC: "actionModeCloseDrawable",
+	0x010102DD: "windowActionModeOverlay",
+	0x010102DE: "valueFrom",
+	0x010102DF: "valueTo",
+	0x010102E0: "valueType",
+	0x010102E1: "propertyName",
+	0x010102E2: "ordering",
+	0x010102E3: "fragment",
+	0x010102E4: "windowActionBarOverlay",
+	0x010102E5: "fragmentOpenEnterAnimation",
+	0x010102E6: "fragmentOpenExitAnimation",
+	0x010102E7: "fragmentCloseEnterAnimation",
+	0x010102E8: "fragmentCloseExitAnimation",
+	0x010102E9: "fragmentFadeEnterAnimation",
+	0x010102EA: "fragmentFadeExitAnimation",
+	0x010102EB: "actionBarSize",
+	0x010102EC: "imeSubtypeLocale",
+	0x010102ED: "imeSubtypeMode",
+	0x010102EE: "imeSubtypeExtraValue",
+	0x010102EF: "splitMotionEvents",
+	0x010102F0: "listChoiceBackgroundIndicator",
+	0x010102F1: "spinnerMode",
+	0x010102F2: "animateLayoutChanges",
+	0x010102F3: "actionBarTabStyle",
+	0x010102F4: "actionBarTabBarStyle",
+	0x010102F5: "actionBarTabTextStyle",
+	0x010102F6: "actionOverflowButtonStyle",
+	0x010102F7: "actionModeCloseButtonStyle",
+	0x010102F8: "titleTextStyle",
+	0x010102F9: "subtitleTextStyle",
+	0x010102FA: "iconifiedByDefault",
+	0x010102FB: "actionLayout",
+	0x010102FC: "actionViewClass",
+	0x010102FD: "activatedBackgroundIndicator",
+	0x010102FE: "state_activated",
+	0x010102FF: "listPopupWindowStyle",
+	0x01010300: "popupMenuStyle",
+	0x01010301: "textAppearanceLargePopupMenu",
+	0x01010302: "textAppearanceSmallPopupMenu",
+	0x01010303: "breadCrumbTitle",
+	0x01010304: "breadCrumbShortTitle",
+	0x01010305: "listDividerAlertDialog",
+	0x01010306: "textColorAlertDialogListItem",
+	0x01010307: "loopViews",
+	0x01010308: "dialogTheme",
+	0x01010309: "alertDialogTheme",
+	0x0101030A: "dividerVertical",
+	0x0101030B: "homeAsUpIndicator",
+	0x0101030C: "enterFadeDuration",
+	0x0101030D: "exitFadeDuration",
+	0x0101030E: "selectableItemBackground",
+	0x0101030F: "autoAdvanceViewId",
+	0x01010310: "useIntrinsicSizeAsMinimum",
+	0x01010311: "actionModeCutDrawable",
+	0x01010312: "actionModeCopyDrawable",
+	0x01010313: "actionModePasteDrawable",
+	0x01010314: "textEditPasteWindowLayout",
+	0x01010315: "textEditNoPasteWindowLayout",
+	0x01010316: "textIsSelectable",
+	0x01010317: "windowEnableSplitTouch",
+	0x01010318: "indeterminateProgressStyle",
+	0x01010319: "progressBarPadding",
+	0x0101031A: "animationResolution",
+	0x0101031B: "state_accelerated",
+	0x0101031C: "baseline",
+	0x0101031D: "homeLayout",
+	0x0101031E: "opacity",
+	0x0101031F: "alpha",
+	0x01010320: "transformPivotX",
+	0x01010321: "transformPivotY",
+	0x01010322: "translationX",
+	0x01010323: "translationY",
+	0x01010324: "scaleX",
+	0x01010325: "scaleY",
+	0x01010326: "rotation",
+	0x01010327: "rotationX",
+	0x01010328: "rotationY",
+	0x01010329: "showDividers",
+	0x0101032A: "dividerPadding",
+	0x0101032B: "borderlessButtonStyle",
+	0x0101032C: "dividerHorizontal",
+	0x0101032D: "itemPadding",
+	0x0101032E: "buttonBarStyle",
+	0x0101032F: "buttonBarButtonStyle",
+	0x01010330: "segmentedButtonStyle",
+	0x01010331: "staticWallpaperPreview",
+	0x01010332: "allowParallelSyncs",
+	0x01010333: "isAlwaysSyncable",
+	0x01010334: "verticalScrollbarPosition",
+	0x01010335: "fastScrollAlwaysVisible",
+	0x01010336: "fastScrollThumbDrawable",
+	0x01010337: "fastScrollPreviewBackgroundLeft",
+	0x01010338: "fastScrollPreviewBackgroundRight",
+	0x01010339: "fastScrollTrackDrawable",
+	0x0101033A: "fastScrollOverlayPosition",
+	0x0101033B: "customTokens",
+	0x0101033C: "nextFocusForward",
+	0x0101033D: "firstDayOfWeek",
+	0x0101033E: "showWeekNumber",
+	0x0101033F: "minDate",
+	0x01010340: "maxDate",
+	0x01010341: "shownWeekCount",
+	0x01010342: "selectedWeekBackgroundColor",
+	0x01010343: "focusedMonthDateColor",
+	0x01010344: "unfocusedMonthDateColor",
+	0x01010345: "weekNumberColor",
+	0x01010346: "weekSeparatorLineColor",
+	0x01010347: "selectedDateVerticalBar",
+	0x01010348: "weekDayTextAppearance",
+	0x01010349: "dateTextAppearance",
+	0x0101034A: "solidColor",
+	0x0101034B: "spinnersShown",
+	0x0101034C: "calendarViewShown",
+	0x0101034D: "state_multiline",
+	0x0101034E: "detailsElementBackground",
+	0x0101034F: "textColorHighlightInverse",
+	0x01010350: "textColorLinkInverse",
+	0x01010351: "editTextColor",
+	0x01010352: "editTextBackground",
+	0x01010353: "horizontalScrollViewStyle",
+	0x01010354: "layerType",
+	0x01010355: "alertDialogIcon",
+	0x01010356: "windowMinWidthMajor",
+	0x01010357: "windowMinWidthMinor",
+	0x01010358: "queryHint",
+	0x01010359: "fastScrollTextColor",
+	0x0101035A: "largeHeap",
+	0x0101035B: "windowCloseOnTouchOutside",
+	0x0101035C: "datePickerStyle",
+	0x0101035D: "calendarViewStyle",
+	0x0101035E: "textEditSidePasteWindowLayout",
+	0x0101035F: "textEditSideNoPasteWindowLayout",
+	0x01010360: "actionMenuTextAppearance",
+	0x01010361: "actionMenuTextColor",
+	0x01010362: "textCursorDrawable",
+	0x01010363: "resizeMode",
+	0x01010364: "requiresSmallestWidthDp",
+	0x01010365: "compatibleWidthLimitDp",
+	0x01010366: "largestWidthLimitDp",
+	0x01010367: "state_hovered",
+	0x01010368: "state_drag_can_accept",
+	0x01010369: "state_drag_hovered",
+	0x0101036A: "stopWithTask",
+	0x0101036B: "switchTextOn",
+	0x0101036C: "switchTextOff",
+	0x0101036D: "switchPreferenceStyle",
+	0x0101036E: "switchTextAppearance",
+	0x0101036F: "track",
+	0x01010370: "switchMinWidth",
+	0x01010371: "switchPadding",
+	0x01010372: "thumbTextPadding",
+	0x01010373: "textSuggestionsWindowStyle",
+	0x01010374: "textEditSuggestionItemLayout",
+	0x01010375: "rowCount",
+	0x01010376: "rowOrderPreserved",
+	0x01010377: "columnCount",
+	0x01010378: "columnOrderPreserved",
+	0x01010379: "useDefaultMargins",
+	0x0101037A: "alignmentMode",
+	0x0101037B: "layout_row",
+	0x0101037C: "layout_rowSpan",
+	0x0101037D: "layout_columnSpan",
+	0x0101037E: "actionModeSelectAllDrawable",
+	0x0101037F: "isAuxiliary",
+	0x01010380: "accessibilityEventTypes",
+	0x01010381: "packageNames",
+	0x01010382: "accessibilityFeedbackType",
+	0x01010383: "notificationTimeout",
+	0x01010384: "accessibilityFlags",
+	0x01010385: "canRetrieveWindowContent",
+	0x01010386: "listPreferredItemHeightLarge",
+	0x01010387: "listPreferredItemHeightSmall",
+	0x01010388: "actionBarSplitStyle",
+	0x01010389: "actionProviderClass",
+	0x0101038A: "backgroundStacked",
+	0x0101038B: "backgroundSplit",
+	0x0101038C: "textAllCaps",
+	0x0101038D: "colorPressedHighlight",
+	0x0101038E: "colorLongPressedHighlight",
+	0x0101038F: "colorFocusedHighlight",
+	0x01010390: "colorActivatedHighlight",
+	0x01010391: "colorMultiSelectHighlight",
+	0x01010392: "drawableStart",
+	0x01010393: "drawableEnd",
+	0x01010394: "actionModeStyle",
+	0x01010395: "minResizeWidth",
+	0x01010396: "minResizeHeight",
+	0x01010397: "actionBarWidgetTheme",
+	0x01010398: "uiOptions",
+	0x01010399: "subtypeLocale",
+	0x0101039A: "subtypeExtraValue",
+	0x0101039B: "actionBarDivider",
+	0x0101039C: "actionBarItemBackground",
+	0x0101039D: "actionModeSplitBackground",
+	0x0101039E: "textAppearanceListItem",
+	0x0101039F: "textAppearanceListItemSmall",
+	0x010103A0: "targetDescriptions",
+	0x010103A1: "directionDescriptions",
+	0x010103A2: "overridesImplicitlyEnabledSubtype",
+	0x010103A3: "listPreferredItemPaddingLeft",
+	0x010103A4: "listPreferredItemPaddingRight",
+	0x010103A5: "requiresFadingEdge",
+	0x010103A6: "publicKey",
+	0x010103A7: "searchWidgetCorpusItemBackground",
+	0x010103A8: "textAppearanceEasyCorrectSuggestion",
+	0x010103A9: "textAppearanceMisspelledSuggestion",
+	0x010103AA: "textAppearanceAutoCorrectionSuggestion",
+	0x010103AB: "textUnderlineColor",
+	0x010103AC: "textUnderlineThickness",
+	0x010103AD: "errorMessageBackground",
+	0x010103AE: "errorMessageAboveBackground",
+	0x010103AF: "searchResultListItemHeight",
+	0x010103B0: "dropdownListPreferredItemHeight",
+	0x010103B1: "windowSplitActionBar",
+	0x010103B2: "alertDialogButtonGroupStyle",
+	0x010103B3: "alertDialogCenterButtons",
+	0x010103B4: "panelMenuIsCompact",
+	0x010103B5: "panelMenuListWidth",
+	0x010103B6: "panelMenuListTheme",
+	0x010103B7: "gestureOverlayViewStyle",
+	0x010103B8: "quickContactBadgeOverlay",
+	0x010103B9: "stackViewStyle",
+	0x010103BA: "numberPickerStyle",
+	0x010103BB: "numberPickerUpButtonStyle",
+	0x010103BC: "numberPickerDownButtonStyle",
+	0x010103BD: "numberPickerInputTextStyle",
+	0x010103BE: "timePickerStyle",
+	0x010103BF: "activityChooserViewStyle",
+	0x010103C0: "actionModeShareDrawable",
+	0x010103C1: "actionModeFindDrawable",
+	0x010103C2: "actionModeWebSearchDrawable",
+	0x010103C3: "actionModePopupWindowStyle",
+	0x010103C4: "preferenceFragmentStyle",
+	0x010103C5: "preferencePanelStyle",
+	0x010103C6: "dialogTitleIconsDecorLayout",
+	0x010103C7: "dialogCustomTitleDecorLayout",
+	0x010103C8: "dialogTitleDecorLayout",
+	0x010103C9: "toastFrameBackground",
+	0x010103CA: "searchDropdownBackground",
+	0x010103CB: "searchViewCloseIcon",
+	0x010103CC: "searchViewGoIcon",
+	0x010103CD: "searchViewSearchIcon",
+	0x010103CE: "searchViewVoiceIcon",
+	0x010103CF: "searchViewEditQuery",
+	0x010103D0: "searchViewEditQueryBackground",
+	0x010103D1: "searchViewTextField",
+	0x010103D2: "searchViewTextFieldRight",
+	0x010103D3: "searchDialogTheme",
+	0x010103D4: "preferenceFrameLayoutStyle",
+	0x010103D5: "switchStyle",
+	0x010103D6: "pointerStyle",
+	0x010103D7: "listLayout",
+	0x010103D8: "multiChoiceItemLayout",
+	0x010103D9: "singleChoiceItemLayout",
+	0x010103DA: "listItemLayout",
+	0x010103DB: "progressLayout",
+	0x010103DC: "horizontalProgressLayout",
+	0x010103DD: "paddingStart",
+	0x010103DE: "paddingEnd",
+	0x010103DF: "layoutDirection",
+	0x010103E0: "textDirection",
+	0x010103E1: "layout_marginStart",
+	0x010103E2: "layout_marginEnd",
+	0x010103E3: "foregroundInsidePadding",
+	0x010103E4: "drawableAlpha",
+	0x010103E5: "borderTop",
+	0x010103E6: "borderBottom",
+	0x010103E7: "borderLeft",
+	0x010103E8: "borderRight",
+	0x010103E9: "layout_removeBorders",
+	0x010103EA: "preserveIconSpacing",
+	0x010103EB: "maxItems",
+	0x010103EC: "resOutColor",
+	0x010103ED: "clickColor",
+	0x010103EE: "tabLayout",
+	0x010103EF: "popupPromptView",
+	0x010103F0: "disableChildrenWhenDisabled",
+	0x010103F1: "quickContactWindowSize",
+	0x010103F2: "majorWeightMin",
+	0x010103F3: "minorWeightMin",
+	0x010103F4: "majorWeightMax",
+	0x010103F5: "minorWeightMax",
+	0x010103F6: "flingable",
+	0x010103F7: "selectionDivider",
+	0x010103F8: "selectionDividerHeight",
+	0x010103F9: "frameDuration",
+	0x010103FA: "framesCount",
+	0x010103FB: "initialActivityCount",
+	0x010103FC: "expandActivityOverflowButtonDrawable",
+	0x010103FD: "keyboardViewStyle",
+	0x010103FE: "targetDrawables",
+	0x010103FF: "handleDrawable",
+	0x01010400: "leftChevronDrawable",
+	0x01010401: "rightChevronDrawable",
+	0x01010402: "topChevronDrawable",
+	0x01010403: "bottomChevronDrawable",
+	0x01010404: "waveDrawable",
+	0x01010405: "outerRadius",
+	0x01010406: "hitRadius",
+	0x01010407: "vibrationDuration",
+	0x01010408: "snapMargin",
+	0x01010409: "feedbackCount",
+	0x0101040A: "verticalOffset",
+	0x0101040B: "horizontalOffset",
+	0x0101040C: "aspect",
+	0x0101040D: "pointerIconArrow",
+	0x0101040E: "pointerIconSpotHover",
+	0x0101040F: "pointerIconSpotTouch",
+	0x01010410: "pointerIconSpotAnchor",
+	0x01010411: "bitmap",
+	0x01010412: "hotSpotX",
+	0x01010413: "hotSpotY",
+	0x01010414: "mountPoint",
+	0x01010415: "storageDescription",
+	0x01010416: "primary",
+	0x01010417: "removable",
+	0x01010418: "emulated",
+	0x01010419: "mtpReserve",
+	0x0101041A: "allowMassStorage",
+	0x0101041B: "maxFileSize",
+	0x0101041C: "neverEncrypt",
+	0x0101041D: "cantSaveState",
+}
@@ -3,8 +3,10 @@ package androidbinary
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"unicode/utf16"
+	"unsafe"
 )
 
 // ChunkType is a type of a resource chunk.
@@ -65,6 +67,10 @@ type ResStringPoolHeader struct {
 // ResStringPoolSpan is a span of style information associated with
 // a string in the pool.
 type ResStringPoolSpan struct {
+	// Name is the reference to the name of the span in the pool, such as "b" and "annotation;key=value".
+	Name ResStringPoolRef
+
+	// FirstChar and LastChar are the range of the span in UTF-16 code units, both inclusive.
 	FirstChar, LastChar uint32
 }
 
@@ -72,7 +78,19 @@ type ResStringPoolSpan struct {
 type ResStringPool struct {
 	Header  ResStringPoolHeader
 	Strings []string
-	Styles  []ResStringPoolSpan
+
+	// Styles is the first span of each styled string.
+	//
+	// Deprecated: Use Spans, which has all the spans of the strings.
+	Styles []ResStringPoolSpan
+
+	// Spans is the spans of the styled strings. Spans[i] is the spans of the i-th string.
+	// The strings after len(Spans) have no spans.
+	Spans [][]ResStringPoolSpan
+
+	// lazy is the string pool chunk, if the strings are read from it on demand.
+	// Strings is empty in that case.
+	lazy *io.SectionReader
 }
 
 // NilResStringPoolRef is nil reference for string pool.
@@ -115,8 +133,19 @@ type ResValue struct {
 }
 
 // GetString returns a string referenced by ref.
-// It panics if the pool doesn't contain ref.
+// It returns the empty string if the pool doesn't contain ref,
+// or if the string can't be read from the pool read lazily.
 func (pool *ResStringPool) GetString(ref ResStringPoolRef) string {
+	if !pool.HasString(ref) {
+		return ""
+	}
+	if pool.lazy != nil {
+		str, err := pool.readString(int(ref))
+		if err != nil {
+			return ""
+		}
+		return str
+	}
 	return pool.Strings[int(ref)]
 }
 
@@ -125,14 +154,59 @@ func (pool *ResStringPool) HasString(ref ResStringPoolRef) bool {
 	if pool == nil {
 		return false
 	}
+	if pool.lazy != nil {
+		return int(ref) >= 0 && int64(ref) < int64(pool.Header.StringCount)
+	}
 	return int(ref) >= 0 && int(ref) < len(pool.Strings)
 }
 
-func readStringPool(sr *io.SectionReader) (*ResStringPool, error) {
-	sp := new(ResStringPool)
+// allStrings returns all the strings in the pool.
+// The strings that can't be read are empty if the pool is read lazily.
+func (pool *ResStringPool) allStrings() []string {
+	if pool.lazy == nil {
+		return pool.Strings
+	}
+	strs := make([]string, pool.Header.StringCount)
+	for i := range strs {
+		strs[i] = pool.GetString(ResStringPoolRef(i))
+	}
+	return strs
+}
+
+// readString reads the i-th string from the string pool chunk.
+func (pool *ResStringPool) readString(i int) (string, error) {
+	var buf [4]byte
+	if _, err := pool.lazy.ReadAt(buf[:], int64(pool.Header.Header.HeaderSize)+4*int64(i)); err != nil {
+		return "", err
+	}
+	start := int64(pool.Header.StringStart) + int64(binary.LittleEndian.Uint32(buf[:]))
+	sr := newSectionReader(pool.lazy, start, pool.lazy.Size()-start)
+	if (pool.Header.Flags & UTF8Flag) == 0 {
+		return readUTF16(sr)
+	}
+	return readUTF8(sr)
+}
+
+// readStringPool reads the string pool chunk.
+// The errors are *ParseError of the offset relative to sr.
+func readStringPool(sr *io.SectionReader, p *parser) (sp *ResStringPool, err error) {
+	defer func() {
+		err = chunkError(ResStringPoolChunkType, 0, err)
+	}()
+
+	sp = new(ResStringPool)
 	if err := binary.Read(sr, binary.LittleEndian, &sp.Header); err != nil {
 		return nil, err
 	}
+	if err := checkStringPoolCounts(&sp.Header, sr.Size()); err != nil {
+		if err := p.recover(ResStringPoolChunkType, err); err != nil {
+			return nil, err
+		}
+		clampStringPoolCounts(&sp.Header, sr.Size())
+	}
+	if err := p.alloc(int64(sp.Header.StringCount)*(4+16) + int64(sp.Header.StyleCount)*(4+8)); err != nil {
+		return nil, err
+	}
 
 	stringStarts := make([]uint32, sp.Header.StringCount)
 	if err := binary.Read(sr, binary.LittleEndian, stringStarts); err != nil {
@@ -145,7 +219,13 @@ func readStringPool(sr *io.SectionReader) (*ResStringPool, error) {
 	}
 
 	sp.Strings = make([]string, sp.Header.StringCount)
+	var broken int
 	for i, start := range stringStarts {
+		if i%1024 == 0 {
+			if err := p.err(); err != nil {
+				return nil, err
+			}
+		}
 		var str string
 		var err error
 		if _, err := sr.Seek(int64(sp.Header.StringStart+start), io.SeekStart); err != nil {
@@ -157,24 +237,162 @@ func readStringPool(sr *io.SectionReader) (*ResStringPool, error) {
 			str, err = readUTF8(sr)
 		}
 		if err != nil {
+			if !p.lenient() {
+				return nil, err
+			}
+			// the broken strings are empty in the lenient mode.
+			broken++
+			continue
+		}
+		if err := p.alloc(int64(len(str))); err != nil {
 			return nil, err
 		}
 		sp.Strings[i] = str
 	}
+	if broken > 0 {
+		if err := p.recover(ResStringPoolChunkType, fmt.Errorf("androidbinary: %d broken strings", broken)); err != nil {
+			return nil, err
+		}
+	}
 
-	sp.Styles = make([]ResStringPoolSpan, sp.Header.StyleCount)
-	for i, start := range styleStarts {
-		if _, err := sr.Seek(int64(sp.Header.StylesStart+start), io.SeekStart); err != nil {
+	if err := readStringPoolStyles(sr, sp, styleStarts, p); err != nil {
+		if err := p.recover(ResStringPoolChunkType, err); err != nil {
 			return nil, err
 		}
-		if err := binary.Read(sr, binary.LittleEndian, &sp.Styles[i]); err != nil {
+		sp.Styles = nil
+		sp.Spans = nil
+	}
+	return sp, nil
+}
+
+// readLazyStringPool reads the header and the styles of the string pool.
+// The strings are read from sr on demand, so sr must be kept readable while the pool is used.
+func readLazyStringPool(sr *io.SectionReader, p *parser) (sp *ResStringPool, err error) {
+	defer func() {
+		err = chunkError(ResStringPoolChunkType, 0, err)
+	}()
+
+	sp = &ResStringPool{lazy: sr}
+	if err := binary.Read(sr, binary.LittleEndian, &sp.Header); err != nil {
+		return nil, err
+	}
+	if err := checkStringPoolCounts(&sp.Header, sr.Size()); err != nil {
+		if err := p.recover(ResStringPoolChunkType, err); err != nil {
 			return nil, err
 		}
+		clampStringPoolCounts(&sp.Header, sr.Size())
+	}
+	if err := p.alloc(int64(sp.Header.StyleCount) * (4 + 8)); err != nil {
+		return nil, err
 	}
 
+	styleStarts := make([]uint32, sp.Header.StyleCount)
+	if _, err := sr.Seek(int64(sp.Header.Header.HeaderSize)+4*int64(sp.Header.StringCount), io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(sr, binary.LittleEndian, styleStarts); err != nil {
+		return nil, err
+	}
+
+	if err := readStringPoolStyles(sr, sp, styleStarts, p); err != nil {
+		if err := p.recover(ResStringPoolChunkType, err); err != nil {
+			return nil, err
+		}
+		sp.Styles = nil
+		sp.Spans = nil
+	}
 	return sp, nil
 }
 
+// checkStringPoolCounts validates that the indexes of the strings and the styles fit in the chunk.
+func checkStringPoolCounts(header *ResStringPoolHeader, available int64) error {
+	body := chunkBodySize(&header.Header, available)
+	if 4*int64(header.StringCount) > body {
+		return &CountError{Type: header.Header.Type, Field: "StringCount", Count: header.StringCount}
+	}
+	if 4*(int64(header.StringCount)+int64(header.StyleCount)) > body {
+		return &CountError{Type: header.Header.Type, Field: "StyleCount", Count: header.StyleCount}
+	}
+	return nil
+}
+
+// checkChunkHeader validates the sizes of the chunk, so that the next chunk can be found.
+func checkChunkHeader(header *ResChunkHeader) error {
+	if header.HeaderSize < uint16(binary.Size(header)) {
+		return fmt.Errorf("androidbinary: invalid chunk header size: %d", header.HeaderSize)
+	}
+	if header.Size < uint32(header.HeaderSize) {
+		return fmt.Errorf("androidbinary: invalid chunk size: %d", header.Size)
+	}
+	return nil
+}
+
+// clampStringPoolCounts reduces the counts of the strings and the styles to fit in the chunk.
+func clampStringPoolCounts(header *ResStringPoolHeader, available int64) {
+	n := chunkBodySize(&header.Header, available) / 4
+	if n < 0 {
+		n = 0
+	}
+	if int64(header.StringCount) > n {
+		header.StringCount = uint32(n)
+	}
+	if int64(header.StringCount)+int64(header.StyleCount) > n {
+		header.StyleCount = uint32(n - int64(header.StringCount))
+	}
+}
+
+// resStringPoolSpanEnd is the name of the span that terminates the span list of a string.
+const resStringPoolSpanEnd = ResStringPoolRef(0xFFFFFFFF)
+
+func readStringPoolStyles(sr *io.SectionReader, sp *ResStringPool, styleStarts []uint32, p *parser) error {
+	sp.Styles = make([]ResStringPoolSpan, sp.Header.StyleCount)
+	sp.Spans = make([][]ResStringPoolSpan, sp.Header.StyleCount)
+
+	// a valid pool has each span in only one list, so the spans fit in the chunk.
+	// the lists of the same offset are decoded only once and shared,
+	// and the lists that overlap each other are rejected by the limit.
+	limit := chunkBodySize(&sp.Header.Header, sr.Size()) / int64(unsafe.Sizeof(ResStringPoolSpan{}))
+	var total int64
+	decoded := make(map[uint32][]ResStringPoolSpan)
+	for i, start := range styleStarts {
+		spans, ok := decoded[start]
+		if !ok {
+			if _, err := sr.Seek(int64(sp.Header.StylesStart)+int64(start), io.SeekStart); err != nil {
+				return err
+			}
+			// the span list is terminated by resStringPoolSpanEnd.
+			for {
+				var span ResStringPoolSpan
+				if err := binary.Read(sr, binary.LittleEndian, &span.Name); err != nil {
+					return err
+				}
+				if span.Name == resStringPoolSpanEnd {
+					break
+				}
+				if err := binary.Read(sr, binary.LittleEndian, &span.FirstChar); err != nil {
+					return err
+				}
+				if err := binary.Read(sr, binary.LittleEndian, &span.LastChar); err != nil {
+					return err
+				}
+				if total++; total > limit {
+					return &CountError{Type: ResStringPoolChunkType, Field: "StyleCount", Count: sp.Header.StyleCount}
+				}
+				if err := p.alloc(int64(unsafe.Sizeof(span))); err != nil {
+					return err
+				}
+				spans = append(spans, span)
+			}
+			decoded[start] = spans
+		}
+		sp.Spans[i] = spans
+		if len(spans) > 0 {
+			sp.Styles[i] = spans[0]
+		}
+	}
+	return nil
+}
+
 func readUTF16(sr *io.SectionReader) (string, error) {
 	// read length of string
 	size, err := readUTF16length(sr)
@@ -183,6 +401,9 @@ func readUTF16(sr *io.SectionReader) (string, error) {
 	}
 
 	// read string value
+	if err := checkStringLength(sr, 2*int64(size)); err != nil {
+		return "", err
+	}
 	buf := make([]uint16, size)
 	if err := binary.Read(sr, binary.LittleEndian, buf); err != nil {
 		return "", err
@@ -220,6 +441,9 @@ func readUTF8(sr *io.SectionReader) (string, error) {
 		return "", err
 	}
 
+	if err := checkStringLength(sr, int64(size)); err != nil {
+		return "", err
+	}
 	buf := make([]uint8, size)
 	if err := binary.Read(sr, binary.LittleEndian, buf); err != nil {
 		return "", err
@@ -244,6 +468,19 @@ func readUTF8length(sr *io.SectionReader) (int, error) {
 	return size, nil
 }
 
+// checkStringLength returns io.ErrUnexpectedEOF if sr has less than n bytes,
+// not to allocate the buffer for a broken length.
+func checkStringLength(sr *io.SectionReader, n int64) error {
+	offset, err := sr.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if n > sr.Size()-offset {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
 func newZeroFilledReader(r io.Reader, actual int64, expected int64) (io.Reader, error) {
 	if actual >= expected {
 		// no need to fill
@@ -2,6 +2,8 @@ package androidbinary
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"io"
 	"reflect"
 	"testing"
@@ -11,12 +13,13 @@ var readStringPoolTests = []struct {
 	input   []uint8
 	strings []string
 	styles  []ResStringPoolSpan
+	spans   [][]ResStringPoolSpan
 }{
 	{
 		[]uint8{
 			0x01, 0x00, // Type = RES_STRING_POOL_TYPE
 			0x1C, 0x00, // HeaderSize = 28 bytes
-			0x3C, 0x00, 0x00, 0x00, // Size = 60
+			0x54, 0x00, 0x00, 0x00, // Size = 84
 			0x02, 0x00, 0x00, 0x00, // StringCount = 2
 			0x02, 0x00, 0x00, 0x00, // StyleScount = 2
 			0x00, 0x00, 0x00, 0x00, // Flags = 0x00
@@ -29,20 +32,28 @@ var readStringPoolTests = []struct {
 
 			// StyleIndexes
 			0x00, 0x00, 0x00, 0x00,
-			0x08, 0x00, 0x00, 0x00,
+			0x10, 0x00, 0x00, 0x00,
 
 			// Strings
 			0x01, 0x00, 0x61, 0x00,
 			0x01, 0x00, 0x42, 0x30,
 
 			// Styles
-			0x01, 0x00, 0x00, 0x00,
-			0x02, 0x00, 0x00, 0x00,
-			0x03, 0x00, 0x00, 0x00,
-			0x04, 0x00, 0x00, 0x00,
+			0x01, 0x00, 0x00, 0x00, // Name = 1
+			0x00, 0x00, 0x00, 0x00, // FirstChar = 0
+			0x00, 0x00, 0x00, 0x00, // LastChar = 0
+			0xFF, 0xFF, 0xFF, 0xFF, // END
+			0x00, 0x00, 0x00, 0x00, // Name = 0
+			0x00, 0x00, 0x00, 0x00, // FirstChar = 0
+			0x00, 0x00, 0x00, 0x00, // LastChar = 0
+			0x01, 0x00, 0x00, 0x00, // Name = 1
+			0x00, 0x00, 0x00, 0x00, // FirstChar = 0
+			0x00, 0x00, 0x00, 0x00, // LastChar = 0
+			0xFF, 0xFF, 0xFF, 0xFF, // END
 		},
 		[]string{"a", "\u3042"},
-		[]ResStringPoolSpan{{1, 2}, {3, 4}},
+		[]ResStringPoolSpan{{Name: 1}, {Name: 0}},
+		[][]ResStringPoolSpan{{{Name: 1}}, {{Name: 0}, {Name: 1}}},
 	},
 }
 
@@ -50,7 +61,7 @@ func TestReadStringPool(t *testing.T) {
 	for _, tt := range readStringPoolTests {
 		buf := bytes.NewReader(tt.input)
 		sr := io.NewSectionReader(buf, 0, int64(len(tt.input)))
-		actual, err := readStringPool(sr)
+		actual, err := readStringPool(sr, nil)
 		if err != nil {
 			t.Errorf("got %v want no error", err)
 		}
@@ -60,6 +71,73 @@ func TestReadStringPool(t *testing.T) {
 		if !reflect.DeepEqual(actual.Styles, tt.styles) {
 			t.Errorf("got %v want %v", actual.Styles, tt.styles)
 		}
+		if !reflect.DeepEqual(actual.Spans, tt.spans) {
+			t.Errorf("got %v want %v", actual.Spans, tt.spans)
+		}
+	}
+}
+
+// encodeTestStyledPool encodes a string pool without strings,
+// of which the styles start at styleStarts in a list of n spans.
+func encodeTestStyledPool(styleStarts []uint32, n int) []byte {
+	headerSize := 28
+	stylesStart := headerSize + 4*len(styleStarts)
+	size := stylesStart + 12*n + 4
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, ResStringPoolHeader{
+		Header:      ResChunkHeader{Type: ResStringPoolChunkType, HeaderSize: uint16(headerSize), Size: uint32(size)},
+		StyleCount:  uint32(len(styleStarts)),
+		StylesStart: uint32(stylesStart),
+	})
+	binary.Write(buf, binary.LittleEndian, styleStarts)
+	for i := 0; i < n; i++ {
+		binary.Write(buf, binary.LittleEndian, ResStringPoolSpan{FirstChar: uint32(i), LastChar: uint32(i)})
+	}
+	binary.Write(buf, binary.LittleEndian, resStringPoolSpanEnd)
+	return buf.Bytes()
+}
+
+func TestReadStringPoolSharedSpans(t *testing.T) {
+	t.Run("same offset", func(t *testing.T) {
+		// all the styles point at the same list, which is decoded only once.
+		input := encodeTestStyledPool(make([]uint32, 1000), 100)
+		sp, err := readStringPool(io.NewSectionReader(bytes.NewReader(input), 0, int64(len(input))), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sp.Spans) != 1000 || len(sp.Spans[999]) != 100 {
+			t.Fatalf("unexpected spans: %d", len(sp.Spans))
+		}
+		if &sp.Spans[0][0] != &sp.Spans[999][0] {
+			t.Error("want the spans shared")
+		}
+	})
+
+	t.Run("overlapped", func(t *testing.T) {
+		// each style points at a suffix of the same list.
+		starts := make([]uint32, 100)
+		for i := range starts {
+			starts[i] = uint32(12 * i)
+		}
+		input := encodeTestStyledPool(starts, 100)
+		_, err := readStringPool(io.NewSectionReader(bytes.NewReader(input), 0, int64(len(input))), nil)
+		var countErr *CountError
+		if !errors.As(err, &countErr) {
+			t.Errorf("want CountError, got %v", err)
+		}
+	})
+}
+
+func TestResStringPoolGetStringOutOfRange(t *testing.T) {
+	pool := &ResStringPool{Strings: []string{"a"}}
+	for _, ref := range []ResStringPoolRef{1, NilResStringPoolRef} {
+		if got := pool.GetString(ref); got != "" {
+			t.Errorf("%d: want empty, got %q", ref, got)
+		}
+	}
+	var nilPool *ResStringPool
+	if got := nilPool.GetString(0); got != "" {
+		t.Errorf("want empty, got %q", got)
 	}
 }
 
@@ -99,6 +177,15 @@ func TestReadUTF16(t *testing.T) {
 	}
 }
 
+func TestReadUTF16TooLong(t *testing.T) {
+	// the length is 0x7FFFFFFF, but the data is missing.
+	input := []uint8{0xFF, 0xFF, 0xFF, 0xFF, 0x61, 0x00}
+	sr := io.NewSectionReader(bytes.NewReader(input), 0, int64(len(input)))
+	if _, err := readUTF16(sr); err != io.ErrUnexpectedEOF {
+		t.Errorf("got %v want io.ErrUnexpectedEOF", err)
+	}
+}
+
 var readUTF8Tests = []struct {
 	input  []uint8
 	output string
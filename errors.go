@@ -0,0 +1,88 @@
+package androidbinary
+
+import (
+	"fmt"
+)
+
+// FileKind is a kind of binary files.
+type FileKind int
+
+const (
+	// FileKindUnknown is the kind of the file that is not known,
+	// e.g. the string pool parsed alone.
+	FileKindUnknown FileKind = iota
+
+	// FileKindXML is a binary XML file, such as AndroidManifest.xml.
+	FileKindXML
+
+	// FileKindTable is a resource table, such as resources.arsc.
+	FileKindTable
+)
+
+func (k FileKind) String() string {
+	switch k {
+	case FileKindUnknown:
+		return "unknown"
+	case FileKindXML:
+		return "XML"
+	case FileKindTable:
+		return "resource table"
+	}
+	return fmt.Sprintf("FileKind(%d)", int(k))
+}
+
+// ParseError is an error in parsing a binary file.
+// Use errors.As and errors.Is to examine the cause, such as *InvalidReferenceError, *LimitError and io.ErrUnexpectedEOF.
+type ParseError struct {
+	// Kind is the kind of the file.
+	Kind FileKind
+
+	// Chunk is the type of the innermost chunk that failed to be parsed.
+	// It is ResNullChunkType if the chunk header can't be read.
+	Chunk ChunkType
+
+	// Offset is the byte offset of the chunk from the beginning of the file.
+	Offset int64
+
+	// Err is the cause of the error.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("androidbinary: failed to parse %s: chunk 0x%04X at offset %d: %v", e.Kind, uint16(e.Chunk), e.Offset, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// chunkError returns err as a *ParseError of the chunk at offset.
+// If err is already a *ParseError of a nested chunk, offset is added to its offset,
+// because the offsets of nested chunks are relative to their parents.
+func chunkError(chunk ChunkType, offset int64, err error) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*ParseError); ok {
+		e.Offset += offset
+		return e
+	}
+	return &ParseError{
+		Chunk:  chunk,
+		Offset: offset,
+		Err:    err,
+	}
+}
+
+// fileError returns err as a *ParseError of the file of kind.
+func fileError(kind FileKind, err error) error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*ParseError)
+	if !ok {
+		e = &ParseError{Err: err}
+	}
+	e.Kind = kind
+	return e
+}
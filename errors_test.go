@@ -0,0 +1,128 @@
+package androidbinary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// findTestTableType returns the offset of the first type chunk in the first package of the resource table.
+func findTestTableType(data []byte) int {
+	chunk := func(offset int) (ChunkType, int, int) {
+		return ChunkType(binary.LittleEndian.Uint16(data[offset:])),
+			int(binary.LittleEndian.Uint16(data[offset+2:])),
+			int(binary.LittleEndian.Uint32(data[offset+4:]))
+	}
+	offset := 12
+	for typ, _, size := chunk(offset); typ != ResTablePackageType; typ, _, size = chunk(offset) {
+		offset += size
+	}
+	_, headerSize, _ := chunk(offset)
+	offset += headerSize
+	for typ, _, size := chunk(offset); typ != ResTableTypeType; typ, _, size = chunk(offset) {
+		offset += size
+	}
+	return offset
+}
+
+func TestParseError(t *testing.T) {
+	t.Run("truncated XML", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/AndroidManifest.xml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = NewXMLFile(bytes.NewReader(data[:len(data)-10]))
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("want ParseError, got %v", err)
+		}
+		if parseErr.Kind != FileKindXML || parseErr.Chunk != ResXMLEndNamespaceType || parseErr.Offset <= 0 {
+			t.Errorf("unexpected error: %+v", parseErr)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("want io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		data := []byte{
+			0x03, 0x00, // Type = RES_XML_TYPE
+			0x08, 0x00, // HeaderSize = 8
+			0x48, 0x00, 0x00, 0x00, // Size = 72
+
+			0x01, 0x00, // Type = RES_STRING_POOL_TYPE
+			0x1C, 0x00, // HeaderSize = 28
+			0x1C, 0x00, 0x00, 0x00, // Size = 28
+			0x00, 0x00, 0x00, 0x00, // StringCount = 0
+			0x00, 0x00, 0x00, 0x00, // StyleCount = 0
+			0x00, 0x00, 0x00, 0x00, // Flags = 0x00
+			0x1C, 0x00, 0x00, 0x00, // StringStart = 28
+			0x00, 0x00, 0x00, 0x00, // StylesStart = 0
+
+			0x03, 0x01, // Type = RES_XML_END_ELEMENT_TYPE
+			0x10, 0x00, // HeaderSize = 16
+			0x18, 0x00, 0x00, 0x00, // Size = 24
+			0x00, 0x00, 0x00, 0x00, // LineNumber = 0
+			0xFF, 0xFF, 0xFF, 0xFF, // Comment = -1
+			0xFF, 0xFF, 0xFF, 0xFF, // NS = -1
+			0x05, 0x00, 0x00, 0x00, // Name = 5
+		}
+		_, err := NewXMLFile(bytes.NewReader(data))
+		var refErr *InvalidReferenceError
+		if !errors.As(err, &refErr) || refErr.Ref != 5 {
+			t.Errorf("want InvalidReferenceError, got %v", err)
+		}
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Chunk != ResXMLEndElementType || parseErr.Offset != 36 {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("string pool", func(t *testing.T) {
+		data := []byte{
+			0x01, 0x00, // Type = RES_STRING_POOL_TYPE
+			0x1C, 0x00, // HeaderSize = 28
+			0x20, 0x00, 0x00, 0x00, // Size = 32
+			0x01, 0x00, 0x00, 0x00, // StringCount = 1
+			0x00, 0x00, 0x00, 0x00, // StyleCount = 0
+			0x00, 0x00, 0x00, 0x00, // Flags = 0x00
+			0x20, 0x00, 0x00, 0x00, // StringStart = 32
+			0x00, 0x00, 0x00, 0x00, // StylesStart = 0
+			0x00, 0x00, 0x00, 0x00, // the index of the missing string
+		}
+		_, err := readStringPool(io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data))), nil)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Kind != FileKindUnknown || parseErr.Chunk != ResStringPoolChunkType || parseErr.Offset != 0 {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("nested chunk in table", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/resources.arsc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset := findTestTableType(data)
+		binary.LittleEndian.PutUint32(data[offset+12:], 0x7FFFFFFF)
+
+		_, err = NewTableFile(bytes.NewReader(data))
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("want ParseError, got %v", err)
+		}
+		if parseErr.Kind != FileKindTable || parseErr.Chunk != ResTableTypeType || parseErr.Offset != int64(offset) {
+			t.Errorf("want the type chunk at %d, got %+v", offset, parseErr)
+		}
+	})
+
+	t.Run("truncated table", func(t *testing.T) {
+		_, err := NewTableFile(bytes.NewReader([]byte{0x02, 0x00}))
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Kind != FileKindTable || parseErr.Chunk != ResTableChunkType {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
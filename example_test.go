@@ -36,6 +36,7 @@ func ExampleNewXMLFile() {
 	// 		<activity android:theme="" android:name="MapActivity" android:label="" android:screenOrientation="0"></activity>
 	// 		<activity android:theme="" android:name="SettingActivity" android:label="" android:screenOrientation=""></activity>
 	// 		<activity android:theme="" android:name="PlaceSettingActivity" android:label="" android:screenOrientation=""></activity>
+	// 		<uses-library android:name="com.google.android.maps"></uses-library>
 	// 	</application>
 	// 	<instrumentation android:name="" android:targetPackage="" android:handleProfiling="false" android:functionalTest="false"></instrumentation>
 	// 	<uses-sdk android:minSdkVersion="0" android:targetSdkVersion="0" android:maxSdkVersion="0"></uses-sdk>
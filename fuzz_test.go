@@ -5,6 +5,8 @@ package androidbinary
 
 import (
 	"bytes"
+	"encoding/binary"
+	"io"
 	"os"
 	"testing"
 )
@@ -17,9 +19,139 @@ func FuzzNewXMLFile(f *testing.F) {
 	f.Add(data)
 
 	f.Fuzz(func(t *testing.T, data []byte) {
+		if xmlFile, err := NewXMLFileWithOptions(bytes.NewReader(data), &ParseOptions{Lenient: true}); err == nil {
+			var v struct{}
+			xmlFile.Decode(&v, nil, nil)
+		}
+
 		_, err := NewXMLFile(bytes.NewReader(data))
 		if err != nil {
 			t.Skip(err)
 		}
 	})
 }
+
+func FuzzNewTableFile(f *testing.F) {
+	data, err := os.ReadFile("testdata/resources.arsc")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+
+	// all the entries of the first type point at one complex entry, which spans the rest of the chunk.
+	amplified := append([]byte(nil), data...)
+	offset := findTestTableType(amplified)
+	headerSize := int(binary.LittleEndian.Uint16(amplified[offset+2:]))
+	size := int(binary.LittleEndian.Uint32(amplified[offset+4:]))
+	entryCount := int(binary.LittleEndian.Uint32(amplified[offset+12:]))
+	entriesStart := int(binary.LittleEndian.Uint32(amplified[offset+16:]))
+	for i := 0; i < entryCount; i++ {
+		binary.LittleEndian.PutUint32(amplified[offset+headerSize+4*i:], 0)
+	}
+	entry := amplified[offset+entriesStart:]
+	binary.LittleEndian.PutUint16(entry[0:], 16)
+	binary.LittleEndian.PutUint16(entry[2:], EntryFlagComplex)
+	binary.LittleEndian.PutUint32(entry[12:], uint32((size-entriesStart-16)/12))
+	f.Add(amplified)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, opts := range []*ParseOptions{nil, {Lazy: true}, {Lenient: true}, {Lazy: true, Lenient: true}} {
+			table, err := NewTableFileWithOptions(bytes.NewReader(data), opts)
+			if err != nil {
+				continue
+			}
+			// look up all the resources in the table, including the string values.
+			for _, p := range table.tablePackages {
+				for _, typ := range p.TableTypes {
+					for i := range typ.allEntries() {
+						id := ResID(p.Header.ID<<24 | uint32(typ.Header.ID)<<16 | uint32(i))
+						table.GetResource(id, nil)
+						table.GetResource(id, &typ.Header.Config)
+					}
+				}
+			}
+			MergeTableFiles(table, table)
+		}
+	})
+}
+
+func FuzzReadStringPool(f *testing.F) {
+	for _, tt := range readStringPoolTests {
+		f.Add(tt.input)
+	}
+
+	// UTF-8 string pool
+	f.Add([]byte{
+		0x01, 0x00, // Type = RES_STRING_POOL_TYPE
+		0x1C, 0x00, // HeaderSize = 28 bytes
+		0x2C, 0x00, 0x00, 0x00, // Size = 44
+		0x02, 0x00, 0x00, 0x00, // StringCount = 2
+		0x00, 0x00, 0x00, 0x00, // StyleScount = 0
+		0x00, 0x01, 0x00, 0x00, // Flags = UTF8Flag
+		0x24, 0x00, 0x00, 0x00, // StringStart = 36
+		0x00, 0x00, 0x00, 0x00, // StylesStart = 0
+
+		// StringIndexes
+		0x00, 0x00, 0x00, 0x00,
+		0x04, 0x00, 0x00, 0x00,
+
+		// Strings
+		0x01, 0x01, 0x61, 0x00,
+		0x01, 0x03, 0xE3, 0x81, 0x82, 0x00,
+	})
+
+	// the styles point at the same span list, and at the suffixes of it.
+	f.Add(encodeTestStyledPool(make([]uint32, 1000), 100))
+	suffixes := make([]uint32, 100)
+	for i := range suffixes {
+		suffixes[i] = uint32(12 * i)
+	}
+	f.Add(encodeTestStyledPool(suffixes, 100))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sr := io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data)))
+		for _, lenient := range []bool{false, true} {
+			p := newParser(FileKindUnknown, &ParseOptions{Lenient: lenient})
+			if sp, err := readStringPool(sr, p); err == nil {
+				for i := range sp.Strings {
+					sp.GetStyledString(ResStringPoolRef(i))
+				}
+				sp.GetString(NilResStringPoolRef)
+			}
+			if sp, err := readLazyStringPool(sr, p); err == nil {
+				sp.allStrings()
+				sp.GetString(NilResStringPoolRef)
+			}
+		}
+	})
+}
+
+func FuzzResTableConfig(f *testing.F) {
+	size := binary.Size(ResTableConfig{})
+	f.Add(make([]byte, 3*size))
+	f.Add(bytes.Repeat([]byte{0xFF}, 3*size))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) < 3*size {
+			t.Skip()
+		}
+		var configs [3]ResTableConfig
+		if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &configs); err != nil {
+			t.Skip(err)
+		}
+		a, b, r := &configs[0], &configs[1], &configs[2]
+
+		a.Match(r)
+		a.IsMoreSpecificThan(b)
+		a.IsBetterThan(b, r)
+		a.IsBetterThan(b, nil)
+		a.IsLocaleMoreSpecificThan(b)
+		a.IsLocaleBetterThan(b, r)
+		a.Locale()
+
+		// a configuration is never better than itself.
+		if a.IsBetterThan(a, r) {
+			t.Errorf("%+v is better than itself for %+v", a, r)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// Command attrs generates the names of the framework attributes from android.jar of the Android SDK.
+//
+// To update the names, run go generate in the root of the module with ANDROID_HOME set.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+func main() {
+	input := flag.String("i", "android.jar", "the path to android.jar, or resources.arsc in it")
+	output := flag.String("o", "attrs_table.go", "the path to the generated file")
+	flag.Parse()
+
+	data, err := readResources(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	table, err := androidbinary.NewTableFileWithOptions(bytes.NewReader(data), &androidbinary.ParseOptions{Lenient: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	// the directory of android.jar is the platform, such as android-16.
+	source := path.Join(path.Base(path.Dir(*input)), path.Base(*input))
+	fmt.Fprintf(&buf, "// Code generated by internal/gen/attrs from %s. DO NOT EDIT.\n\n", source)
+	buf.WriteString("package androidbinary\n\n")
+	buf.WriteString("// frameworkAttrNames is the names of the attributes of the Android framework, keyed by the resource IDs.\n")
+	buf.WriteString("var frameworkAttrNames = map[ResID]string{\n")
+	for _, id := range table.ResourceIDs() {
+		if id.Package() != 0x01 {
+			continue
+		}
+		typ, name, err := table.GetResourceName(id)
+		if err != nil || typ != "attr" || name == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "0x%08X: %q,\n", uint32(id), name)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readResources reads resources.arsc from android.jar, or reads the file itself if it is not a jar.
+func readResources(name string) ([]byte, error) {
+	if path.Ext(name) != ".jar" {
+		return ioutil.ReadFile(name)
+	}
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	for _, file := range r.File {
+		if file.Name != "resources.arsc" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("resources.arsc not found in %s", name)
+}
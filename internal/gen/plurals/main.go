@@ -0,0 +1,278 @@
+// Command plurals generates the plural rules of the integers from plurals.xml of CLDR.
+//
+// To update the rules, copy common/supplemental/plurals.xml of the latest CLDR release
+// to this directory, and run go generate in the root of the module.
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type supplementalData struct {
+	Plurals []struct {
+		Type  string `xml:"type,attr"`
+		Rules []struct {
+			Locales string `xml:"locales,attr"`
+			Rules   []struct {
+				Count     string `xml:"count,attr"`
+				Condition string `xml:",chardata"`
+			} `xml:"pluralRule"`
+		} `xml:"pluralRules"`
+	} `xml:"plurals"`
+}
+
+var quantities = map[string]string{
+	"zero":  "PluralZero",
+	"one":   "PluralOne",
+	"two":   "PluralTwo",
+	"few":   "PluralFew",
+	"many":  "PluralMany",
+	"other": "PluralOther",
+}
+
+func main() {
+	input := flag.String("i", "plurals.xml", "the path to plurals.xml of CLDR")
+	output := flag.String("o", "plurals_table.go", "the path to the generated file")
+	flag.Parse()
+
+	data, err := ioutil.ReadFile(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var supplemental supplementalData
+	if err := xml.Unmarshal(data, &supplemental); err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/gen/plurals from plurals.xml of CLDR. DO NOT EDIT.\n\n")
+	buf.WriteString("package androidbinary\n\n")
+
+	rules := map[string]string{}
+	for _, plurals := range supplemental.Plurals {
+		if plurals.Type != "cardinal" {
+			continue
+		}
+		for _, r := range plurals.Rules {
+			locales := strings.Fields(r.Locales)
+			name := funcName(locales[0])
+			for _, locale := range locales {
+				rules[strings.ToLower(locale)] = name
+			}
+
+			fmt.Fprintf(&buf, "// %s is the plural rule of %s.\n", name, strings.Join(locales, ", "))
+			fmt.Fprintf(&buf, "func %s(n int) PluralQuantity {\n", name)
+			var cases bytes.Buffer
+			always := ""
+			for _, rule := range r.Rules {
+				q, ok := quantities[rule.Count]
+				if !ok {
+					log.Fatalf("unknown count %q of %s", rule.Count, r.Locales)
+				}
+				if rule.Count == "other" {
+					continue
+				}
+				cond, err := compile(rule.Condition)
+				if err != nil {
+					log.Fatalf("%s of %s: %v", rule.Count, r.Locales, err)
+				}
+				if cond == "false" {
+					continue
+				}
+				if cond == "true" {
+					always = q
+					break
+				}
+				fmt.Fprintf(&cases, "case %s:\nreturn %s\n", cond, q)
+			}
+			if cases.Len() > 0 {
+				fmt.Fprintf(&buf, "switch {\n%s}\n", cases.String())
+			}
+			if always == "" {
+				always = "PluralOther"
+			}
+			fmt.Fprintf(&buf, "return %s\n}\n\n", always)
+		}
+	}
+
+	locales := make([]string, 0, len(rules))
+	for locale := range rules {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	buf.WriteString("// pluralRules is the cardinal plural rules of CLDR for the integers, keyed by the lower-cased locales.\n")
+	buf.WriteString("var pluralRules = map[string]pluralRule{\n")
+	for _, locale := range locales {
+		fmt.Fprintf(&buf, "%q: %s,\n", locale, rules[locale])
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// funcName returns the name of the function of the rule, such as pluralRuleEn and pluralRulePtPT.
+func funcName(locale string) string {
+	var name strings.Builder
+	name.WriteString("pluralRule")
+	for _, part := range strings.Split(locale, "_") {
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return name.String()
+}
+
+// compile compiles the condition of the rule into the Go expression of the integer n.
+// The operands of the fraction digits, v, w, f, t, e and c, are always zero for the integers,
+// so the relations of them are evaluated in advance.
+// It returns "true" or "false" if the condition is constant.
+func compile(condition string) (string, error) {
+	if i := strings.Index(condition, "@"); i >= 0 {
+		condition = condition[:i]
+	}
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return "true", nil
+	}
+
+	var ors []string
+	for _, or := range strings.Split(condition, " or ") {
+		var ands []string
+		isFalse := false
+		for _, and := range strings.Split(or, " and ") {
+			expr, err := compileRelation(strings.TrimSpace(and))
+			if err != nil {
+				return "", err
+			}
+			if expr == "false" {
+				isFalse = true
+				break
+			}
+			if expr != "true" {
+				ands = append(ands, expr)
+			}
+		}
+		if isFalse {
+			continue
+		}
+		if len(ands) == 0 {
+			return "true", nil
+		}
+		if len(ands) > 1 {
+			// the lists of the values are parenthesized, because && is evaluated before ||.
+			for i, expr := range ands {
+				if strings.Contains(expr, " || ") {
+					ands[i] = "(" + expr + ")"
+				}
+			}
+		}
+		ors = append(ors, strings.Join(ands, " && "))
+	}
+	if len(ors) == 0 {
+		return "false", nil
+	}
+	return strings.Join(ors, " || "), nil
+}
+
+// compileRelation compiles the relation such as "n % 10 = 2..4" and "i != 1".
+func compileRelation(relation string) (string, error) {
+	negate := false
+	var lhs, rhs string
+	if i := strings.Index(relation, "!="); i >= 0 {
+		negate = true
+		lhs, rhs = relation[:i], relation[i+2:]
+	} else if i := strings.Index(relation, "="); i >= 0 {
+		lhs, rhs = relation[:i], relation[i+1:]
+	} else {
+		return "", fmt.Errorf("invalid relation: %q", relation)
+	}
+
+	// the left hand side is an operand with an optional modulus.
+	fields := strings.Fields(lhs)
+	var operand string
+	mod := 0
+	switch len(fields) {
+	case 1:
+		operand = fields[0]
+	case 3:
+		if fields[1] != "%" {
+			return "", fmt.Errorf("invalid expression: %q", lhs)
+		}
+		operand = fields[0]
+		var err error
+		if mod, err = strconv.Atoi(fields[2]); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("invalid expression: %q", lhs)
+	}
+
+	// the right hand side is a list of the values and the ranges.
+	type valueRange struct{ from, to int }
+	var ranges []valueRange
+	for _, item := range strings.Split(strings.TrimSpace(rhs), ",") {
+		from, to := item, item
+		if i := strings.Index(item, ".."); i >= 0 {
+			from, to = item[:i], item[i+2:]
+		}
+		f, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return "", err
+		}
+		t, err := strconv.Atoi(strings.TrimSpace(to))
+		if err != nil {
+			return "", err
+		}
+		ranges = append(ranges, valueRange{f, t})
+	}
+
+	switch operand {
+	case "n", "i":
+	case "v", "w", "f", "t", "e", "c":
+		// the constant zero for the integers.
+		in := false
+		for _, r := range ranges {
+			if r.from <= 0 && 0 <= r.to {
+				in = true
+			}
+		}
+		return strconv.FormatBool(in != negate), nil
+	default:
+		return "", fmt.Errorf("unknown operand: %q", operand)
+	}
+
+	x := "n"
+	if mod != 0 {
+		x = fmt.Sprintf("n%%%d", mod)
+	}
+	var exprs []string
+	for _, r := range ranges {
+		switch {
+		case r.from == r.to && negate:
+			exprs = append(exprs, fmt.Sprintf("%s != %d", x, r.from))
+		case r.from == r.to:
+			exprs = append(exprs, fmt.Sprintf("%s == %d", x, r.from))
+		case negate:
+			exprs = append(exprs, fmt.Sprintf("!inRange(%s, %d, %d)", x, r.from, r.to))
+		default:
+			exprs = append(exprs, fmt.Sprintf("inRange(%s, %d, %d)", x, r.from, r.to))
+		}
+	}
+	if negate {
+		return strings.Join(exprs, " && "), nil
+	}
+	return strings.Join(exprs, " || "), nil
+}
@@ -0,0 +1,130 @@
+package l10n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// formatArgs is the arguments of a format string, mapping the argument indexes from 1 to their kinds.
+type formatArgs map[int]byte
+
+// parseFormat parses the format specifiers of s, such as %1$s and %d,
+// in the same way as java.util.Formatter which is used by Resources.getString.
+// The kinds of the arguments are normalized, e.g. both %d and %x are 'd'.
+func parseFormat(s string) formatArgs {
+	args := make(formatArgs)
+	var ordinary, last int
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		j := i + 1
+
+		// argument index, such as 1$.
+		index := 0
+		k := j
+		for k < len(s) && '0' <= s[k] && s[k] <= '9' {
+			k++
+		}
+		if k > j && k < len(s) && s[k] == '$' {
+			index, _ = strconv.Atoi(s[j:k])
+			j = k + 1
+		}
+
+		// flags, width and precision.
+		relative := false
+		for j < len(s) && strings.IndexByte("-#+ 0,(<", s[j]) >= 0 {
+			if s[j] == '<' {
+				relative = true
+			}
+			j++
+		}
+		for j < len(s) && ('0' <= s[j] && s[j] <= '9' || s[j] == '.') {
+			j++
+		}
+		if j >= len(s) {
+			break
+		}
+
+		conv := s[j]
+		if conv == 't' || conv == 'T' {
+			// date/time conversions have a suffix, such as %tY.
+			j++
+		}
+		kind, ok := formatKind(conv)
+		if !ok {
+			// not a format specifier.
+			continue
+		}
+		i = j
+		if kind == 0 {
+			// %% and %n don't consume any argument.
+			continue
+		}
+
+		switch {
+		case relative:
+			index = last
+		case index == 0:
+			ordinary++
+			index = ordinary
+		}
+		if index <= 0 {
+			continue
+		}
+		args[index] = kind
+		last = index
+	}
+	return args
+}
+
+// formatKind returns the normalized kind of the conversion, or 0 if it doesn't consume any argument.
+func formatKind(conv byte) (byte, bool) {
+	switch conv {
+	case '%', 'n':
+		return 0, true
+	case 's', 'S':
+		return 's', true
+	case 'b', 'B':
+		return 'b', true
+	case 'h', 'H':
+		return 'h', true
+	case 'c', 'C':
+		return 'c', true
+	case 'd', 'o', 'x', 'X':
+		return 'd', true
+	case 'e', 'E', 'f', 'g', 'G', 'a', 'A':
+		return 'f', true
+	case 't', 'T':
+		return 't', true
+	}
+	return 0, false
+}
+
+// equal returns whether the arguments are same as o.
+func (args formatArgs) equal(o formatArgs) bool {
+	if len(args) != len(o) {
+		return false
+	}
+	for i, kind := range args {
+		if o[i] != kind {
+			return false
+		}
+	}
+	return true
+}
+
+func (args formatArgs) String() string {
+	indexes := make([]int, 0, len(args))
+	for i := range args {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	specs := make([]string, len(indexes))
+	for i, index := range indexes {
+		specs[i] = fmt.Sprintf("%%%d$%c", index, args[index])
+	}
+	return "[" + strings.Join(specs, " ") + "]"
+}
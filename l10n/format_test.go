@@ -0,0 +1,26 @@
+package l10n
+
+import "testing"
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"no format", "[]"},
+		{"100%% sure%n", "[]"},
+		{"%s and %d", "[%1$s %2$d]"},
+		{"%2$s %1$x", "[%1$d %2$s]"},
+		{"%1$s %s %s", "[%1$s %2$s]"},
+		{"%.2f %-10S %,d", "[%1$f %2$s %3$d]"},
+		{"%tY-%<tm", "[%1$t]"},
+		{"%1$tY %2$b %3$c", "[%1$t %2$b %3$c]"},
+		{"50%!", "[]"},
+		{"trailing %", "[]"},
+	}
+	for _, tt := range tests {
+		if got := parseFormat(tt.in).String(); got != tt.want {
+			t.Errorf("%q: want %s, got %s", tt.in, tt.want, got)
+		}
+	}
+}
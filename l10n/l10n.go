@@ -0,0 +1,231 @@
+// Package l10n checks the localized strings of resource tables.
+//
+// It compares the translated strings and plurals to the values of the default locale,
+// and reports the mismatched format specifiers, the missing plural quantities and the untranslated strings,
+// as Android Lint does for the source files.
+package l10n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// IssueType is a type of localization issues.
+type IssueType int
+
+const (
+	// FormatMismatch is reported if the format specifiers of a translated string, such as %1$s and %d,
+	// don't match the default string.
+	FormatMismatch IssueType = iota + 1
+
+	// MissingQuantity is reported if a translated plurals resource lacks a quantity used by its language.
+	MissingQuantity
+
+	// Untranslated is reported if a string or plurals resource has no translation for a locale of the app.
+	Untranslated
+)
+
+func (t IssueType) String() string {
+	switch t {
+	case FormatMismatch:
+		return "FormatMismatch"
+	case MissingQuantity:
+		return "MissingQuantity"
+	case Untranslated:
+		return "Untranslated"
+	}
+	return fmt.Sprintf("IssueType(%d)", int(t))
+}
+
+// Issue is a localization issue of a resource.
+type Issue struct {
+	Type IssueType
+	ID   androidbinary.ResID
+
+	// Name is the name of the resource, such as "string/app_name".
+	Name string
+
+	// Locale is the locale of the translation, such as "ja" and "en-US".
+	Locale string
+
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", i.Name, i.Locale, i.Type, i.Message)
+}
+
+// Options is options for Check.
+type Options struct {
+	// Locales is the locales to check, such as "ja" and "en-US".
+	// If it is empty, all the locales of the strings and the plurals in the table are checked.
+	Locales []string
+}
+
+// Check checks the localized strings and plurals of table.
+// The issues are ordered by the resource IDs and the locales.
+func Check(table *androidbinary.TableFile, opts *Options) []Issue {
+	var resources []*resource
+	for _, id := range table.ResourceIDs() {
+		typ, name, err := table.GetResourceName(id)
+		if err != nil || (typ != "string" && typ != "plurals") {
+			continue
+		}
+		resources = append(resources, newResource(table, id, typ+"/"+name))
+	}
+
+	var locales []string
+	if opts != nil && len(opts.Locales) > 0 {
+		locales = opts.Locales
+	} else {
+		locales = allLocales(resources)
+	}
+
+	var issues []Issue
+	for _, r := range resources {
+		if r.def == nil {
+			// the resource is only for some locales.
+			continue
+		}
+		for _, locale := range locales {
+			issues = append(issues, r.check(table, locale)...)
+		}
+	}
+	return issues
+}
+
+// resource is a string or plurals resource.
+type resource struct {
+	id   androidbinary.ResID
+	name string
+	typ  string
+
+	// def is the entry of the default locale.
+	def *androidbinary.ConfigEntry
+
+	// translations is the entries of the locales, such as "ja" and "en-US".
+	translations map[string]*androidbinary.ConfigEntry
+}
+
+func newResource(table *androidbinary.TableFile, id androidbinary.ResID, name string) *resource {
+	r := &resource{
+		id:           id,
+		name:         name,
+		typ:          name[:strings.IndexByte(name, '/')],
+		translations: make(map[string]*androidbinary.ConfigEntry),
+	}
+	for _, e := range table.GetEntries(id) {
+		e := e
+		locale := e.Config.Locale()
+		if locale == "" {
+			r.def = lessSpecific(r.def, &e)
+			continue
+		}
+		r.translations[locale] = lessSpecific(r.translations[locale], &e)
+	}
+	return r
+}
+
+// lessSpecific returns the entry of the less specific configuration,
+// e.g. "values-ja" rather than "values-ja-land".
+func lessSpecific(a, b *androidbinary.ConfigEntry) *androidbinary.ConfigEntry {
+	if a == nil || a.Config.IsMoreSpecificThan(&b.Config) {
+		return b
+	}
+	return a
+}
+
+// allLocales returns the locales of the resources in ascending order.
+func allLocales(resources []*resource) []string {
+	seen := make(map[string]bool)
+	var locales []string
+	for _, r := range resources {
+		for locale := range r.translations {
+			if !seen[locale] {
+				seen[locale] = true
+				locales = append(locales, locale)
+			}
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// translation returns the entry used for the locale.
+// The language without the region is used as a fallback, e.g. "en" for "en-US".
+func (r *resource) translation(locale string) *androidbinary.ConfigEntry {
+	if e, ok := r.translations[locale]; ok {
+		return e
+	}
+	return r.translations[language(locale)]
+}
+
+func (r *resource) check(table *androidbinary.TableFile, locale string) []Issue {
+	issue := func(typ IssueType, format string, args ...interface{}) Issue {
+		return Issue{
+			Type:    typ,
+			ID:      r.id,
+			Name:    r.name,
+			Locale:  locale,
+			Message: fmt.Sprintf(format, args...),
+		}
+	}
+
+	e := r.translation(locale)
+	if e == nil {
+		return []Issue{issue(Untranslated, "not translated")}
+	}
+
+	var issues []Issue
+	switch r.typ {
+	case "string":
+		def, ok := stringValue(table, &r.def.Entry)
+		if !ok {
+			break
+		}
+		s, ok := stringValue(table, &e.Entry)
+		if !ok {
+			break
+		}
+		want, got := parseFormat(def), parseFormat(s)
+		if !want.equal(got) {
+			issues = append(issues, issue(FormatMismatch, "format arguments %v don't match the default %v", got, want))
+		}
+	case "plurals":
+		has := make(map[androidbinary.PluralQuantity]bool)
+		for _, m := range e.Entry.Map {
+			if q, ok := androidbinary.PluralQuantityOf(m.Name); ok {
+				has[q] = true
+			}
+		}
+		var missing []string
+		for _, q := range androidbinary.PluralQuantities(locale) {
+			if !has[q] {
+				missing = append(missing, string(q))
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, issue(MissingQuantity, "missing quantities: %s", strings.Join(missing, ", ")))
+		}
+	}
+	return issues
+}
+
+// stringValue returns the string of the simple entry.
+func stringValue(table *androidbinary.TableFile, e *androidbinary.TableEntry) (string, bool) {
+	if e.Value == nil || e.Value.DataType != androidbinary.TypeString {
+		return "", false
+	}
+	return table.GetString(androidbinary.ResStringPoolRef(e.Value.Data)), true
+}
+
+// language returns the language of the locale, e.g. "en" for "en-US".
+func language(locale string) string {
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
@@ -0,0 +1,271 @@
+package l10n
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// testEntry is an entry of the resource table for tests.
+type testEntry struct {
+	typ    string // "string" or "plurals"
+	name   string
+	locale string // such as "", "ja" and "en-US"
+
+	value   string
+	plurals map[androidbinary.ResID]string
+}
+
+// encodeTestStringPool encodes strs as a UTF-16 string pool chunk.
+func encodeTestStringPool(strs []string) []byte {
+	var data bytes.Buffer
+	indexes := make([]uint32, len(strs))
+	for i, s := range strs {
+		indexes[i] = uint32(data.Len())
+		units := utf16.Encode([]rune(s))
+		binary.Write(&data, binary.LittleEndian, uint16(len(units)))
+		binary.Write(&data, binary.LittleEndian, units)
+		binary.Write(&data, binary.LittleEndian, uint16(0))
+	}
+	for data.Len()%4 != 0 {
+		data.WriteByte(0)
+	}
+
+	headerSize := uint32(binary.Size(androidbinary.ResStringPoolHeader{}))
+	header := androidbinary.ResStringPoolHeader{
+		Header: androidbinary.ResChunkHeader{
+			Type:       androidbinary.ResStringPoolChunkType,
+			HeaderSize: uint16(headerSize),
+			Size:       headerSize + 4*uint32(len(strs)) + uint32(data.Len()),
+		},
+		StringCount: uint32(len(strs)),
+		StringStart: headerSize + 4*uint32(len(strs)),
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, header)
+	binary.Write(&buf, binary.LittleEndian, indexes)
+	buf.Write(data.Bytes())
+	return buf.Bytes()
+}
+
+// encodeTestTable encodes the entries as a resource table of the package 0x7F.
+// The types and the entries are numbered in the order of their first appearance.
+func encodeTestTable(t testing.TB, entries []testEntry) []byte {
+	t.Helper()
+
+	var strs, types, keys []string
+	index := func(list *[]string, s string) uint32 {
+		for i, v := range *list {
+			if v == s {
+				return uint32(i)
+			}
+		}
+		*list = append(*list, s)
+		return uint32(len(*list) - 1)
+	}
+
+	// group the entries by their types and locales.
+	type chunkKey struct {
+		typ    uint32
+		locale string
+	}
+	var chunkKeys []chunkKey
+	chunks := make(map[chunkKey]map[uint32][]byte)
+	entryIndexes := make(map[string]uint32)
+	counts := make(map[uint32]uint32)
+	for _, e := range entries {
+		typ := index(&types, e.typ)
+		name := e.typ + "/" + e.name
+		i, ok := entryIndexes[name]
+		if !ok {
+			i = counts[typ]
+			counts[typ]++
+			entryIndexes[name] = i
+		}
+
+		var buf bytes.Buffer
+		key := index(&keys, e.name)
+		if e.plurals == nil {
+			binary.Write(&buf, binary.LittleEndian, androidbinary.ResTableEntry{Size: 8, Key: androidbinary.ResStringPoolRef(key)})
+			binary.Write(&buf, binary.LittleEndian, androidbinary.ResValue{Size: 8, DataType: androidbinary.TypeString, Data: index(&strs, e.value)})
+		} else {
+			binary.Write(&buf, binary.LittleEndian, androidbinary.ResTableEntry{Size: 16, Flags: androidbinary.EntryFlagComplex, Key: androidbinary.ResStringPoolRef(key)})
+			binary.Write(&buf, binary.LittleEndian, []uint32{0, uint32(len(e.plurals))})
+			for _, attr := range []androidbinary.ResID{androidbinary.AttrPluralOther, androidbinary.AttrPluralZero, androidbinary.AttrPluralOne, androidbinary.AttrPluralTwo, androidbinary.AttrPluralFew, androidbinary.AttrPluralMany} {
+				if v, ok := e.plurals[attr]; ok {
+					binary.Write(&buf, binary.LittleEndian, uint32(attr))
+					binary.Write(&buf, binary.LittleEndian, androidbinary.ResValue{Size: 8, DataType: androidbinary.TypeString, Data: index(&strs, v)})
+				}
+			}
+		}
+
+		k := chunkKey{typ: typ, locale: e.locale}
+		if chunks[k] == nil {
+			chunks[k] = make(map[uint32][]byte)
+			chunkKeys = append(chunkKeys, k)
+		}
+		chunks[k][i] = buf.Bytes()
+	}
+
+	// encode the package.
+	var body bytes.Buffer
+	for _, k := range chunkKeys {
+		config := androidbinary.ResTableConfig{Size: uint32(binary.Size(androidbinary.ResTableConfig{}))}
+		if k.locale != "" {
+			copy(config.Language[:], k.locale[:2])
+			if len(k.locale) == 5 {
+				copy(config.Country[:], k.locale[3:])
+			}
+		}
+		count := counts[k.typ]
+		indexes := make([]uint32, count)
+		var data bytes.Buffer
+		for i := range indexes {
+			e, ok := chunks[k][uint32(i)]
+			if !ok {
+				indexes[i] = 0xFFFFFFFF
+				continue
+			}
+			indexes[i] = uint32(data.Len())
+			data.Write(e)
+		}
+		headerSize := uint32(binary.Size(androidbinary.ResTableType{}))
+		header := androidbinary.ResTableType{
+			Header: androidbinary.ResChunkHeader{
+				Type:       androidbinary.ResTableTypeType,
+				HeaderSize: uint16(headerSize),
+				Size:       headerSize + 4*count + uint32(data.Len()),
+			},
+			ID:           uint8(k.typ + 1),
+			EntryCount:   count,
+			EntriesStart: headerSize + 4*count,
+			Config:       config,
+		}
+		binary.Write(&body, binary.LittleEndian, header)
+		binary.Write(&body, binary.LittleEndian, indexes)
+		body.Write(data.Bytes())
+	}
+	typeStrings := encodeTestStringPool(types)
+	keyStrings := encodeTestStringPool(keys)
+	packageHeaderSize := uint32(binary.Size(androidbinary.ResTablePackage{}))
+	pkg := androidbinary.ResTablePackage{
+		Header: androidbinary.ResChunkHeader{
+			Type:       androidbinary.ResTablePackageType,
+			HeaderSize: uint16(packageHeaderSize),
+			Size:       packageHeaderSize + uint32(len(typeStrings)+len(keyStrings)+body.Len()),
+		},
+		ID:          0x7F,
+		TypeStrings: packageHeaderSize,
+		KeyStrings:  packageHeaderSize + uint32(len(typeStrings)),
+	}
+	var pkgBuf bytes.Buffer
+	binary.Write(&pkgBuf, binary.LittleEndian, pkg)
+	pkgBuf.Write(typeStrings)
+	pkgBuf.Write(keyStrings)
+	pkgBuf.Write(body.Bytes())
+
+	// encode the table.
+	globalStrings := encodeTestStringPool(strs)
+	tableHeaderSize := uint32(binary.Size(androidbinary.ResTableHeader{}))
+	header := androidbinary.ResTableHeader{
+		Header: androidbinary.ResChunkHeader{
+			Type:       androidbinary.ResTableChunkType,
+			HeaderSize: uint16(tableHeaderSize),
+			Size:       tableHeaderSize + uint32(len(globalStrings)+pkgBuf.Len()),
+		},
+		PackageCount: 1,
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, header)
+	buf.Write(globalStrings)
+	buf.Write(pkgBuf.Bytes())
+	return buf.Bytes()
+}
+
+func newTestTable(t testing.TB, entries []testEntry) *androidbinary.TableFile {
+	t.Helper()
+	table, err := androidbinary.NewTableFile(bytes.NewReader(encodeTestTable(t, entries)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestCheck(t *testing.T) {
+	table := newTestTable(t, []testEntry{
+		{typ: "string", name: "app_name", value: "Example"},
+		{typ: "string", name: "app_name", locale: "ja", value: "例"},
+		{typ: "string", name: "app_name", locale: "ru", value: "Пример"},
+
+		{typ: "string", name: "greeting", value: "Hello, %1$s! You have %2$d messages."},
+		{typ: "string", name: "greeting", locale: "ja", value: "%1$sさん、%2$d件のメッセージがあります。"},
+		{typ: "string", name: "greeting", locale: "ru", value: "Привет, %1$d!"},
+
+		{typ: "string", name: "untranslated", value: "100%% sure"},
+		{typ: "string", name: "untranslated", locale: "ja", value: "100%%確実"},
+
+		{typ: "plurals", name: "messages", plurals: map[androidbinary.ResID]string{
+			androidbinary.AttrPluralOne:   "%d message",
+			androidbinary.AttrPluralOther: "%d messages",
+		}},
+		{typ: "plurals", name: "messages", locale: "ja", plurals: map[androidbinary.ResID]string{
+			androidbinary.AttrPluralOther: "%d件のメッセージ",
+		}},
+		{typ: "plurals", name: "messages", locale: "ru", plurals: map[androidbinary.ResID]string{
+			androidbinary.AttrPluralOne:   "%d сообщение",
+			androidbinary.AttrPluralOther: "%d сообщений",
+		}},
+
+		{typ: "string", name: "ja_only", locale: "ja", value: "日本語のみ"},
+	})
+
+	type result struct {
+		Type   IssueType
+		Name   string
+		Locale string
+	}
+	var got []result
+	for _, issue := range Check(table, nil) {
+		got = append(got, result{issue.Type, issue.Name, issue.Locale})
+	}
+	want := []result{
+		{FormatMismatch, "string/greeting", "ru"},
+		{Untranslated, "string/untranslated", "ru"},
+		{MissingQuantity, "plurals/messages", "ru"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	t.Run("locales", func(t *testing.T) {
+		issues := Check(table, &Options{Locales: []string{"ja-JP", "fr"}})
+		var got []result
+		for _, issue := range issues {
+			got = append(got, result{issue.Type, issue.Name, issue.Locale})
+		}
+		want := []result{
+			{Untranslated, "string/app_name", "fr"},
+			{Untranslated, "string/greeting", "fr"},
+			{Untranslated, "string/untranslated", "fr"},
+			{Untranslated, "plurals/messages", "fr"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("message", func(t *testing.T) {
+		issues := Check(table, &Options{Locales: []string{"ru"}})
+		if len(issues) == 0 {
+			t.Fatal("want issues, got none")
+		}
+		want := "string/greeting [ru] FormatMismatch: format arguments [%1$d] don't match the default [%1$s %2$d]"
+		if got := issues[0].String(); got != want {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package androidbinary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unsafe"
+)
+
+// ParseOptions is options for parsing binary XML files and resource tables.
+// The zero value has no limits.
+type ParseOptions struct {
+	// Context is checked while parsing, and the parsing is cancelled with its error if it is done.
+	Context context.Context
+
+	// MaxAllocation is the maximum number of bytes that are allocated for the parsed data,
+	// such as the strings, the entries and the decoded XML. Zero means no limit.
+	MaxAllocation int64
+
+	// MaxChunks is the maximum number of chunks in the file. Zero means no limit.
+	MaxChunks int
+
+	// Lazy reads the strings and the entries of resource tables on demand.
+	// See NewTableFileLazy. It is ignored for XML files.
+	Lazy bool
+
+	// Lenient tolerates malformed files, which are often crafted by packers and obfuscators,
+	// as far as Android does. The chunks that can't be parsed are skipped,
+	// the counts and the offsets out of the chunks are clamped, and the broken strings are empty.
+	// The problems are reported as warnings instead of errors.
+	// The limits and the context are enforced even in the lenient mode.
+	Lenient bool
+}
+
+// LimitError is returned if parsing a file exceeds a limit of ParseOptions.
+type LimitError struct {
+	// Limit is the name of the exceeded limit, such as "MaxAllocation" and "MaxChunks".
+	Limit string
+
+	// Max is the value of the limit.
+	Max int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("androidbinary: %s exceeded: %d", e.Limit, e.Max)
+}
+
+// CountError is returned if the number of items in a chunk, such as StringCount and EntryCount,
+// is too large for the size of the chunk.
+type CountError struct {
+	// Type is the type of the chunk.
+	Type ChunkType
+
+	// Field is the name of the field in the chunk header.
+	Field string
+
+	// Count is the value of the field.
+	Count uint32
+}
+
+func (e *CountError) Error() string {
+	return fmt.Sprintf("androidbinary: %s %d is too large for the chunk of type 0x%04X", e.Field, e.Count, uint16(e.Type))
+}
+
+// parser counts the chunks and the allocations of parsing a file, and collects the warnings in the lenient mode.
+// The methods of nil parser do nothing, for parsing without limits.
+type parser struct {
+	opts      ParseOptions
+	kind      FileKind
+	allocated int64
+	chunks    int
+
+	// base is the offset of the current chunk from the beginning of the file, for the warnings.
+	base     int64
+	warnings []*ParseError
+}
+
+func newParser(kind FileKind, opts *ParseOptions) *parser {
+	p := &parser{kind: kind}
+	if opts != nil {
+		p.opts = *opts
+	}
+	return p
+}
+
+// lenient returns whether the parser is in the lenient mode.
+func (p *parser) lenient() bool {
+	return p != nil && p.opts.Lenient
+}
+
+// recover records err as a warning of the current chunk and returns nil in the lenient mode.
+// Otherwise, or if err is caused by the limits or the context, it returns err as is.
+func (p *parser) recover(chunk ChunkType, err error) error {
+	if err == nil || !p.lenient() {
+		return err
+	}
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	warning := &ParseError{
+		Kind:   p.kind,
+		Chunk:  chunk,
+		Offset: p.base,
+		Err:    err,
+	}
+	if e, ok := err.(*ParseError); ok {
+		// the offset of e may be relative to its parent chunk, so use the base instead.
+		warning.Chunk = e.Chunk
+		warning.Err = e.Err
+	}
+	if err := p.alloc(int64(unsafe.Sizeof(*warning))); err != nil {
+		return err
+	}
+	p.warnings = append(p.warnings, warning)
+	return nil
+}
+
+// err returns the error of the context.
+func (p *parser) err() error {
+	if p == nil || p.opts.Context == nil {
+		return nil
+	}
+	return p.opts.Context.Err()
+}
+
+// chunk is called for each chunk.
+func (p *parser) chunk() error {
+	if p == nil {
+		return nil
+	}
+	if err := p.err(); err != nil {
+		return err
+	}
+	p.chunks++
+	if p.opts.MaxChunks > 0 && p.chunks > p.opts.MaxChunks {
+		return &LimitError{Limit: "MaxChunks", Max: int64(p.opts.MaxChunks)}
+	}
+	return nil
+}
+
+// alloc is called before allocating n bytes.
+func (p *parser) alloc(n int64) error {
+	if p == nil {
+		return nil
+	}
+	p.allocated += n
+	if p.opts.MaxAllocation > 0 && p.allocated > p.opts.MaxAllocation {
+		return &LimitError{Limit: "MaxAllocation", Max: p.opts.MaxAllocation}
+	}
+	return nil
+}
+
+// readerSize returns the size of r if it is known, such as *bytes.Reader, *io.SectionReader and *os.File.
+func readerSize(r io.ReaderAt) int64 {
+	switch r := r.(type) {
+	case interface{ Size() int64 }:
+		return r.Size()
+	case interface{ Stat() (os.FileInfo, error) }:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return 1<<63 - 1
+}
+
+// newSectionReader returns the section of n bytes from off in r.
+// The section is clamped to the end of r, because the sizes and the offsets in the chunks may be broken.
+func newSectionReader(r *io.SectionReader, off, n int64) *io.SectionReader {
+	size := r.Size()
+	if off > size {
+		off = size
+	}
+	if n > size-off {
+		n = size - off
+	}
+	if n < 0 {
+		n = 0
+	}
+	return io.NewSectionReader(r, off, n)
+}
+
+// checkCount returns an error if count items of size bytes don't fit in the chunk after its header.
+// available is the number of bytes that can be read from the beginning of the chunk.
+func checkCount(header *ResChunkHeader, available int64, field string, count uint32, size int64) error {
+	if int64(count)*size > chunkBodySize(header, available) {
+		return &CountError{Type: header.Type, Field: field, Count: count}
+	}
+	return nil
+}
+
+// chunkBodySize returns the size of the chunk after its header.
+// available is the number of bytes that can be read from the beginning of the chunk.
+func chunkBodySize(header *ResChunkHeader, available int64) int64 {
+	end := int64(header.Size)
+	if available < end {
+		end = available
+	}
+	return end - int64(header.HeaderSize)
+}
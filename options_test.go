@@ -0,0 +1,334 @@
+package androidbinary
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	xmlData, err := ioutil.ReadFile("testdata/AndroidManifest.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableData, err := ioutil.ReadFile("testdata/resources.arsc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parse := func(opts *ParseOptions) []error {
+		_, xmlErr := NewXMLFileWithOptions(bytes.NewReader(xmlData), opts)
+		_, tableErr := NewTableFileWithOptions(bytes.NewReader(tableData), opts)
+		lazyOpts := &ParseOptions{Lazy: true}
+		if opts != nil {
+			*lazyOpts = *opts
+			lazyOpts.Lazy = true
+		}
+		_, lazyErr := NewTableFileWithOptions(bytes.NewReader(tableData), lazyOpts)
+		return []error{xmlErr, tableErr, lazyErr}
+	}
+
+	t.Run("no limits", func(t *testing.T) {
+		for _, err := range parse(&ParseOptions{
+			Context:       context.Background(),
+			MaxAllocation: 1 << 20,
+			MaxChunks:     1000,
+		}) {
+			if err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, err := range parse(&ParseOptions{Context: ctx}) {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("want context.Canceled, got %v", err)
+			}
+		}
+	})
+
+	t.Run("max chunks", func(t *testing.T) {
+		for _, err := range parse(&ParseOptions{MaxChunks: 2}) {
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) || limitErr.Limit != "MaxChunks" || limitErr.Max != 2 {
+				t.Errorf("want MaxChunks error, got %v", err)
+			}
+		}
+	})
+
+	t.Run("max allocation", func(t *testing.T) {
+		errs := parse(&ParseOptions{MaxAllocation: 256})
+		for _, err := range errs[:2] {
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) || limitErr.Limit != "MaxAllocation" {
+				t.Errorf("want MaxAllocation error, got %v", err)
+			}
+		}
+
+		// the lazy table doesn't decode the strings and the entries.
+		if err := errs[2]; err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestParseHugeCounts(t *testing.T) {
+	t.Run("StringCount", func(t *testing.T) {
+		// a XML file which has a string pool of 0x10000000 strings without the string data.
+		data := []byte{
+			0x03, 0x00, // Type = RES_XML_TYPE
+			0x08, 0x00, // HeaderSize = 8
+			0x24, 0x00, 0x00, 0x00, // Size = 36
+
+			0x01, 0x00, // Type = RES_STRING_POOL_TYPE
+			0x1C, 0x00, // HeaderSize = 28
+			0x1C, 0x00, 0x00, 0x00, // Size = 28
+			0x00, 0x00, 0x00, 0x10, // StringCount = 0x10000000
+			0x00, 0x00, 0x00, 0x00, // StyleCount = 0
+			0x00, 0x00, 0x00, 0x00, // Flags = 0x00
+			0x1C, 0x00, 0x00, 0x00, // StringStart = 28
+			0x00, 0x00, 0x00, 0x00, // StylesStart = 0
+		}
+		_, err := NewXMLFile(bytes.NewReader(data))
+		var countErr *CountError
+		if !errors.As(err, &countErr) || countErr.Field != "StringCount" || countErr.Count != 0x10000000 {
+			t.Errorf("want StringCount error, got %v", err)
+		}
+	})
+
+	t.Run("EntryCount", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/resources.arsc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		offset := findTestTableType(data)
+		binary.LittleEndian.PutUint32(data[offset+12:], 0x7FFFFFFF)
+
+		for _, lazy := range []bool{false, true} {
+			_, err := NewTableFileWithOptions(bytes.NewReader(data), &ParseOptions{Lazy: lazy})
+			var countErr *CountError
+			if !errors.As(err, &countErr) || countErr.Type != ResTableTypeType || countErr.Field != "EntryCount" {
+				t.Errorf("lazy %v: want EntryCount error, got %v", lazy, err)
+			}
+		}
+	})
+}
+
+// findTestXMLChunks returns the offsets of the chunks of typ in the binary XML file.
+func findTestXMLChunks(data []byte, typ ChunkType) []int {
+	var offsets []int
+	for offset := 8; offset+8 <= len(data); offset += int(binary.LittleEndian.Uint32(data[offset+4:])) {
+		if ChunkType(binary.LittleEndian.Uint16(data[offset:])) == typ {
+			offsets = append(offsets, offset)
+		}
+	}
+	return offsets
+}
+
+func TestParseLenient(t *testing.T) {
+	xmlData, err := ioutil.ReadFile("testdata/AndroidManifest.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := NewXMLFile(bytes.NewReader(xmlData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lenient := &ParseOptions{Lenient: true}
+
+	// parseXML parses data in both the strict mode and the lenient mode.
+	parseXML := func(t *testing.T, data []byte) (*XMLFile, []*ParseError) {
+		t.Helper()
+		if _, err := NewXMLFile(bytes.NewReader(data)); err == nil {
+			t.Error("want error in the strict mode, got nil")
+		}
+		f, err := NewXMLFileWithOptions(bytes.NewReader(data), lenient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f, f.Warnings()
+	}
+
+	t.Run("no warnings", func(t *testing.T) {
+		f, err := NewXMLFileWithOptions(bytes.NewReader(xmlData), lenient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(f.Warnings()) != 0 {
+			t.Errorf("unexpected warnings: %v", f.Warnings())
+		}
+		if !bytes.Equal(f.xmlBuffer.Bytes(), want.xmlBuffer.Bytes()) {
+			t.Errorf("unexpected XML: %s", f.xmlBuffer.String())
+		}
+	})
+
+	t.Run("broken end element", func(t *testing.T) {
+		data := append([]byte(nil), xmlData...)
+		for _, offset := range findTestXMLChunks(data, ResXMLEndElementType) {
+			binary.LittleEndian.PutUint32(data[offset+20:], 0x7FFFFFFF) // Name
+		}
+		f, warnings := parseXML(t, data)
+		if len(warnings) != 0 {
+			t.Errorf("unexpected warnings: %v", warnings)
+		}
+		if !bytes.Equal(f.xmlBuffer.Bytes(), want.xmlBuffer.Bytes()) {
+			t.Errorf("unexpected XML: %s", f.xmlBuffer.String())
+		}
+	})
+
+	t.Run("broken start element", func(t *testing.T) {
+		data := append([]byte(nil), xmlData...)
+		offset := findTestXMLChunks(data, ResXMLStartElementType)[1]
+		binary.LittleEndian.PutUint32(data[offset+20:], 0x7FFFFFFF) // Name
+		f, warnings := parseXML(t, data)
+		if len(warnings) != 1 || warnings[0].Chunk != ResXMLStartElementType || warnings[0].Offset != int64(offset) {
+			t.Fatalf("unexpected warnings: %v", warnings)
+		}
+		var refErr *InvalidReferenceError
+		if !errors.As(warnings[0], &refErr) || refErr.Ref != 0x7FFFFFFF {
+			t.Errorf("want InvalidReferenceError, got %v", warnings[0])
+		}
+		var v struct {
+			Package string `xml:"package,attr"`
+		}
+		if err := f.Decode(&v, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if v.Package != "net.sorablue.shogo.FWMeasure" {
+			t.Errorf("unexpected package: %q", v.Package)
+		}
+	})
+
+	t.Run("broken attribute", func(t *testing.T) {
+		data := append([]byte(nil), xmlData...)
+		offset := findTestXMLChunks(data, ResXMLStartElementType)[0]
+		attrStart := int(binary.LittleEndian.Uint16(data[offset+24:])) + int(binary.LittleEndian.Uint16(data[offset+2:]))
+		binary.LittleEndian.PutUint32(data[offset+attrStart+4:], 0x7FFFFFFF) // Name of the first attribute
+		f, warnings := parseXML(t, data)
+		if len(warnings) != 1 || warnings[0].Chunk != ResXMLStartElementType {
+			t.Fatalf("unexpected warnings: %v", warnings)
+		}
+		if err := f.Decode(new(struct{}), nil, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("obfuscated attribute names", func(t *testing.T) {
+		// overwrite the names of the framework attributes in the string pool with garbage.
+		data := append([]byte(nil), xmlData...)
+		pool := findTestXMLChunks(data, ResStringPoolChunkType)[0]
+		stringStart := pool + int(binary.LittleEndian.Uint32(data[pool+20:]))
+		resourceMap := findTestXMLChunks(data, ResXMLResourceMapType)[0]
+		count := (int(binary.LittleEndian.Uint32(data[resourceMap+4:])) - 8) / 4
+		for i := 0; i < count; i++ {
+			start := stringStart + int(binary.LittleEndian.Uint32(data[pool+28+4*i:]))
+			n := int(binary.LittleEndian.Uint16(data[start:]))
+			for j := 0; j < n; j++ {
+				binary.LittleEndian.PutUint16(data[start+2+2*j:], '%')
+			}
+		}
+
+		f, err := NewXMLFileWithOptions(bytes.NewReader(data), lenient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(f.Warnings()) == 0 {
+			t.Error("want warnings, got none")
+		}
+		if !bytes.Equal(f.xmlBuffer.Bytes(), want.xmlBuffer.Bytes()) {
+			t.Errorf("unexpected XML: %s", f.xmlBuffer.String())
+		}
+	})
+
+	t.Run("broken text", func(t *testing.T) {
+		// insert the text of an invalid reference into the root element.
+		offset := findTestXMLChunks(xmlData, ResXMLStartElementType)[0]
+		offset += int(binary.LittleEndian.Uint32(xmlData[offset+4:]))
+		cdata := make([]byte, 28)
+		binary.LittleEndian.PutUint16(cdata[0:], uint16(ResXMLCDataType))
+		binary.LittleEndian.PutUint16(cdata[2:], 16)          // HeaderSize
+		binary.LittleEndian.PutUint32(cdata[4:], 28)          // Size
+		binary.LittleEndian.PutUint32(cdata[12:], 0xFFFFFFFF) // Comment
+		binary.LittleEndian.PutUint32(cdata[16:], 0x7FFFFFFF) // Data
+		binary.LittleEndian.PutUint16(cdata[20:], 8)          // Size of TypedData
+		data := append(append(append([]byte(nil), xmlData[:offset]...), cdata...), xmlData[offset:]...)
+		binary.LittleEndian.PutUint32(data[4:], uint32(len(data)))
+
+		f, warnings := parseXML(t, data)
+		if len(warnings) != 1 || warnings[0].Chunk != ResXMLCDataType || warnings[0].Offset != int64(offset) {
+			t.Fatalf("unexpected warnings: %v", warnings)
+		}
+		var refErr *InvalidReferenceError
+		if !errors.As(warnings[0], &refErr) || refErr.Ref != 0x7FFFFFFF {
+			t.Errorf("want InvalidReferenceError, got %v", warnings[0])
+		}
+		if !bytes.Equal(f.xmlBuffer.Bytes(), want.xmlBuffer.Bytes()) {
+			t.Errorf("unexpected XML: %s", f.xmlBuffer.String())
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		f, warnings := parseXML(t, xmlData[:len(xmlData)-50])
+		var eof bool
+		for _, w := range warnings {
+			eof = eof || errors.Is(w, io.ErrUnexpectedEOF)
+		}
+		if !eof {
+			t.Errorf("want io.ErrUnexpectedEOF, got %v", warnings)
+		}
+		if err := f.Decode(new(struct{}), nil, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("huge StringCount", func(t *testing.T) {
+		data := append([]byte(nil), xmlData...)
+		offset := findTestXMLChunks(data, ResStringPoolChunkType)[0]
+		binary.LittleEndian.PutUint32(data[offset+8:], 0x10000000) // StringCount
+		_, warnings := parseXML(t, data)
+		var countErr *CountError
+		if len(warnings) == 0 || !errors.As(warnings[0], &countErr) || countErr.Field != "StringCount" {
+			t.Errorf("want StringCount warning, got %v", warnings)
+		}
+	})
+
+	t.Run("huge EntryCount", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/resources.arsc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset := findTestTableType(data)
+		binary.LittleEndian.PutUint32(data[offset+12:], 0x7FFFFFFF)
+
+		for _, lazy := range []bool{false, true} {
+			f, err := NewTableFileWithOptions(bytes.NewReader(data), &ParseOptions{Lenient: true, Lazy: lazy})
+			if err != nil {
+				t.Errorf("lazy %v: %v", lazy, err)
+				continue
+			}
+			warnings := f.Warnings()
+			var countErr *CountError
+			if len(warnings) == 0 || !errors.As(warnings[0], &countErr) || countErr.Field != "EntryCount" {
+				t.Errorf("lazy %v: want EntryCount warning, got %v", lazy, warnings)
+			}
+			if warnings[0].Kind != FileKindTable || warnings[0].Offset != int64(offset) {
+				t.Errorf("lazy %v: unexpected warning: %+v", lazy, warnings[0])
+			}
+		}
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		_, err := NewXMLFileWithOptions(bytes.NewReader(xmlData), &ParseOptions{Lenient: true, MaxChunks: 2})
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) {
+			t.Errorf("want LimitError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package androidbinary
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PluralQuantity is a quantity of plurals resources, such as "one" and "other".
+type PluralQuantity string
+
+// The quantities of plurals resources.
+const (
+	PluralZero  PluralQuantity = "zero"
+	PluralOne   PluralQuantity = "one"
+	PluralTwo   PluralQuantity = "two"
+	PluralFew   PluralQuantity = "few"
+	PluralMany  PluralQuantity = "many"
+	PluralOther PluralQuantity = "other"
+)
+
+// The attributes of the items of plurals resources, which are ^attr-private/quantity=... in aapt.
+const (
+	AttrPluralOther ResID = 0x01000004
+	AttrPluralZero  ResID = 0x01000005
+	AttrPluralOne   ResID = 0x01000006
+	AttrPluralTwo   ResID = 0x01000007
+	AttrPluralFew   ResID = 0x01000008
+	AttrPluralMany  ResID = 0x01000009
+)
+
+var pluralQuantities = map[ResID]PluralQuantity{
+	AttrPluralOther: PluralOther,
+	AttrPluralZero:  PluralZero,
+	AttrPluralOne:   PluralOne,
+	AttrPluralTwo:   PluralTwo,
+	AttrPluralFew:   PluralFew,
+	AttrPluralMany:  PluralMany,
+}
+
+// PluralQuantityOf returns the quantity of the attribute of an item of plurals resources.
+// It returns false if attr is not a quantity.
+func PluralQuantityOf(attr ResID) (PluralQuantity, bool) {
+	q, ok := pluralQuantities[attr]
+	return q, ok
+}
+
+// pluralRule returns the quantity of the non-negative integer n.
+type pluralRule func(n int) PluralQuantity
+
+//go:generate go run ./internal/gen/plurals -i internal/gen/plurals/plurals.xml -o plurals_table.go
+
+// inRange returns whether n is in the range from..to of the plural rules.
+func inRange(n, from, to int) bool {
+	return from <= n && n <= to
+}
+
+func defaultPluralRule(n int) PluralQuantity {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleOf returns the plural rule of the locale, such as "ru", "en-US" and "pt_BR".
+// The rule of the region, such as pt_PT, is preferred to the rule of the language.
+// The languages that CLDR doesn't have use "one" for 1, and "other" for the others, as English does.
+func pluralRuleOf(locale string) pluralRule {
+	tags := strings.FieldsFunc(strings.ToLower(locale), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(tags) == 0 {
+		return defaultPluralRule
+	}
+	if len(tags) > 1 {
+		if rule, ok := pluralRules[tags[0]+"_"+tags[1]]; ok {
+			return rule
+		}
+	}
+	if rule, ok := pluralRules[tags[0]]; ok {
+		return rule
+	}
+	return defaultPluralRule
+}
+
+// PluralQuantityFor returns the quantity of the plurals resources used for n in the locale,
+// such as "ru", "en-US" and "pt_BR", in the same way as android.icu.text.PluralRules.select.
+func PluralQuantityFor(locale string, n int) PluralQuantity {
+	if n < 0 {
+		n = -n
+	}
+	return pluralRuleOf(locale)(n)
+}
+
+// PluralQuantities returns the quantities that the locale uses for the integers,
+// in the order of zero, one, two, few, many and other.
+// PluralOther is always included, because Resources.getQuantityString falls back to it.
+func PluralQuantities(locale string) []PluralQuantity {
+	rule := pluralRuleOf(locale)
+	used := map[PluralQuantity]bool{PluralOther: true}
+	for n := 0; n <= 200; n++ {
+		used[rule(n)] = true
+	}
+	used[rule(1000000)] = true
+
+	var quantities []PluralQuantity
+	for _, q := range []PluralQuantity{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther} {
+		if used[q] {
+			quantities = append(quantities, q)
+		}
+	}
+	return quantities
+}
+
+// GetQuantityString returns the string of the plurals resource referenced by id for the quantity n,
+// in the same way as Resources.getQuantityString.
+// The plural rule is chosen by the locale of config, or by the locale of the chosen entry if config is nil.
+// If the resource doesn't have the item of the quantity, the item of "other" is used.
+func (f *TableFile) GetQuantityString(id ResID, config *ResTableConfig, n int) (string, error) {
+	p := f.findPackage(id.Package())
+	if p == nil {
+		return "", fmt.Errorf("androidbinary: package 0x%02X not found", id.Package())
+	}
+	t, e := p.findBestEntry(id.Type(), id.Entry(), config)
+	if e.Key == nil {
+		return "", fmt.Errorf("androidbinary: entry 0x%04X not found", id.Entry())
+	}
+	if e.Value != nil {
+		return "", fmt.Errorf("androidbinary: resource 0x%08X is not a plurals resource", uint32(id))
+	}
+
+	locale := t.Header.Config.Locale()
+	if config != nil {
+		locale = config.Locale()
+	}
+	q := PluralQuantityFor(locale, n)
+	v := findPluralItem(e.Map, q)
+	if v == nil {
+		v = findPluralItem(e.Map, PluralOther)
+	}
+	if v == nil {
+		return "", fmt.Errorf("androidbinary: resource 0x%08X doesn't have the quantity %s", uint32(id), q)
+	}
+
+	switch v.DataType {
+	case TypeString:
+		ref := ResStringPoolRef(v.Data)
+		if !f.stringPool.HasString(ref) {
+			return "", &InvalidReferenceError{Ref: ref}
+		}
+		return f.GetString(ref), nil
+	case TypeReference:
+		// the item is a reference to a string resource, such as @string/messages_one.
+		res, err := f.GetResource(ResID(v.Data), config)
+		if err != nil {
+			return "", err
+		}
+		if s, ok := res.(string); ok {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("androidbinary: the quantity %s of resource 0x%08X is not a string", q, uint32(id))
+}
+
+// findPluralItem returns the value of the item of the quantity q, or nil if it is not found.
+func findPluralItem(items []ResTableMap, q PluralQuantity) *ResValue {
+	for i := range items {
+		if quantity, ok := PluralQuantityOf(items[i].Name); ok && quantity == q {
+			return &items[i].Value
+		}
+	}
+	return nil
+}
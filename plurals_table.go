@@ -0,0 +1,692 @@
+// Code generated by internal/gen/plurals from plurals.xml of CLDR. DO NOT EDIT.
+
+package androidbinary
+
+// pluralRuleBm is the plural rule of bm, bo, dz, hnj, id, ig, ii, in, ja, jbo, jv, jw, kde, kea, km, ko, lkt, lo, ms, my, nqo, osa, root, sah, ses, sg, su, th, to, tpi, vi, wo, yo, yue, zh.
+func pluralRuleBm(n int) PluralQuantity {
+	return PluralOther
+}
+
+// pluralRuleAm is the plural rule of am, as, bn, doi, fa, gu, hi, kn, kok, kok_Latn, pcm, zu.
+func pluralRuleAm(n int) PluralQuantity {
+	switch {
+	case n == 0 || n == 1:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleFf is the plural rule of ff, hy, kab.
+func pluralRuleFf(n int) PluralQuantity {
+	switch {
+	case n == 0 || n == 1:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleAst is the plural rule of ast, de, en, et, fi, fy, gl, ia, ie, io, ji, lij, nl, sc, sv, sw, ur, yi.
+func pluralRuleAst(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleSi is the plural rule of si.
+func pluralRuleSi(n int) PluralQuantity {
+	switch {
+	case n == 0 || n == 1:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleAk is the plural rule of ak, bho, csw, guw, ln, mg, nso, pa, ti, wa.
+func pluralRuleAk(n int) PluralQuantity {
+	switch {
+	case inRange(n, 0, 1):
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleTzm is the plural rule of tzm.
+func pluralRuleTzm(n int) PluralQuantity {
+	switch {
+	case inRange(n, 0, 1) || inRange(n, 11, 99):
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleAf is the plural rule of af, an, asa, az, bal, bem, bez, bg, brx, ce, cgg, chr, ckb, dv, ee, el, eo, eu, fo, fur, gsw, ha, haw, hu, jgo, jmc, ka, kaj, kcg, kk, kkj, kl, ks, ksb, ku, ky, lb, lg, mas, mgo, ml, mn, mr, nah, nb, nd, ne, nn, nnh, no, nr, ny, nyn, om, or, os, pap, ps, rm, rof, rwk, saq, sd, sdh, seh, sn, so, sq, ss, ssy, st, syr, ta, te, teo, tig, tk, tn, tr, ts, ug, uz, ve, vo, vun, wae, xh, xog.
+func pluralRuleAf(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleDa is the plural rule of da.
+func pluralRuleDa(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleIs is the plural rule of is.
+func pluralRuleIs(n int) PluralQuantity {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleMk is the plural rule of mk.
+func pluralRuleMk(n int) PluralQuantity {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleCeb is the plural rule of ceb, fil, tl.
+func pluralRuleCeb(n int) PluralQuantity {
+	switch {
+	case n == 1 || n == 2 || n == 3 || n%10 != 4 && n%10 != 6 && n%10 != 9:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleLv is the plural rule of lv, prg.
+func pluralRuleLv(n int) PluralQuantity {
+	switch {
+	case n%10 == 0 || inRange(n%100, 11, 19):
+		return PluralZero
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleLag is the plural rule of lag.
+func pluralRuleLag(n int) PluralQuantity {
+	switch {
+	case n == 0:
+		return PluralZero
+	case (n == 0 || n == 1) && n != 0:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleBlo is the plural rule of blo, cv, ksh.
+func pluralRuleBlo(n int) PluralQuantity {
+	switch {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralRuleHe is the plural rule of he, iw.
+func pluralRuleHe(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	}
+	return PluralOther
+}
+
+// pluralRuleIu is the plural rule of iu, naq, sat, se, sma, smi, smj, smn, sms.
+func pluralRuleIu(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	}
+	return PluralOther
+}
+
+// pluralRuleShi is the plural rule of shi.
+func pluralRuleShi(n int) PluralQuantity {
+	switch {
+	case n == 0 || n == 1:
+		return PluralOne
+	case inRange(n, 2, 10):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleMo is the plural rule of mo, ro.
+func pluralRuleMo(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n == 0 || n != 1 && inRange(n%100, 1, 19):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleBs is the plural rule of bs, hr, sh, sr.
+func pluralRuleBs(n int) PluralQuantity {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	case inRange(n%10, 2, 4) && !inRange(n%100, 12, 14):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleFr is the plural rule of fr.
+func pluralRuleFr(n int) PluralQuantity {
+	switch {
+	case n == 0 || n == 1:
+		return PluralOne
+	case n != 0 && n%1000000 == 0:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRulePt is the plural rule of pt.
+func pluralRulePt(n int) PluralQuantity {
+	switch {
+	case inRange(n, 0, 1):
+		return PluralOne
+	case n != 0 && n%1000000 == 0:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleCa is the plural rule of ca, it, lld, pt_PT, scn, vec.
+func pluralRuleCa(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n != 0 && n%1000000 == 0:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleEs is the plural rule of es.
+func pluralRuleEs(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n != 0 && n%1000000 == 0:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleGd is the plural rule of gd.
+func pluralRuleGd(n int) PluralQuantity {
+	switch {
+	case n == 1 || n == 11:
+		return PluralOne
+	case n == 2 || n == 12:
+		return PluralTwo
+	case inRange(n, 3, 10) || inRange(n, 13, 19):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleSl is the plural rule of sl.
+func pluralRuleSl(n int) PluralQuantity {
+	switch {
+	case n%100 == 1:
+		return PluralOne
+	case n%100 == 2:
+		return PluralTwo
+	case inRange(n%100, 3, 4):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleDsb is the plural rule of dsb, hsb.
+func pluralRuleDsb(n int) PluralQuantity {
+	switch {
+	case n%100 == 1:
+		return PluralOne
+	case n%100 == 2:
+		return PluralTwo
+	case inRange(n%100, 3, 4):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleCs is the plural rule of cs, sk.
+func pluralRuleCs(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case inRange(n, 2, 4):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRulePl is the plural rule of pl.
+func pluralRulePl(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case inRange(n%10, 2, 4) && !inRange(n%100, 12, 14):
+		return PluralFew
+	case n != 1 && inRange(n%10, 0, 1) || inRange(n%10, 5, 9) || inRange(n%100, 12, 14):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleBe is the plural rule of be.
+func pluralRuleBe(n int) PluralQuantity {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	case inRange(n%10, 2, 4) && !inRange(n%100, 12, 14):
+		return PluralFew
+	case n%10 == 0 || inRange(n%10, 5, 9) || inRange(n%100, 11, 14):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleLt is the plural rule of lt.
+func pluralRuleLt(n int) PluralQuantity {
+	switch {
+	case n%10 == 1 && !inRange(n%100, 11, 19):
+		return PluralOne
+	case inRange(n%10, 2, 9) && !inRange(n%100, 11, 19):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleRu is the plural rule of ru, uk.
+func pluralRuleRu(n int) PluralQuantity {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	case inRange(n%10, 2, 4) && !inRange(n%100, 12, 14):
+		return PluralFew
+	case n%10 == 0 || inRange(n%10, 5, 9) || inRange(n%100, 11, 14):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleSgs is the plural rule of sgs.
+func pluralRuleSgs(n int) PluralQuantity {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case n != 2 && inRange(n%10, 2, 9) && !inRange(n%100, 11, 19):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleBr is the plural rule of br.
+func pluralRuleBr(n int) PluralQuantity {
+	switch {
+	case n%10 == 1 && n%100 != 11 && n%100 != 71 && n%100 != 91:
+		return PluralOne
+	case n%10 == 2 && n%100 != 12 && n%100 != 72 && n%100 != 92:
+		return PluralTwo
+	case (inRange(n%10, 3, 4) || n%10 == 9) && !inRange(n%100, 10, 19) && !inRange(n%100, 70, 79) && !inRange(n%100, 90, 99):
+		return PluralFew
+	case n != 0 && n%1000000 == 0:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleMt is the plural rule of mt.
+func pluralRuleMt(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case n == 0 || inRange(n%100, 3, 10):
+		return PluralFew
+	case inRange(n%100, 11, 19):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleGa is the plural rule of ga.
+func pluralRuleGa(n int) PluralQuantity {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case inRange(n, 3, 6):
+		return PluralFew
+	case inRange(n, 7, 10):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleGv is the plural rule of gv.
+func pluralRuleGv(n int) PluralQuantity {
+	switch {
+	case n%10 == 1:
+		return PluralOne
+	case n%10 == 2:
+		return PluralTwo
+	case n%100 == 0 || n%100 == 20 || n%100 == 40 || n%100 == 60 || n%100 == 80:
+		return PluralFew
+	}
+	return PluralOther
+}
+
+// pluralRuleKw is the plural rule of kw.
+func pluralRuleKw(n int) PluralQuantity {
+	switch {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	case n%100 == 2 || n%100 == 22 || n%100 == 42 || n%100 == 62 || n%100 == 82 || n%1000 == 0 && (inRange(n%100000, 1000, 20000) || n%100000 == 40000 || n%100000 == 60000 || n%100000 == 80000) || n != 0 && n%1000000 == 100000:
+		return PluralTwo
+	case n%100 == 3 || n%100 == 23 || n%100 == 43 || n%100 == 63 || n%100 == 83:
+		return PluralFew
+	case n != 1 && (n%100 == 1 || n%100 == 21 || n%100 == 41 || n%100 == 61 || n%100 == 81):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleAr is the plural rule of ar, ars.
+func pluralRuleAr(n int) PluralQuantity {
+	switch {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case inRange(n%100, 3, 10):
+		return PluralFew
+	case inRange(n%100, 11, 99):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRuleCy is the plural rule of cy.
+func pluralRuleCy(n int) PluralQuantity {
+	switch {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case n == 3:
+		return PluralFew
+	case n == 6:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralRules is the cardinal plural rules of CLDR for the integers, keyed by the lower-cased locales.
+var pluralRules = map[string]pluralRule{
+	"af":       pluralRuleAf,
+	"ak":       pluralRuleAk,
+	"am":       pluralRuleAm,
+	"an":       pluralRuleAf,
+	"ar":       pluralRuleAr,
+	"ars":      pluralRuleAr,
+	"as":       pluralRuleAm,
+	"asa":      pluralRuleAf,
+	"ast":      pluralRuleAst,
+	"az":       pluralRuleAf,
+	"bal":      pluralRuleAf,
+	"be":       pluralRuleBe,
+	"bem":      pluralRuleAf,
+	"bez":      pluralRuleAf,
+	"bg":       pluralRuleAf,
+	"bho":      pluralRuleAk,
+	"blo":      pluralRuleBlo,
+	"bm":       pluralRuleBm,
+	"bn":       pluralRuleAm,
+	"bo":       pluralRuleBm,
+	"br":       pluralRuleBr,
+	"brx":      pluralRuleAf,
+	"bs":       pluralRuleBs,
+	"ca":       pluralRuleCa,
+	"ce":       pluralRuleAf,
+	"ceb":      pluralRuleCeb,
+	"cgg":      pluralRuleAf,
+	"chr":      pluralRuleAf,
+	"ckb":      pluralRuleAf,
+	"cs":       pluralRuleCs,
+	"csw":      pluralRuleAk,
+	"cv":       pluralRuleBlo,
+	"cy":       pluralRuleCy,
+	"da":       pluralRuleDa,
+	"de":       pluralRuleAst,
+	"doi":      pluralRuleAm,
+	"dsb":      pluralRuleDsb,
+	"dv":       pluralRuleAf,
+	"dz":       pluralRuleBm,
+	"ee":       pluralRuleAf,
+	"el":       pluralRuleAf,
+	"en":       pluralRuleAst,
+	"eo":       pluralRuleAf,
+	"es":       pluralRuleEs,
+	"et":       pluralRuleAst,
+	"eu":       pluralRuleAf,
+	"fa":       pluralRuleAm,
+	"ff":       pluralRuleFf,
+	"fi":       pluralRuleAst,
+	"fil":      pluralRuleCeb,
+	"fo":       pluralRuleAf,
+	"fr":       pluralRuleFr,
+	"fur":      pluralRuleAf,
+	"fy":       pluralRuleAst,
+	"ga":       pluralRuleGa,
+	"gd":       pluralRuleGd,
+	"gl":       pluralRuleAst,
+	"gsw":      pluralRuleAf,
+	"gu":       pluralRuleAm,
+	"guw":      pluralRuleAk,
+	"gv":       pluralRuleGv,
+	"ha":       pluralRuleAf,
+	"haw":      pluralRuleAf,
+	"he":       pluralRuleHe,
+	"hi":       pluralRuleAm,
+	"hnj":      pluralRuleBm,
+	"hr":       pluralRuleBs,
+	"hsb":      pluralRuleDsb,
+	"hu":       pluralRuleAf,
+	"hy":       pluralRuleFf,
+	"ia":       pluralRuleAst,
+	"id":       pluralRuleBm,
+	"ie":       pluralRuleAst,
+	"ig":       pluralRuleBm,
+	"ii":       pluralRuleBm,
+	"in":       pluralRuleBm,
+	"io":       pluralRuleAst,
+	"is":       pluralRuleIs,
+	"it":       pluralRuleCa,
+	"iu":       pluralRuleIu,
+	"iw":       pluralRuleHe,
+	"ja":       pluralRuleBm,
+	"jbo":      pluralRuleBm,
+	"jgo":      pluralRuleAf,
+	"ji":       pluralRuleAst,
+	"jmc":      pluralRuleAf,
+	"jv":       pluralRuleBm,
+	"jw":       pluralRuleBm,
+	"ka":       pluralRuleAf,
+	"kab":      pluralRuleFf,
+	"kaj":      pluralRuleAf,
+	"kcg":      pluralRuleAf,
+	"kde":      pluralRuleBm,
+	"kea":      pluralRuleBm,
+	"kk":       pluralRuleAf,
+	"kkj":      pluralRuleAf,
+	"kl":       pluralRuleAf,
+	"km":       pluralRuleBm,
+	"kn":       pluralRuleAm,
+	"ko":       pluralRuleBm,
+	"kok":      pluralRuleAm,
+	"kok_latn": pluralRuleAm,
+	"ks":       pluralRuleAf,
+	"ksb":      pluralRuleAf,
+	"ksh":      pluralRuleBlo,
+	"ku":       pluralRuleAf,
+	"kw":       pluralRuleKw,
+	"ky":       pluralRuleAf,
+	"lag":      pluralRuleLag,
+	"lb":       pluralRuleAf,
+	"lg":       pluralRuleAf,
+	"lij":      pluralRuleAst,
+	"lkt":      pluralRuleBm,
+	"lld":      pluralRuleCa,
+	"ln":       pluralRuleAk,
+	"lo":       pluralRuleBm,
+	"lt":       pluralRuleLt,
+	"lv":       pluralRuleLv,
+	"mas":      pluralRuleAf,
+	"mg":       pluralRuleAk,
+	"mgo":      pluralRuleAf,
+	"mk":       pluralRuleMk,
+	"ml":       pluralRuleAf,
+	"mn":       pluralRuleAf,
+	"mo":       pluralRuleMo,
+	"mr":       pluralRuleAf,
+	"ms":       pluralRuleBm,
+	"mt":       pluralRuleMt,
+	"my":       pluralRuleBm,
+	"nah":      pluralRuleAf,
+	"naq":      pluralRuleIu,
+	"nb":       pluralRuleAf,
+	"nd":       pluralRuleAf,
+	"ne":       pluralRuleAf,
+	"nl":       pluralRuleAst,
+	"nn":       pluralRuleAf,
+	"nnh":      pluralRuleAf,
+	"no":       pluralRuleAf,
+	"nqo":      pluralRuleBm,
+	"nr":       pluralRuleAf,
+	"nso":      pluralRuleAk,
+	"ny":       pluralRuleAf,
+	"nyn":      pluralRuleAf,
+	"om":       pluralRuleAf,
+	"or":       pluralRuleAf,
+	"os":       pluralRuleAf,
+	"osa":      pluralRuleBm,
+	"pa":       pluralRuleAk,
+	"pap":      pluralRuleAf,
+	"pcm":      pluralRuleAm,
+	"pl":       pluralRulePl,
+	"prg":      pluralRuleLv,
+	"ps":       pluralRuleAf,
+	"pt":       pluralRulePt,
+	"pt_pt":    pluralRuleCa,
+	"rm":       pluralRuleAf,
+	"ro":       pluralRuleMo,
+	"rof":      pluralRuleAf,
+	"root":     pluralRuleBm,
+	"ru":       pluralRuleRu,
+	"rwk":      pluralRuleAf,
+	"sah":      pluralRuleBm,
+	"saq":      pluralRuleAf,
+	"sat":      pluralRuleIu,
+	"sc":       pluralRuleAst,
+	"scn":      pluralRuleCa,
+	"sd":       pluralRuleAf,
+	"sdh":      pluralRuleAf,
+	"se":       pluralRuleIu,
+	"seh":      pluralRuleAf,
+	"ses":      pluralRuleBm,
+	"sg":       pluralRuleBm,
+	"sgs":      pluralRuleSgs,
+	"sh":       pluralRuleBs,
+	"shi":      pluralRuleShi,
+	"si":       pluralRuleSi,
+	"sk":       pluralRuleCs,
+	"sl":       pluralRuleSl,
+	"sma":      pluralRuleIu,
+	"smi":      pluralRuleIu,
+	"smj":      pluralRuleIu,
+	"smn":      pluralRuleIu,
+	"sms":      pluralRuleIu,
+	"sn":       pluralRuleAf,
+	"so":       pluralRuleAf,
+	"sq":       pluralRuleAf,
+	"sr":       pluralRuleBs,
+	"ss":       pluralRuleAf,
+	"ssy":      pluralRuleAf,
+	"st":       pluralRuleAf,
+	"su":       pluralRuleBm,
+	"sv":       pluralRuleAst,
+	"sw":       pluralRuleAst,
+	"syr":      pluralRuleAf,
+	"ta":       pluralRuleAf,
+	"te":       pluralRuleAf,
+	"teo":      pluralRuleAf,
+	"th":       pluralRuleBm,
+	"ti":       pluralRuleAk,
+	"tig":      pluralRuleAf,
+	"tk":       pluralRuleAf,
+	"tl":       pluralRuleCeb,
+	"tn":       pluralRuleAf,
+	"to":       pluralRuleBm,
+	"tpi":      pluralRuleBm,
+	"tr":       pluralRuleAf,
+	"ts":       pluralRuleAf,
+	"tzm":      pluralRuleTzm,
+	"ug":       pluralRuleAf,
+	"uk":       pluralRuleRu,
+	"ur":       pluralRuleAst,
+	"uz":       pluralRuleAf,
+	"ve":       pluralRuleAf,
+	"vec":      pluralRuleCa,
+	"vi":       pluralRuleBm,
+	"vo":       pluralRuleAf,
+	"vun":      pluralRuleAf,
+	"wa":       pluralRuleAk,
+	"wae":      pluralRuleAf,
+	"wo":       pluralRuleBm,
+	"xh":       pluralRuleAf,
+	"xog":      pluralRuleAf,
+	"yi":       pluralRuleAst,
+	"yo":       pluralRuleBm,
+	"yue":      pluralRuleBm,
+	"zh":       pluralRuleBm,
+	"zu":       pluralRuleAm,
+}
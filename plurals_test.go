@@ -0,0 +1,188 @@
+package androidbinary
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPluralQuantityFor(t *testing.T) {
+	testCases := []struct {
+		locale string
+		n      int
+		want   PluralQuantity
+	}{
+		{"", 1, PluralOne},
+		{"en-US", 0, PluralOther},
+		{"en-US", 1, PluralOne},
+		{"en-US", -1, PluralOne},
+		{"ja", 1, PluralOther},
+		{"fr", 0, PluralOne},
+		{"fr", 2, PluralOther},
+		{"fr", 1000000, PluralMany},
+		{"pt_BR", 1, PluralOne},
+		{"ru", 1, PluralOne},
+		{"ru", 11, PluralMany},
+		{"ru", 21, PluralOne},
+		{"ru", 22, PluralFew},
+		{"ru", 25, PluralMany},
+		{"pl", 1, PluralOne},
+		{"pl", 21, PluralMany},
+		{"pl", 102, PluralFew},
+		{"cs", 3, PluralFew},
+		{"cs", 5, PluralOther},
+		{"ar", 0, PluralZero},
+		{"ar", 2, PluralTwo},
+		{"ar", 103, PluralFew},
+		{"ar", 111, PluralMany},
+		{"ar", 100, PluralOther},
+		{"cy", 6, PluralMany},
+		{"lv", 10, PluralZero},
+		{"lv", 21, PluralOne},
+		{"ro", 1, PluralOne},
+		{"ro", 0, PluralFew},
+		{"ro", 19, PluralFew},
+		{"ro", 20, PluralOther},
+		{"ro", 101, PluralFew},
+		{"ro", 119, PluralFew},
+		{"pt", 0, PluralOne},
+		{"pt-BR", 0, PluralOne},
+		{"pt-PT", 0, PluralOther},
+		{"pt_PT", 1, PluralOne},
+		{"pt-PT", 1000000, PluralMany},
+		{"fil", 1, PluralOne},
+		{"fil", 4, PluralOther},
+		{"fil", 5, PluralOne},
+		{"fil", 14, PluralOther},
+		{"tl", 5, PluralOne},
+		{"is", 1, PluralOne},
+		{"is", 11, PluralOther},
+		{"is", 21, PluralOne},
+		{"mk", 11, PluralOther},
+		{"mk", 21, PluralOne},
+		{"pa", 0, PluralOne},
+		{"pa", 2, PluralOther},
+		{"si", 0, PluralOne},
+		{"si", 2, PluralOther},
+		{"as", 0, PluralOne},
+		{"br", 1, PluralOne},
+		{"br", 11, PluralOther},
+		{"br", 22, PluralTwo},
+		{"br", 9, PluralFew},
+		{"br", 79, PluralOther},
+		{"br", 1000000, PluralMany},
+		{"iw", 2, PluralTwo},
+		{"in", 1, PluralOther},
+	}
+	for _, tc := range testCases {
+		if got := PluralQuantityFor(tc.locale, tc.n); got != tc.want {
+			t.Errorf("%s %d: want %s, got %s", tc.locale, tc.n, tc.want, got)
+		}
+	}
+}
+
+func TestPluralQuantities(t *testing.T) {
+	testCases := []struct {
+		locale string
+		want   []PluralQuantity
+	}{
+		{"en", []PluralQuantity{PluralOne, PluralOther}},
+		{"ja", []PluralQuantity{PluralOther}},
+		{"fr", []PluralQuantity{PluralOne, PluralMany, PluralOther}},
+		{"ru", []PluralQuantity{PluralOne, PluralFew, PluralMany, PluralOther}},
+		{"cs", []PluralQuantity{PluralOne, PluralFew, PluralOther}},
+		{"ar", []PluralQuantity{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}},
+		{"ro", []PluralQuantity{PluralOne, PluralFew, PluralOther}},
+		{"pt-PT", []PluralQuantity{PluralOne, PluralMany, PluralOther}},
+		{"br", []PluralQuantity{PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}},
+	}
+	for _, tc := range testCases {
+		if got := PluralQuantities(tc.locale); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: want %v, got %v", tc.locale, tc.want, got)
+		}
+	}
+}
+
+func TestGetQuantityString(t *testing.T) {
+	str := func(i uint32) ResValue {
+		return ResValue{Size: 8, DataType: TypeString, Data: i}
+	}
+	plurals := func(items ...ResTableMap) TableEntry {
+		return TableEntry{
+			Key: &ResTableEntry{Size: 16, Flags: EntryFlagComplex},
+			Map: items,
+		}
+	}
+	config := func(lang string) *ResTableConfig {
+		c := &ResTableConfig{}
+		copy(c.Language[:], lang)
+		return c
+	}
+	tableFile := &TableFile{
+		stringPool: &ResStringPool{
+			Strings: []string{"%d message", "%d messages", "%d сообщение", "%d сообщения", "%d сообщений"},
+		},
+		tablePackages: map[uint32]*TablePackage{
+			0x7f: {
+				TableTypes: []*TableType{
+					{
+						Header: &ResTableType{ID: 1},
+						Entries: []TableEntry{plurals(
+							ResTableMap{Name: AttrPluralOne, Value: str(0)},
+							ResTableMap{Name: AttrPluralOther, Value: str(1)},
+						)},
+					},
+					{
+						Header: &ResTableType{ID: 1, Config: *config("ru")},
+						Entries: []TableEntry{plurals(
+							ResTableMap{Name: AttrPluralOne, Value: str(2)},
+							ResTableMap{Name: AttrPluralFew, Value: str(3)},
+							ResTableMap{Name: AttrPluralOther, Value: str(4)},
+						)},
+					},
+					{
+						Header: &ResTableType{ID: 2},
+						Entries: []TableEntry{{
+							Key:   &ResTableEntry{Size: 8},
+							Value: &ResValue{Size: 8, DataType: TypeString, Data: 0},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		config *ResTableConfig
+		n      int
+		want   string
+	}{
+		{&ResTableConfig{}, 1, "%d message"},
+		{&ResTableConfig{}, 2, "%d messages"},
+		{config("en"), 0, "%d messages"},
+		{config("ru"), 21, "%d сообщение"},
+		{config("ru"), 3, "%d сообщения"},
+
+		// "many" falls back to "other".
+		{config("ru"), 5, "%d сообщений"},
+
+		// the rule of the chosen entry is used without config.
+		{nil, 22, "%d сообщения"},
+	}
+	for _, tc := range testCases {
+		got, err := tableFile.GetQuantityString(0x7f010000, tc.config, tc.n)
+		if err != nil {
+			t.Errorf("%v %d: %v", tc.config, tc.n, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%v %d: want %q, got %q", tc.config, tc.n, tc.want, got)
+		}
+	}
+
+	if _, err := tableFile.GetQuantityString(0x7f020000, nil, 1); err == nil {
+		t.Error("want error for the string resource, got nil")
+	}
+	if _, err := tableFile.GetQuantityString(0x7f010001, nil, 1); err == nil {
+		t.Error("want error for the missing resource, got nil")
+	}
+}
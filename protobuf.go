@@ -0,0 +1,148 @@
+package androidbinary
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// protocol buffers wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+var errInvalidProto = errors.New("androidbinary: invalid protocol buffers message")
+
+// protoReader is a minimal decoder of the protocol buffers wire format,
+// which is enough to read the messages that aapt2 writes.
+type protoReader struct {
+	data []byte
+}
+
+// next reads the key of the next field.
+// It returns false if all fields are read.
+func (r *protoReader) next() (field int, wireType int, ok bool, err error) {
+	if len(r.data) == 0 {
+		return 0, 0, false, nil
+	}
+	key, err := r.varint()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	field, wireType = int(key>>3), int(key&0x7)
+	if field == 0 {
+		return 0, 0, false, errInvalidProto
+	}
+	return field, wireType, true, nil
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		return 0, errInvalidProto
+	}
+	r.data = r.data[n:]
+	return v, nil
+}
+
+func (r *protoReader) fixed32() (uint32, error) {
+	if len(r.data) < 4 {
+		return 0, errInvalidProto
+	}
+	v := binary.LittleEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return v, nil
+}
+
+func (r *protoReader) bytes() ([]byte, error) {
+	l, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if l > uint64(len(r.data)) {
+		return nil, errInvalidProto
+	}
+	v := r.data[:l]
+	r.data = r.data[l:]
+	return v, nil
+}
+
+// skip skips the value of the field.
+func (r *protoReader) skip(wireType int) error {
+	var err error
+	switch wireType {
+	case protoVarint:
+		_, err = r.varint()
+	case protoFixed64:
+		if len(r.data) < 8 {
+			return errInvalidProto
+		}
+		r.data = r.data[8:]
+	case protoBytes:
+		_, err = r.bytes()
+	case protoFixed32:
+		_, err = r.fixed32()
+	default:
+		err = errInvalidProto
+	}
+	return err
+}
+
+// readUint reads a varint field.
+func (r *protoReader) readUint(wireType int) (uint32, error) {
+	if wireType != protoVarint {
+		return 0, errInvalidProto
+	}
+	v, err := r.varint()
+	return uint32(v), err
+}
+
+// readString reads a length-delimited field as a string.
+func (r *protoReader) readString(wireType int) (string, error) {
+	if wireType != protoBytes {
+		return "", errInvalidProto
+	}
+	v, err := r.bytes()
+	return string(v), err
+}
+
+// readMessage reads a length-delimited field as an embedded message.
+func (r *protoReader) readMessage(wireType int) (*protoReader, error) {
+	if wireType != protoBytes {
+		return nil, errInvalidProto
+	}
+	v, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &protoReader{data: v}, nil
+}
+
+// readID reads the embedded message that has the only id field,
+// such as PackageId, TypeId and EntryId.
+func (r *protoReader) readID(wireType int) (uint32, error) {
+	m, err := r.readMessage(wireType)
+	if err != nil {
+		return 0, err
+	}
+	var id uint32
+	for {
+		field, wireType, ok, err := m.next()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return id, nil
+		}
+		if field == 1 {
+			id, err = m.readUint(wireType)
+		} else {
+			err = m.skip(wireType)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package androidbinary
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// testProto encodes a protocol buffers message for testing.
+type testProto []byte
+
+func (m testProto) uvarint(v uint64) testProto {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(m, buf[:n]...)
+}
+
+func (m testProto) key(field, wireType int) testProto {
+	return m.uvarint(uint64(field<<3 | wireType))
+}
+
+func (m testProto) varint(field int, v uint64) testProto {
+	return m.key(field, protoVarint).uvarint(v)
+}
+
+func (m testProto) fixed32(field int, v uint32) testProto {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(m.key(field, protoFixed32), buf[:]...)
+}
+
+func (m testProto) bytes(field int, v []byte) testProto {
+	return append(m.key(field, protoBytes).uvarint(uint64(len(v))), v...)
+}
+
+func (m testProto) string(field int, v string) testProto {
+	return m.bytes(field, []byte(v))
+}
+
+func (m testProto) message(field int, v testProto) testProto {
+	return m.bytes(field, v)
+}
+
+func TestProtoReader(t *testing.T) {
+	data := testProto(nil).
+		varint(1, 300).
+		fixed32(2, 0xdeadbeef).
+		string(3, "hello").
+		key(4, protoFixed64)
+	data = append(data, 1, 2, 3, 4, 5, 6, 7, 8)
+	data = data.varint(5, 1)
+
+	r := &protoReader{data: data}
+	var fields []int
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		fields = append(fields, field)
+		switch field {
+		case 1:
+			if v, err := r.readUint(wireType); err != nil || v != 300 {
+				t.Errorf("want 300, got %d, %v", v, err)
+			}
+		case 2:
+			if v, err := r.fixed32(); err != nil || v != 0xdeadbeef {
+				t.Errorf("want 0xdeadbeef, got %x, %v", v, err)
+			}
+		case 3:
+			if v, err := r.readString(wireType); err != nil || v != "hello" {
+				t.Errorf("want hello, got %q, %v", v, err)
+			}
+		default:
+			if err := r.skip(wireType); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if len(fields) != 5 {
+		t.Errorf("want 5 fields, got %v", fields)
+	}
+
+	// truncated
+	r = &protoReader{data: testProto(nil).string(1, "hello")[:4]}
+	_, wireType, _, err := r.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.readString(wireType); err != errInvalidProto {
+		t.Errorf("want errInvalidProto, got %v", err)
+	}
+}
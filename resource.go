@@ -0,0 +1,88 @@
+package androidbinary
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ConfigEntry is an entry of a resource for a configuration.
+type ConfigEntry struct {
+	Config ResTableConfig
+	Entry  TableEntry
+}
+
+// ResourceIDs returns the IDs of all the resources in the table in ascending order.
+func (f *TableFile) ResourceIDs() []ResID {
+	if f == nil {
+		return nil
+	}
+	seen := make(map[ResID]bool)
+	var ids []ResID
+	for _, p := range f.tablePackages {
+		for _, t := range p.TableTypes {
+			for i, e := range t.allEntries() {
+				if e.Key == nil {
+					continue
+				}
+				id := ResID(p.Header.ID<<24 | uint32(t.Header.ID)<<16 | uint32(i))
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// GetResourceName returns the type and the name of the resource referenced by id,
+// such as "string" and "app_name" for @string/app_name.
+func (f *TableFile) GetResourceName(id ResID) (typ, name string, err error) {
+	p := f.findPackage(id.Package())
+	if p == nil {
+		return "", "", fmt.Errorf("androidbinary: package 0x%02X not found", id.Package())
+	}
+	entries := p.findEntries(id.Type(), id.Entry())
+	if len(entries) == 0 {
+		return "", "", fmt.Errorf("androidbinary: entry 0x%04X not found", id.Entry())
+	}
+
+	// the type IDs start from 1.
+	typeRef := ResStringPoolRef(id.Type() - 1)
+	if !p.TypeStrings.HasString(typeRef) {
+		return "", "", &InvalidReferenceError{Ref: typeRef}
+	}
+	keyRef := entries[0].Entry.Key.Key
+	if !p.KeyStrings.HasString(keyRef) {
+		return "", "", &InvalidReferenceError{Ref: keyRef}
+	}
+	return p.TypeStrings.GetString(typeRef), p.KeyStrings.GetString(keyRef), nil
+}
+
+// GetEntries returns the entries of the resource referenced by id for all the configurations.
+// It returns nil if the table doesn't contain id.
+func (f *TableFile) GetEntries(id ResID) []ConfigEntry {
+	p := f.findPackage(id.Package())
+	if p == nil {
+		return nil
+	}
+	return p.findEntries(id.Type(), id.Entry())
+}
+
+// findEntries returns the entries of all the configurations.
+func (p *TablePackage) findEntries(typeIndex, entryIndex int) []ConfigEntry {
+	var entries []ConfigEntry
+	for _, t := range p.TableTypes {
+		if int(t.Header.ID) != typeIndex {
+			continue
+		}
+		if e := t.entry(entryIndex); e.Key != nil {
+			entries = append(entries, ConfigEntry{
+				Config: t.Header.Config,
+				Entry:  e,
+			})
+		}
+	}
+	return entries
+}
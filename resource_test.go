@@ -0,0 +1,55 @@
+package androidbinary
+
+import (
+	"testing"
+)
+
+func TestResourceIDs(t *testing.T) {
+	tableFile := loadTestData()
+	ids := tableFile.ResourceIDs()
+	if len(ids) == 0 {
+		t.Fatal("want resources, got none")
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i-1] >= ids[i] {
+			t.Errorf("not sorted: %v, %v", ids[i-1], ids[i])
+		}
+	}
+
+	typ, name, err := tableFile.GetResourceName(ResID(0x7f040000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ != "string" || name != "app_name" {
+		t.Errorf("want string/app_name, got %s/%s", typ, name)
+	}
+	if _, _, err := tableFile.GetResourceName(ResID(0x7f04ffff)); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestGetEntries(t *testing.T) {
+	for _, tableFile := range []*TableFile{loadTestData(), loadLazyTestData(t)} {
+		entries := tableFile.GetEntries(ResID(0x7f040000))
+		locales := make(map[string]string)
+		for _, e := range entries {
+			locales[e.Config.Locale()] = tableFile.GetString(ResStringPoolRef(e.Entry.Value.Data))
+		}
+		if locales[""] != "FireworksMeasure" || locales["ja"] != "花火距離計算" {
+			t.Errorf("unexpected entries: %v", locales)
+		}
+
+		// string-array is a complex entry.
+		entries = tableFile.GetEntries(ResID(0x7f050000))
+		if len(entries) == 0 {
+			t.Fatal("want entries, got none")
+		}
+		e := entries[0].Entry
+		if e.Key.Flags&EntryFlagComplex == 0 || e.Value != nil || len(e.Map) != 16 {
+			t.Errorf("unexpected complex entry: %+v", e)
+		}
+		if _, err := tableFile.GetResource(ResID(0x7f050000), nil); err == nil {
+			t.Error("want error for the complex resource, got nil")
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package androidbinary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// GetSpans returns the spans of the string referenced by ref.
+// It returns nil if the string has no spans.
+func (pool *ResStringPool) GetSpans(ref ResStringPoolRef) []ResStringPoolSpan {
+	if pool == nil || int64(ref) >= int64(len(pool.Spans)) {
+		return nil
+	}
+	return pool.Spans[ref]
+}
+
+// GetStyledString returns the string referenced by ref with its spans
+// as the HTML-like markup of Android string resources, such as "Hello, <b>World</b>!".
+// The names of the spans with attributes, such as "annotation;key=value", are rendered as
+// the tags with the attributes, such as `<annotation key="value">`.
+// The text of the string is escaped.
+// It returns the empty string if the pool doesn't contain ref.
+func (pool *ResStringPool) GetStyledString(ref ResStringPoolRef) string {
+	if !pool.HasString(ref) {
+		return ""
+	}
+	var spans []styleSpan
+	for _, span := range pool.GetSpans(ref) {
+		if !pool.HasString(span.Name) {
+			continue
+		}
+		tag, attrs := parseSpanName(pool.GetString(span.Name))
+		if tag == "" {
+			continue
+		}
+		spans = append(spans, styleSpan{
+			tag:   tag,
+			attrs: attrs,
+			first: int64(span.FirstChar),
+			last:  int64(span.LastChar),
+		})
+	}
+	return renderStyledString(pool.GetString(ref), spans)
+}
+
+// GetStyledString returns a string referenced by ref with its spans.
+// See ResStringPool.GetStyledString.
+func (f *TableFile) GetStyledString(ref ResStringPoolRef) string {
+	return f.stringPool.GetStyledString(ref)
+}
+
+// GetStyledResource returns the string resource referenced by id with its spans, such as "Hello, <b>World</b>!".
+// See ResStringPool.GetStyledString.
+func (f *TableFile) GetStyledResource(id ResID, config *ResTableConfig) (string, error) {
+	p := f.findPackage(id.Package())
+	if p == nil {
+		return "", fmt.Errorf("androidbinary: package 0x%02X not found", id.Package())
+	}
+	e := p.findEntry(id.Type(), id.Entry(), config)
+	if e.Key == nil {
+		return "", fmt.Errorf("androidbinary: entry 0x%04X not found", id.Entry())
+	}
+	v := e.Value
+	if v == nil || v.DataType != TypeString {
+		return "", fmt.Errorf("androidbinary: resource 0x%08X is not a string", uint32(id))
+	}
+	ref := ResStringPoolRef(v.Data)
+	if !f.stringPool.HasString(ref) {
+		return "", &InvalidReferenceError{Ref: ref}
+	}
+	return f.GetStyledString(ref), nil
+}
+
+type styleSpan struct {
+	tag   string
+	attrs [][2]string
+
+	// first and last are the range of the span in UTF-16 code units, both inclusive.
+	first, last int64
+}
+
+// parseSpanName parses the name of the span, such as "b" and "annotation;key=value;key2=value2".
+func parseSpanName(name string) (string, [][2]string) {
+	fields := strings.Split(name, ";")
+	var attrs [][2]string
+	for _, field := range fields[1:] {
+		i := strings.Index(field, "=")
+		if i <= 0 {
+			continue
+		}
+		attrs = append(attrs, [2]string{field[:i], field[i+1:]})
+	}
+	return fields[0], attrs
+}
+
+// renderStyledString renders s with the spans as the HTML-like markup.
+func renderStyledString(s string, spans []styleSpan) string {
+	var buf strings.Builder
+	units := utf16.Encode([]rune(s))
+	n := int64(len(units))
+
+	// the outer spans are opened first.
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].first != spans[j].first {
+			return spans[i].first < spans[j].first
+		}
+		return spans[i].last > spans[j].last
+	})
+
+	var open []styleSpan
+	var pos, next int64
+	flush := func(i int64) {
+		if pos < i {
+			escapeText(&buf, string(utf16.Decode(units[pos:i])))
+			pos = i
+		}
+	}
+	for i := int64(0); i <= n; i++ {
+		// close the spans that end before i.
+		// If they are not nested properly, the inner spans are closed and reopened.
+		for j, span := range open {
+			if span.last >= i {
+				continue
+			}
+			flush(i)
+			for k := len(open) - 1; k >= j; k-- {
+				writeEndTag(&buf, open[k])
+			}
+			rest := open[j+1:]
+			open = open[:j]
+			for _, span := range rest {
+				if span.last >= i {
+					writeStartTag(&buf, span)
+					open = append(open, span)
+				}
+			}
+			break
+		}
+
+		// open the spans that start at i.
+		for ; next < int64(len(spans)) && spans[next].first <= i; next++ {
+			flush(i)
+			writeStartTag(&buf, spans[next])
+			open = append(open, spans[next])
+		}
+	}
+	flush(n)
+	for k := len(open) - 1; k >= 0; k-- {
+		writeEndTag(&buf, open[k])
+	}
+	return buf.String()
+}
+
+func writeStartTag(buf *strings.Builder, span styleSpan) {
+	buf.WriteString("<")
+	buf.WriteString(span.tag)
+	for _, attr := range span.attrs {
+		buf.WriteString(" ")
+		buf.WriteString(attr[0])
+		buf.WriteString(`="`)
+		escapeText(buf, attr[1])
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+}
+
+func writeEndTag(buf *strings.Builder, span styleSpan) {
+	buf.WriteString("</")
+	buf.WriteString(span.tag)
+	buf.WriteString(">")
+}
+
+// markupEscaper escapes the text and the attribute values of the markup.
+var markupEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func escapeText(buf *strings.Builder, s string) {
+	markupEscaper.WriteString(buf, s)
+}
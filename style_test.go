@@ -0,0 +1,59 @@
+package androidbinary
+
+import "testing"
+
+func TestGetStyledString(t *testing.T) {
+	pool := &ResStringPool{
+		Strings: []string{
+			"b",
+			"i",
+			"annotation;key=value;quote=\"",
+			"Hello, World!",
+			"bold italic",
+			"a < b & c",
+			"\U0001F600 smile",
+			"plain",
+		},
+		Spans: [][]ResStringPoolSpan{
+			nil,
+			nil,
+			nil,
+			{{Name: 0, FirstChar: 7, LastChar: 11}},
+			{{Name: 1, FirstChar: 5, LastChar: 10}, {Name: 0, FirstChar: 0, LastChar: 6}},
+			{{Name: 2, FirstChar: 2, LastChar: 2}},
+			{{Name: 0, FirstChar: 0, LastChar: 1}, {Name: 0x7FFFFFFF, FirstChar: 0, LastChar: 1}},
+		},
+	}
+
+	tests := []struct {
+		ref  ResStringPoolRef
+		want string
+	}{
+		{3, "Hello, <b>World</b>!"},
+		{4, "<b>bold <i>it</i></b><i>alic</i>"},
+		{5, `a <annotation key="value" quote="&quot;">&lt;</annotation> b &amp; c`},
+		{6, "<b>\U0001F600</b> smile"},
+		{7, "plain"},
+		{8, ""},
+	}
+	for _, tt := range tests {
+		if got := pool.GetStyledString(tt.ref); got != tt.want {
+			t.Errorf("%d: got %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+
+	if spans := pool.GetSpans(7); spans != nil {
+		t.Errorf("want no spans, got %v", spans)
+	}
+}
+
+func TestGetStyledResource(t *testing.T) {
+	tableFile := loadTestData()
+	val, err := tableFile.GetStyledResource(ResID(0x7f040000), &ResTableConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "FireworksMeasure" {
+		t.Errorf(`got %v want "FireworksMeasure"`, val)
+	}
+}
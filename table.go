@@ -2,6 +2,7 @@ package androidbinary
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -16,6 +17,7 @@ type ResID uint32
 type TableFile struct {
 	stringPool    *ResStringPool
 	tablePackages map[uint32]*TablePackage
+	warnings      []*ParseError
 }
 
 // ResTableHeader is a header of TableFile.
@@ -158,6 +160,10 @@ type ResTableConfig struct {
 type TableType struct {
 	Header  *ResTableType
 	Entries []TableEntry
+
+	// lazy is the type chunk, if the entries are read from it on demand.
+	// Entries is empty in that case.
+	lazy *io.SectionReader
 }
 
 // ResTableEntry is the beginning of information about an entry in the resource table.
@@ -167,11 +173,36 @@ type ResTableEntry struct {
 	Key   ResStringPoolRef
 }
 
+// The flags of ResTableEntry.
+const (
+	// EntryFlagComplex is set if the entry is a complex entry, which is a map of the attributes to the values,
+	// such as styles, plurals and arrays.
+	EntryFlagComplex uint16 = 0x0001
+
+	// EntryFlagPublic is set if the entry is public.
+	EntryFlagPublic uint16 = 0x0002
+
+	// EntryFlagWeak is set if the entry can be overridden by the other packages.
+	EntryFlagWeak uint16 = 0x0004
+)
+
 // TableEntry is a entry in a resource table.
 type TableEntry struct {
 	Key   *ResTableEntry
 	Value *ResValue
 	Flags uint32
+
+	// Parent and Map are the parent and the items of the complex entry, if EntryFlagComplex is set.
+	// Value is nil for the complex entries.
+	Parent ResID
+	Map    []ResTableMap
+}
+
+// ResTableMap is an item of the complex entries.
+type ResTableMap struct {
+	// Name is the attribute of the item, such as the attributes of styles and the quantities of plurals.
+	Name  ResID
+	Value ResValue
 }
 
 // ResTableTypeSpec is specification of the resources defined by a particular type.
@@ -221,24 +252,75 @@ func (id ResID) Entry() int {
 
 // NewTableFile returns new TableFile.
 func NewTableFile(r io.ReaderAt) (*TableFile, error) {
+	return NewTableFileWithOptions(r, nil)
+}
+
+// NewTableFileLazy returns new TableFile that reads the strings and the entries from r on demand.
+// Only the chunk headers are read when it is created, so it is cheaper than NewTableFile
+// if a few resources are looked up in a large table.
+// r must be kept readable while the TableFile is used.
+func NewTableFileLazy(r io.ReaderAt) (*TableFile, error) {
+	return NewTableFileWithOptions(r, &ParseOptions{Lazy: true})
+}
+
+// NewTableFileWithOptions is same as NewTableFile, but it accepts options.
+// The errors in parsing are *ParseError.
+func NewTableFileWithOptions(r io.ReaderAt, opts *ParseOptions) (*TableFile, error) {
+	f, err := newTableFile(r, opts)
+	if err != nil {
+		return nil, fileError(FileKindTable, err)
+	}
+	return f, nil
+}
+
+func newTableFile(r io.ReaderAt, opts *ParseOptions) (*TableFile, error) {
 	f := new(TableFile)
-	sr := io.NewSectionReader(r, 0, 1<<63-1)
+	p := newParser(FileKindTable, opts)
+	sr := io.NewSectionReader(r, 0, readerSize(r))
 
 	header := new(ResTableHeader)
-	binary.Read(sr, binary.LittleEndian, header)
+	if err := binary.Read(sr, binary.LittleEndian, header); err != nil {
+		return nil, chunkError(ResTableChunkType, 0, err)
+	}
 	f.tablePackages = make(map[uint32]*TablePackage)
 
+	end := int64(header.Header.Size)
+	if end > sr.Size() && p.lenient() {
+		err := fmt.Errorf("androidbinary: chunk size %d exceeds the file size %d", end, sr.Size())
+		if err := p.recover(ResTableChunkType, err); err != nil {
+			return nil, err
+		}
+		end = sr.Size()
+	}
 	offset := int64(header.Header.HeaderSize)
-	for offset < int64(header.Header.Size) {
-		chunkHeader, err := f.readChunk(sr, offset)
+	for offset < end {
+		if err := p.chunk(); err != nil {
+			return nil, chunkError(ResNullChunkType, offset, err)
+		}
+		p.base = offset
+		chunkHeader, err := f.readChunk(sr, offset, p)
 		if err != nil {
-			return nil, err
+			// skip the broken chunk in the lenient mode, or stop if the next chunk can't be found.
+			p.base = offset
+			if err := p.recover(ResNullChunkType, err); err != nil {
+				return nil, err
+			}
+			if chunkHeader == nil {
+				break
+			}
 		}
 		offset += int64(chunkHeader.Size)
 	}
+	f.warnings = p.warnings
 	return f, nil
 }
 
+// Warnings returns the problems that are tolerated in the lenient mode.
+// See ParseOptions.Lenient.
+func (f *TableFile) Warnings() []*ParseError {
+	return f.warnings
+}
+
 func (f *TableFile) findPackage(id uint32) *TablePackage {
 	if f == nil {
 		return nil
@@ -247,25 +329,68 @@ func (f *TableFile) findPackage(id uint32) *TablePackage {
 }
 
 func (p *TablePackage) findEntry(typeIndex, entryIndex int, config *ResTableConfig) TableEntry {
+	_, e := p.findBestEntry(typeIndex, entryIndex, config)
+	return e
+}
+
+// findBestEntry returns the entry that matches config best, and the type that contains it.
+func (p *TablePackage) findBestEntry(typeIndex, entryIndex int, config *ResTableConfig) (*TableType, TableEntry) {
 	var best *TableType
+	var bestEntry TableEntry
 	for _, t := range p.TableTypes {
+		if int(t.Header.ID) != typeIndex || !t.Header.Config.Match(config) {
+			continue
+		}
+		e := t.entry(entryIndex)
 		switch {
-		case int(t.Header.ID) != typeIndex:
-			// nothing to do
-		case !t.Header.Config.Match(config):
-			// nothing to do
-		case entryIndex >= len(t.Entries):
-			// nothing to do
-		case t.Entries[entryIndex].Value == nil:
+		case e.Key == nil:
 			// nothing to do
 		case best == nil || t.Header.Config.IsBetterThan(&best.Header.Config, config):
-			best = t
+			best, bestEntry = t, e
 		}
 	}
-	if best == nil || entryIndex >= len(best.Entries) {
+	return best, bestEntry
+}
+
+// entry returns the entry of index i, or the zero value if it is not defined.
+func (t *TableType) entry(i int) TableEntry {
+	if t.lazy == nil {
+		if i < 0 || i >= len(t.Entries) {
+			return TableEntry{}
+		}
+		return t.Entries[i]
+	}
+
+	if i < 0 || int64(i) >= int64(t.Header.EntryCount) {
+		return TableEntry{}
+	}
+	var buf [4]byte
+	if _, err := t.lazy.ReadAt(buf[:], int64(t.Header.Header.HeaderSize)+4*int64(i)); err != nil {
+		return TableEntry{}
+	}
+	index := binary.LittleEndian.Uint32(buf[:])
+	if index == 0xFFFFFFFF {
+		return TableEntry{}
+	}
+
+	// readTableType fails if the entry is truncated, but it is treated as not defined here.
+	e, err := readTableEntry(t.lazy, int64(t.Header.EntriesStart)+int64(index), nil)
+	if err != nil {
 		return TableEntry{}
 	}
-	return best.Entries[entryIndex]
+	return e
+}
+
+// allEntries returns all the entries of the type.
+func (t *TableType) allEntries() []TableEntry {
+	if t.lazy == nil {
+		return t.Entries
+	}
+	entries := make([]TableEntry, t.Header.EntryCount)
+	for i := range entries {
+		entries[i] = t.entry(i)
+	}
+	return entries
 }
 
 // GetResource returns a resource referenced by id.
@@ -275,15 +400,22 @@ func (f *TableFile) GetResource(id ResID, config *ResTableConfig) (interface{},
 		return nil, fmt.Errorf("androidbinary: package 0x%02X not found", id.Package())
 	}
 	e := p.findEntry(id.Type(), id.Entry(), config)
+	if e.Key == nil {
+		return nil, fmt.Errorf("androidbinary: entry 0x%04X not found", id.Entry())
+	}
 	v := e.Value
 	if v == nil {
-		return nil, fmt.Errorf("androidbinary: entry 0x%04X not found", id.Entry())
+		return nil, fmt.Errorf("androidbinary: entry 0x%04X is a complex resource", id.Entry())
 	}
 	switch v.DataType {
 	case TypeNull:
 		return nil, nil
 	case TypeString:
-		return f.GetString(ResStringPoolRef(v.Data)), nil
+		ref := ResStringPoolRef(v.Data)
+		if !f.stringPool.HasString(ref) {
+			return nil, &InvalidReferenceError{Ref: ref}
+		}
+		return f.GetString(ref), nil
 	case TypeIntDec:
 		return v.Data, nil
 	case TypeIntHex:
@@ -295,86 +427,243 @@ func (f *TableFile) GetResource(id ResID, config *ResTableConfig) (interface{},
 }
 
 // GetString returns a string referenced by ref.
+// It returns the empty string if the table doesn't contain ref.
 func (f *TableFile) GetString(ref ResStringPoolRef) string {
 	return f.stringPool.GetString(ref)
 }
 
-func (f *TableFile) readChunk(r io.ReaderAt, offset int64) (*ResChunkHeader, error) {
-	sr := io.NewSectionReader(r, offset, 1<<63-1-offset)
-	chunkHeader := &ResChunkHeader{}
-	if _, err := sr.Seek(0, io.SeekStart); err != nil {
-		return nil, err
+// MergeTableFiles returns a new TableFile that contains all resources of the tables,
+// in the same way as the resources of a base APK and its split APKs are loaded together.
+// The packages of the same ID are merged, and GetResource chooses the best entry among all the tables.
+// The tables are not modified.
+func MergeTableFiles(tables ...*TableFile) *TableFile {
+	merged := &TableFile{
+		stringPool:    new(ResStringPool),
+		tablePackages: make(map[uint32]*TablePackage),
+	}
+	for _, t := range tables {
+		if t == nil {
+			continue
+		}
+		offset := uint32(len(merged.stringPool.Strings))
+		if t.stringPool != nil {
+			merged.stringPool.Strings = append(merged.stringPool.Strings, t.stringPool.allStrings()...)
+			mergeStringPoolSpans(merged.stringPool, t.stringPool, offset)
+		}
+		for id, p := range t.tablePackages {
+			mp := merged.tablePackages[id]
+			if mp == nil {
+				mp = &TablePackage{
+					Header:      p.Header,
+					TypeStrings: new(ResStringPool),
+					KeyStrings:  new(ResStringPool),
+				}
+				merged.tablePackages[id] = mp
+			}
+			mergeTablePackage(mp, p, offset)
+		}
+	}
+	merged.stringPool.Header.StringCount = uint32(len(merged.stringPool.Strings))
+	merged.stringPool.Header.StyleCount = uint32(len(merged.stringPool.Styles))
+	return merged
+}
+
+// mergeStringPoolSpans adds the spans of pool into merged.
+// offset is the offset of the strings of pool in merged.
+func mergeStringPoolSpans(merged, pool *ResStringPool, offset uint32) {
+	if len(pool.Spans) == 0 {
+		return
+	}
+	// the spans are indexed by the strings, so pad them to the strings of the former tables.
+	for len(merged.Spans) < int(offset) {
+		merged.Spans = append(merged.Spans, nil)
+		merged.Styles = append(merged.Styles, ResStringPoolSpan{})
+	}
+	// the names of the spans are in the same pool.
+	// the span lists shared by the strings are kept shared.
+	shifted := make(map[*ResStringPoolSpan][]ResStringPoolSpan)
+	for _, spans := range pool.Spans {
+		var style ResStringPoolSpan
+		if len(spans) > 0 {
+			list, ok := shifted[&spans[0]]
+			if !ok {
+				list = make([]ResStringPoolSpan, len(spans))
+				for i, span := range spans {
+					span.Name += ResStringPoolRef(offset)
+					list[i] = span
+				}
+				shifted[&spans[0]] = list
+			}
+			spans = list
+			style = list[0]
+		}
+		merged.Spans = append(merged.Spans, spans)
+		merged.Styles = append(merged.Styles, style)
+	}
+}
+
+// mergeTablePackage adds the types of p into merged.
+// offset is the offset of the global string pool of p in the merged string pool.
+func mergeTablePackage(merged, p *TablePackage, offset uint32) {
+	if p.TypeStrings != nil && len(p.TypeStrings.allStrings()) > len(merged.TypeStrings.allStrings()) {
+		merged.TypeStrings = p.TypeStrings
+	}
+	keyOffset := uint32(len(merged.KeyStrings.Strings))
+	if p.KeyStrings != nil {
+		merged.KeyStrings.Strings = append(merged.KeyStrings.Strings, p.KeyStrings.allStrings()...)
+		merged.KeyStrings.Header.StringCount = uint32(len(merged.KeyStrings.Strings))
+	}
+
+	for _, t := range p.TableTypes {
+		entries := make([]TableEntry, len(t.allEntries()))
+		for i, e := range t.allEntries() {
+			entries[i].Flags = e.Flags
+			if e.Key != nil {
+				key := *e.Key
+				key.Key += ResStringPoolRef(keyOffset)
+				entries[i].Key = &key
+			}
+			if e.Value != nil {
+				value := *e.Value
+				if value.DataType == TypeString {
+					value.Data += offset
+				}
+				entries[i].Value = &value
+			}
+			entries[i].Parent = e.Parent
+			if e.Map != nil {
+				entries[i].Map = make([]ResTableMap, len(e.Map))
+				for j, m := range e.Map {
+					if m.Value.DataType == TypeString {
+						m.Value.Data += offset
+					}
+					entries[i].Map[j] = m
+				}
+			}
+		}
+		merged.TableTypes = append(merged.TableTypes, &TableType{
+			Header:  t.Header,
+			Entries: entries,
+		})
 	}
+}
+
+func (f *TableFile) readChunk(r *io.SectionReader, offset int64, p *parser) (*ResChunkHeader, error) {
+	sr := newSectionReader(r, offset, r.Size()-offset)
+	chunkHeader := &ResChunkHeader{}
 	if err := binary.Read(sr, binary.LittleEndian, chunkHeader); err != nil {
-		return nil, err
+		return nil, chunkError(ResNullChunkType, offset, err)
+	}
+	if err := checkChunkHeader(chunkHeader); err != nil {
+		return nil, chunkError(chunkHeader.Type, offset, err)
 	}
 
 	var err error
 	if _, err := sr.Seek(0, io.SeekStart); err != nil {
-		return nil, err
+		return nil, chunkError(chunkHeader.Type, offset, err)
 	}
 	switch chunkHeader.Type {
 	case ResStringPoolChunkType:
-		f.stringPool, err = readStringPool(sr)
+		if p.opts.Lazy {
+			f.stringPool, err = readLazyStringPool(newSectionReader(r, offset, int64(chunkHeader.Size)), p)
+		} else {
+			f.stringPool, err = readStringPool(sr, p)
+		}
 	case ResTablePackageType:
 		var tablePackage *TablePackage
-		tablePackage, err = readTablePackage(sr)
-		f.tablePackages[tablePackage.Header.ID] = tablePackage
+		tablePackage, err = readTablePackage(sr, p)
+		if err == nil {
+			f.tablePackages[tablePackage.Header.ID] = tablePackage
+		}
 	}
 	if err != nil {
-		return nil, err
+		// the header is returned with the error, so that the chunk can be skipped.
+		return chunkHeader, chunkError(chunkHeader.Type, offset, err)
 	}
 
 	return chunkHeader, nil
 }
 
-func readTablePackage(sr *io.SectionReader) (*TablePackage, error) {
+// readTablePackage reads the package chunk.
+// The offsets of the errors in the nested chunks are relative to sr.
+func readTablePackage(sr *io.SectionReader, p *parser) (*TablePackage, error) {
 	tablePackage := new(TablePackage)
 	header := new(ResTablePackage)
 	if err := binary.Read(sr, binary.LittleEndian, header); err != nil {
 		return nil, err
 	}
 	tablePackage.Header = *header
+	base := p.base
 
-	srTypes := io.NewSectionReader(sr, int64(header.TypeStrings), int64(header.Header.Size-header.TypeStrings))
-	if typeStrings, err := readStringPool(srTypes); err == nil {
+	readPool := readStringPool
+	lazy := p.opts.Lazy
+	if lazy {
+		readPool = readLazyStringPool
+	}
+
+	// the broken string pools are empty in the lenient mode.
+	p.base = base + int64(header.TypeStrings)
+	srTypes := newSectionReader(sr, int64(header.TypeStrings), int64(header.Header.Size)-int64(header.TypeStrings))
+	if typeStrings, err := readPool(srTypes, p); err == nil {
 		tablePackage.TypeStrings = typeStrings
+	} else if err := p.recover(ResStringPoolChunkType, err); err == nil {
+		tablePackage.TypeStrings = new(ResStringPool)
 	} else {
-		return nil, err
+		return nil, chunkError(ResStringPoolChunkType, int64(header.TypeStrings), err)
 	}
 
-	srKeys := io.NewSectionReader(sr, int64(header.KeyStrings), int64(header.Header.Size-header.KeyStrings))
-	if keyStrings, err := readStringPool(srKeys); err == nil {
+	p.base = base + int64(header.KeyStrings)
+	srKeys := newSectionReader(sr, int64(header.KeyStrings), int64(header.Header.Size)-int64(header.KeyStrings))
+	if keyStrings, err := readPool(srKeys, p); err == nil {
 		tablePackage.KeyStrings = keyStrings
+	} else if err := p.recover(ResStringPoolChunkType, err); err == nil {
+		tablePackage.KeyStrings = new(ResStringPool)
 	} else {
-		return nil, err
+		return nil, chunkError(ResStringPoolChunkType, int64(header.KeyStrings), err)
 	}
 
 	offset := int64(header.Header.HeaderSize)
 	for offset < int64(header.Header.Size) {
+		if err := p.chunk(); err != nil {
+			return nil, chunkError(ResNullChunkType, offset, err)
+		}
+		p.base = base + offset
 		chunkHeader := &ResChunkHeader{}
 		if _, err := sr.Seek(offset, io.SeekStart); err != nil {
-			return nil, err
+			return nil, chunkError(ResNullChunkType, offset, err)
 		}
 		if err := binary.Read(sr, binary.LittleEndian, chunkHeader); err != nil {
-			return nil, err
+			// the rest of the package is ignored in the lenient mode.
+			if err := p.recover(ResNullChunkType, err); err != nil {
+				return nil, chunkError(ResNullChunkType, offset, err)
+			}
+			break
+		}
+		if err := checkChunkHeader(chunkHeader); err != nil {
+			if err := p.recover(chunkHeader.Type, err); err != nil {
+				return nil, chunkError(chunkHeader.Type, offset, err)
+			}
+			break
 		}
 
 		var err error
-		chunkReader := io.NewSectionReader(sr, offset, int64(chunkHeader.Size))
-		if _, err := sr.Seek(offset, io.SeekStart); err != nil {
-			return nil, err
-		}
-		switch chunkHeader.Type {
-		case ResTableTypeType:
-			var tableType *TableType
-			tableType, err = readTableType(chunkHeader, chunkReader)
-			tablePackage.TableTypes = append(tablePackage.TableTypes, tableType)
-		case ResTableTypeSpecType:
-			_, err = readTableTypeSpec(chunkReader)
+		var tableType *TableType
+		chunkReader := newSectionReader(sr, offset, int64(chunkHeader.Size))
+		switch {
+		case chunkHeader.Type == ResTableTypeType && lazy:
+			tableType, err = readLazyTableType(chunkHeader, chunkReader, p)
+		case chunkHeader.Type == ResTableTypeType:
+			tableType, err = readTableType(chunkHeader, chunkReader, p)
+		case chunkHeader.Type == ResTableTypeSpecType && !lazy:
+			_, err = readTableTypeSpec(chunkReader, p)
 		}
 		if err != nil {
-			return nil, err
+			// skip the broken chunk in the lenient mode.
+			if err := p.recover(chunkHeader.Type, err); err != nil {
+				return nil, chunkError(chunkHeader.Type, offset, err)
+			}
+		} else if tableType != nil {
+			tablePackage.TableTypes = append(tablePackage.TableTypes, tableType)
 		}
 		offset += int64(chunkHeader.Size)
 	}
@@ -382,17 +671,12 @@ func readTablePackage(sr *io.SectionReader) (*TablePackage, error) {
 	return tablePackage, nil
 }
 
-func readTableType(chunkHeader *ResChunkHeader, sr *io.SectionReader) (*TableType, error) {
-	// TableType header may be omitted
-	header := new(ResTableType)
-	if _, err := sr.Seek(0, io.SeekStart); err != nil {
-		return nil, err
-	}
-	buf, err := newZeroFilledReader(sr, int64(chunkHeader.HeaderSize), int64(unsafe.Sizeof(*header)))
+func readTableType(chunkHeader *ResChunkHeader, sr *io.SectionReader, p *parser) (*TableType, error) {
+	header, err := readTableTypeHeader(chunkHeader, sr, p)
 	if err != nil {
 		return nil, err
 	}
-	if err := binary.Read(buf, binary.LittleEndian, header); err != nil {
+	if err := p.alloc(int64(header.EntryCount) * int64(4+unsafe.Sizeof(TableEntry{}))); err != nil {
 		return nil, err
 	}
 
@@ -405,32 +689,161 @@ func readTableType(chunkHeader *ResChunkHeader, sr *io.SectionReader) (*TableTyp
 	}
 
 	entries := make([]TableEntry, header.EntryCount)
+	var broken int
+
+	// a valid type has each map item in only one entry, so the items fit in the chunk.
+	// the entries of the same offset are read only once and shared,
+	// and the entries that overlap each other are rejected by the limit.
+	limit := chunkBodySize(&header.Header, sr.Size()) / 12
+	var total int64
+	decoded := make(map[uint32]TableEntry)
 	for i, index := range entryIndexes {
 		if index == 0xFFFFFFFF {
 			continue
 		}
-		if _, err := sr.Seek(int64(header.EntriesStart+index), io.SeekStart); err != nil {
+		if e, ok := decoded[index]; ok {
+			entries[i] = e
+			continue
+		}
+		e, err := readTableEntry(sr, int64(header.EntriesStart)+int64(index), p)
+		if err != nil {
+			var limitErr *LimitError
+			if !p.lenient() || errors.As(err, &limitErr) {
+				return nil, err
+			}
+			// the broken entries are not defined in the lenient mode.
+			broken++
+			continue
+		}
+		if total += int64(len(e.Map)); total > limit {
+			return nil, &CountError{Type: ResTableTypeType, Field: "EntryCount", Count: header.EntryCount}
+		}
+		decoded[index] = e
+		entries[i] = e
+	}
+	if broken > 0 {
+		if err := p.recover(ResTableTypeType, fmt.Errorf("androidbinary: %d broken entries", broken)); err != nil {
 			return nil, err
 		}
-		var key ResTableEntry
-		binary.Read(sr, binary.LittleEndian, &key)
-		entries[i].Key = &key
+	}
+	return &TableType{
+		Header:  header,
+		Entries: entries,
+	}, nil
+}
+
+// readTableEntry reads the entry at offset in the type chunk sr.
+func readTableEntry(sr *io.SectionReader, offset int64, p *parser) (TableEntry, error) {
+	if err := p.alloc(int64(unsafe.Sizeof(ResTableEntry{}) + unsafe.Sizeof(ResValue{}))); err != nil {
+		return TableEntry{}, err
+	}
+	var buf [16]byte
+	if _, err := sr.ReadAt(buf[:], offset); err != nil {
+		return TableEntry{}, err
+	}
+	key := ResTableEntry{
+		Size:  binary.LittleEndian.Uint16(buf[0:]),
+		Flags: binary.LittleEndian.Uint16(buf[2:]),
+		Key:   ResStringPoolRef(binary.LittleEndian.Uint32(buf[4:])),
+	}
+	if key.Flags&EntryFlagComplex == 0 {
+		return TableEntry{
+			Key: &key,
+			Value: &ResValue{
+				Size:     binary.LittleEndian.Uint16(buf[8:]),
+				Res0:     buf[10],
+				DataType: DataType(buf[11]),
+				Data:     binary.LittleEndian.Uint32(buf[12:]),
+			},
+		}, nil
+	}
+
+	// the complex entry is followed by the items of the map.
+	parent := ResID(binary.LittleEndian.Uint32(buf[8:]))
+	count := binary.LittleEndian.Uint32(buf[12:])
+	if key.Size < 16 {
+		return TableEntry{}, fmt.Errorf("androidbinary: invalid complex entry size: %d", key.Size)
+	}
+	offset += int64(key.Size)
+	if int64(count)*12 > sr.Size()-offset {
+		return TableEntry{}, &CountError{Type: ResTableTypeType, Field: "Count", Count: count}
+	}
+	if err := p.alloc(int64(count) * int64(unsafe.Sizeof(ResTableMap{}))); err != nil {
+		return TableEntry{}, err
+	}
+	items := make([]ResTableMap, count)
+	for i := range items {
+		var buf [12]byte
+		if _, err := sr.ReadAt(buf[:], offset+12*int64(i)); err != nil {
+			return TableEntry{}, err
+		}
+		items[i] = ResTableMap{
+			Name: ResID(binary.LittleEndian.Uint32(buf[0:])),
+			Value: ResValue{
+				Size:     binary.LittleEndian.Uint16(buf[4:]),
+				Res0:     buf[6],
+				DataType: DataType(buf[7]),
+				Data:     binary.LittleEndian.Uint32(buf[8:]),
+			},
+		}
+	}
+	return TableEntry{
+		Key:    &key,
+		Parent: parent,
+		Map:    items,
+	}, nil
+}
 
-		var val ResValue
-		binary.Read(sr, binary.LittleEndian, &val)
-		entries[i].Value = &val
+// readLazyTableType reads the header of the type chunk.
+// The entries are read from sr on demand, so sr must be kept readable while the type is used.
+func readLazyTableType(chunkHeader *ResChunkHeader, sr *io.SectionReader, p *parser) (*TableType, error) {
+	header, err := readTableTypeHeader(chunkHeader, sr, p)
+	if err != nil {
+		return nil, err
 	}
 	return &TableType{
-		header,
-		entries,
+		Header: header,
+		lazy:   sr,
 	}, nil
 }
 
-func readTableTypeSpec(sr *io.SectionReader) ([]uint32, error) {
+func readTableTypeHeader(chunkHeader *ResChunkHeader, sr *io.SectionReader, p *parser) (*ResTableType, error) {
+	// TableType header may be omitted
+	header := new(ResTableType)
+	if _, err := sr.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf, err := newZeroFilledReader(sr, int64(chunkHeader.HeaderSize), int64(unsafe.Sizeof(*header)))
+	if err != nil {
+		return nil, err
+	}
+	if err := binary.Read(buf, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+	if err := checkCount(&header.Header, sr.Size(), "EntryCount", header.EntryCount, 4); err != nil {
+		if err := p.recover(ResTableTypeType, err); err != nil {
+			return nil, err
+		}
+		if n := chunkBodySize(&header.Header, sr.Size()) / 4; n > 0 {
+			header.EntryCount = uint32(n)
+		} else {
+			header.EntryCount = 0
+		}
+	}
+	return header, nil
+}
+
+func readTableTypeSpec(sr *io.SectionReader, p *parser) ([]uint32, error) {
 	header := new(ResTableTypeSpec)
 	if err := binary.Read(sr, binary.LittleEndian, header); err != nil {
 		return nil, err
 	}
+	if err := checkCount(&header.Header, sr.Size(), "EntryCount", header.EntryCount, 4); err != nil {
+		return nil, err
+	}
+	if err := p.alloc(4 * int64(header.EntryCount)); err != nil {
+		return nil, err
+	}
 
 	flags := make([]uint32, header.EntryCount)
 	if _, err := sr.Seek(int64(header.Header.HeaderSize), io.SeekStart); err != nil {
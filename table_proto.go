@@ -0,0 +1,877 @@
+package androidbinary
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// protoTableBuilder builds TableFile from a ResourceTable message of aapt2.
+type protoTableBuilder struct {
+	file    *TableFile
+	strings map[string]uint32
+	keys    map[*TablePackage]map[string]uint32
+}
+
+// NewTableFileFromProto returns a new TableFile from a ResourceTable message of aapt2,
+// which is the format of resources.pb in Android App Bundles.
+// The styles, the arrays and the plurals are decoded into the complex entries, same as resources.arsc.
+// The other compound values, such as attributes and styleables, are not supported.
+//
+// The packages of the same ID are merged, so concatenated ResourceTable messages
+// are also decoded into one TableFile, as the protocol buffers merge them.
+func NewTableFileFromProto(data []byte) (*TableFile, error) {
+	b := &protoTableBuilder{
+		file: &TableFile{
+			stringPool:    new(ResStringPool),
+			tablePackages: make(map[uint32]*TablePackage),
+		},
+		strings: make(map[string]uint32),
+		keys:    make(map[*TablePackage]map[string]uint32),
+	}
+	r := &protoReader{data: data}
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		switch field {
+		case 2: // package
+			var m *protoReader
+			if m, err = r.readMessage(wireType); err == nil {
+				err = b.readPackage(m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	b.file.stringPool.Header.StringCount = uint32(len(b.file.stringPool.Strings))
+	return b.file, nil
+}
+
+// stringRef returns the reference to s in the global string pool, adding s if needed.
+func (b *protoTableBuilder) stringRef(s string) ResStringPoolRef {
+	ref, ok := b.strings[s]
+	if !ok {
+		ref = uint32(len(b.file.stringPool.Strings))
+		b.file.stringPool.Strings = append(b.file.stringPool.Strings, s)
+		b.strings[s] = ref
+	}
+	return ResStringPoolRef(ref)
+}
+
+// keyRef returns the reference to the key in the key string pool of the package.
+func (b *protoTableBuilder) keyRef(p *TablePackage, key string) ResStringPoolRef {
+	keys := b.keys[p]
+	ref, ok := keys[key]
+	if !ok {
+		ref = uint32(len(p.KeyStrings.Strings))
+		p.KeyStrings.Strings = append(p.KeyStrings.Strings, key)
+		p.KeyStrings.Header.StringCount++
+		keys[key] = ref
+	}
+	return ResStringPoolRef(ref)
+}
+
+// tablePackage returns the package of id, creating a new one if needed.
+func (b *protoTableBuilder) tablePackage(id uint32, name string) *TablePackage {
+	if p, ok := b.file.tablePackages[id]; ok {
+		return p
+	}
+	p := &TablePackage{
+		TypeStrings: new(ResStringPool),
+		KeyStrings:  new(ResStringPool),
+	}
+	p.Header.ID = id
+	copy(p.Header.Name[:len(p.Header.Name)-1], utf16.Encode([]rune(name)))
+	b.file.tablePackages[id] = p
+	b.keys[p] = make(map[string]uint32)
+	return p
+}
+
+func (b *protoTableBuilder) readPackage(r *protoReader) error {
+	var id uint32
+	var name string
+	var types []*protoReader
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		switch field {
+		case 1: // package_id
+			id, err = r.readID(wireType)
+		case 2: // package_name
+			name, err = r.readString(wireType)
+		case 3: // type
+			var m *protoReader
+			if m, err = r.readMessage(wireType); err == nil {
+				types = append(types, m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	p := b.tablePackage(id, name)
+	for _, m := range types {
+		if err := b.readType(p, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *protoTableBuilder) readType(p *TablePackage, r *protoReader) error {
+	var id uint32
+	var name string
+	var entries []*protoReader
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		switch field {
+		case 1: // type_id
+			id, err = r.readID(wireType)
+		case 2: // name
+			name, err = r.readString(wireType)
+		case 3: // entry
+			var m *protoReader
+			if m, err = r.readMessage(wireType); err == nil {
+				entries = append(entries, m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if id == 0 || id > 0xff {
+		return fmt.Errorf("androidbinary: invalid type id: %d", id)
+	}
+
+	// the type strings are indexed by the type id minus one.
+	for len(p.TypeStrings.Strings) < int(id) {
+		p.TypeStrings.Strings = append(p.TypeStrings.Strings, "")
+	}
+	p.TypeStrings.Strings[id-1] = name
+	p.TypeStrings.Header.StringCount = uint32(len(p.TypeStrings.Strings))
+
+	for _, m := range entries {
+		if err := b.readEntry(p, uint8(id), m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *protoTableBuilder) readEntry(p *TablePackage, typeID uint8, r *protoReader) error {
+	var id uint32
+	var hasID bool
+	var name string
+	var values []*protoReader
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		switch field {
+		case 1: // entry_id
+			id, err = r.readID(wireType)
+			hasID = true
+		case 2: // name
+			name, err = r.readString(wireType)
+		case 6: // config_value
+			var m *protoReader
+			if m, err = r.readMessage(wireType); err == nil {
+				values = append(values, m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !hasID {
+		// the entry can't be referenced without id.
+		return nil
+	}
+	if id > 0xffff {
+		return fmt.Errorf("androidbinary: invalid entry id: %d", id)
+	}
+
+	key := &ResTableEntry{
+		Size: 8,
+		Key:  b.keyRef(p, name),
+	}
+	complexKey := &ResTableEntry{
+		Size:  16,
+		Flags: EntryFlagComplex,
+		Key:   key.Key,
+	}
+	for _, m := range values {
+		config, entry, err := b.readConfigValue(m)
+		if err != nil {
+			return err
+		}
+		entry.Key = key
+		if entry.Map != nil {
+			entry.Key = complexKey
+		}
+		t := findProtoTableType(p, typeID, config)
+		for len(t.Entries) <= int(id) {
+			t.Entries = append(t.Entries, TableEntry{})
+		}
+		t.Entries[id] = entry
+		t.Header.EntryCount = uint32(len(t.Entries))
+	}
+	return nil
+}
+
+// findProtoTableType returns the table type of the id and the config, creating a new one if needed.
+func findProtoTableType(p *TablePackage, id uint8, config ResTableConfig) *TableType {
+	for _, t := range p.TableTypes {
+		if t.Header.ID == id && t.Header.Config == config {
+			return t
+		}
+	}
+	t := &TableType{
+		Header: &ResTableType{
+			ID:     id,
+			Config: config,
+		},
+	}
+	p.TableTypes = append(p.TableTypes, t)
+	return t
+}
+
+// readConfigValue reads a ConfigValue message.
+// The value of the entry is nil if it is a compound value.
+func (b *protoTableBuilder) readConfigValue(r *protoReader) (ResTableConfig, TableEntry, error) {
+	var config ResTableConfig
+	var entry TableEntry
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return ResTableConfig{}, TableEntry{}, err
+		}
+		if !ok {
+			break
+		}
+		var m *protoReader
+		switch field {
+		case 1: // config
+			if m, err = r.readMessage(wireType); err == nil {
+				config, err = readProtoConfig(m)
+			}
+		case 2: // value
+			if m, err = r.readMessage(wireType); err == nil {
+				entry, err = b.readValue(m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return ResTableConfig{}, TableEntry{}, err
+		}
+	}
+	return config, entry, nil
+}
+
+// readValue reads a Value message into the value or the map of the entry.
+func (b *protoTableBuilder) readValue(r *protoReader) (TableEntry, error) {
+	var entry TableEntry
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return TableEntry{}, err
+		}
+		if !ok {
+			return entry, nil
+		}
+		var m *protoReader
+		switch field {
+		case 4: // item
+			if m, err = r.readMessage(wireType); err == nil {
+				var value ResValue
+				if value, ok, err = b.readItem(m); err == nil && ok {
+					entry = TableEntry{Value: &value}
+				}
+			}
+		case 5: // compound_value
+			if m, err = r.readMessage(wireType); err == nil {
+				entry, err = b.readCompoundValue(m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return TableEntry{}, err
+		}
+	}
+}
+
+// readItem reads an Item message, and adds the string value to the global string pool.
+// It returns false if the item is not supported.
+func (b *protoTableBuilder) readItem(r *protoReader) (ResValue, bool, error) {
+	item, ok, err := readProtoItem(r)
+	if err != nil || !ok {
+		return ResValue{}, false, err
+	}
+	v := item.value
+	if v.DataType == TypeString {
+		v.Data = uint32(b.stringRef(item.str))
+	}
+	return v, true, nil
+}
+
+// readCompoundValue reads a CompoundValue message into the parent and the map of the entry.
+// The map is nil if the compound value is not supported.
+func (b *protoTableBuilder) readCompoundValue(r *protoReader) (TableEntry, error) {
+	var entry TableEntry
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return TableEntry{}, err
+		}
+		if !ok {
+			return entry, nil
+		}
+		var m *protoReader
+		switch field {
+		case 2: // style
+			if m, err = r.readMessage(wireType); err == nil {
+				entry, err = b.readStyle(m)
+			}
+		case 4: // array
+			if m, err = r.readMessage(wireType); err == nil {
+				entry = TableEntry{}
+				entry.Map, err = b.readArray(m)
+			}
+		case 5: // plural
+			if m, err = r.readMessage(wireType); err == nil {
+				entry = TableEntry{}
+				entry.Map, err = b.readPlural(m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return TableEntry{}, err
+		}
+	}
+}
+
+// readStyle reads a Style message.
+// The items are keyed by the attributes, same as the styles of resources.arsc.
+func (b *protoTableBuilder) readStyle(r *protoReader) (TableEntry, error) {
+	entry := TableEntry{Map: []ResTableMap{}}
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return TableEntry{}, err
+		}
+		if !ok {
+			return entry, nil
+		}
+		var m *protoReader
+		switch field {
+		case 1: // parent
+			if m, err = r.readMessage(wireType); err == nil {
+				var parent ResValue
+				parent, err = readProtoReference(m)
+				entry.Parent = ResID(parent.Data)
+			}
+		case 3: // entry
+			if m, err = r.readMessage(wireType); err == nil {
+				var item ResTableMap
+				if item, ok, err = b.readStyleEntry(m); err == nil && ok {
+					entry.Map = append(entry.Map, item)
+				}
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return TableEntry{}, err
+		}
+	}
+}
+
+// readStyleEntry reads a Style.Entry message.
+// It returns false if the item is not supported.
+func (b *protoTableBuilder) readStyleEntry(r *protoReader) (ResTableMap, bool, error) {
+	var item ResTableMap
+	var found bool
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return ResTableMap{}, false, err
+		}
+		if !ok {
+			return item, found, nil
+		}
+		var m *protoReader
+		switch field {
+		case 3: // key
+			if m, err = r.readMessage(wireType); err == nil {
+				var key ResValue
+				key, err = readProtoReference(m)
+				item.Name = ResID(key.Data)
+			}
+		case 4: // item
+			if m, err = r.readMessage(wireType); err == nil {
+				item.Value, found, err = b.readItem(m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return ResTableMap{}, false, err
+		}
+	}
+}
+
+// protoArrayIndexBase is the name of the first item of arrays, which is Res_MAKEARRAY(0) of aapt.
+const protoArrayIndexBase ResID = 0x02000000
+
+// readArray reads an Array message.
+// The items are keyed by their indexes, same as the arrays of resources.arsc.
+func (b *protoTableBuilder) readArray(r *protoReader) ([]ResTableMap, error) {
+	items := []ResTableMap{}
+	index := 0
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return items, nil
+		}
+		switch field {
+		case 1: // element
+			var m *protoReader
+			if m, err = r.readMessage(wireType); err == nil {
+				var value ResValue
+				if value, ok, err = b.readArrayElement(m); err == nil && ok {
+					items = append(items, ResTableMap{Name: protoArrayIndexBase + ResID(index), Value: value})
+				}
+				index++
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readArrayElement reads an Array.Element message.
+// It returns false if the item is not supported.
+func (b *protoTableBuilder) readArrayElement(r *protoReader) (ResValue, bool, error) {
+	var value ResValue
+	var found bool
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return ResValue{}, false, err
+		}
+		if !ok {
+			return value, found, nil
+		}
+		if field == 3 { // item
+			var m *protoReader
+			if m, err = r.readMessage(wireType); err == nil {
+				value, found, err = b.readItem(m)
+			}
+		} else {
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return ResValue{}, false, err
+		}
+	}
+}
+
+// protoPluralArities is the attributes of the quantities, indexed by the arities of Plural messages.
+var protoPluralArities = []ResID{
+	AttrPluralZero,
+	AttrPluralOne,
+	AttrPluralTwo,
+	AttrPluralFew,
+	AttrPluralMany,
+	AttrPluralOther,
+}
+
+// readPlural reads a Plural message.
+// The items are keyed by the attributes of the quantities, same as the plurals of resources.arsc.
+func (b *protoTableBuilder) readPlural(r *protoReader) ([]ResTableMap, error) {
+	items := []ResTableMap{}
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return items, nil
+		}
+		switch field {
+		case 1: // entry
+			var m *protoReader
+			if m, err = r.readMessage(wireType); err == nil {
+				var item ResTableMap
+				if item, ok, err = b.readPluralEntry(m); err == nil && ok {
+					items = append(items, item)
+				}
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readPluralEntry reads a Plural.Entry message.
+// It returns false if the item is not supported.
+func (b *protoTableBuilder) readPluralEntry(r *protoReader) (ResTableMap, bool, error) {
+	// the arity is zero if it is omitted.
+	item := ResTableMap{Name: AttrPluralZero}
+	var found bool
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return ResTableMap{}, false, err
+		}
+		if !ok {
+			return item, found, nil
+		}
+		var m *protoReader
+		switch field {
+		case 3: // arity
+			var arity uint32
+			if arity, err = r.readUint(wireType); err == nil {
+				if int64(arity) >= int64(len(protoPluralArities)) {
+					return ResTableMap{}, false, fmt.Errorf("androidbinary: invalid plural arity: %d", arity)
+				}
+				item.Name = protoPluralArities[arity]
+			}
+		case 4: // item
+			if m, err = r.readMessage(wireType); err == nil {
+				item.Value, found, err = b.readItem(m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return ResTableMap{}, false, err
+		}
+	}
+}
+
+// protoItem is a value of an Item message.
+type protoItem struct {
+	value ResValue
+
+	// str is the value if value.DataType is TypeString.
+	str string
+}
+
+// readProtoItem reads an Item message.
+// It returns false if the item is not supported.
+func readProtoItem(r *protoReader) (protoItem, bool, error) {
+	var item protoItem
+	var found bool
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return protoItem{}, false, err
+		}
+		if !ok {
+			break
+		}
+		var m *protoReader
+		switch field {
+		case 1: // ref
+			if m, err = r.readMessage(wireType); err == nil {
+				item.value, err = readProtoReference(m)
+				found = true
+			}
+		case 2, 3, 4, 5: // str, raw_str, styled_str and file
+			// their first fields are the string values, and the file reference is the path of the file.
+			if m, err = r.readMessage(wireType); err == nil {
+				item.str, err = readProtoFirstString(m)
+				item.value = ResValue{Size: 8, DataType: TypeString}
+				found = true
+			}
+		case 6: // id
+			item.value = ResValue{Size: 8, DataType: TypeIntBoolean}
+			found = true
+			err = r.skip(wireType)
+		case 7: // prim
+			if m, err = r.readMessage(wireType); err == nil {
+				item.value, found, err = readProtoPrimitive(m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return protoItem{}, false, err
+		}
+	}
+	return item, found, nil
+}
+
+// readProtoReference reads a Reference message.
+func readProtoReference(r *protoReader) (ResValue, error) {
+	value := ResValue{Size: 8, DataType: TypeReference}
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return ResValue{}, err
+		}
+		if !ok {
+			return value, nil
+		}
+		var v uint32
+		switch field {
+		case 1: // type
+			if v, err = r.readUint(wireType); err == nil && v == 1 {
+				value.DataType = TypeAttribute
+			}
+		case 2: // id
+			value.Data, err = r.readUint(wireType)
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return ResValue{}, err
+		}
+	}
+}
+
+// readProtoFirstString reads the first field of the message as a string.
+func readProtoFirstString(r *protoReader) (string, error) {
+	var s string
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return s, nil
+		}
+		if field == 1 {
+			s, err = r.readString(wireType)
+		} else {
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// protoPrimitiveTypes is the data types of the fields of Primitive messages.
+var protoPrimitiveTypes = map[int]DataType{
+	3:  TypeFloat,
+	6:  TypeIntDec,
+	7:  TypeIntHex,
+	8:  TypeIntBoolean,
+	9:  TypeIntColorARGB8,
+	10: TypeIntColorRGB8,
+	11: TypeIntColorARGB4,
+	12: TypeIntColorRGB4,
+	13: TypeDemention,
+	14: TypeFraction,
+}
+
+// readProtoPrimitive reads a Primitive message.
+func readProtoPrimitive(r *protoReader) (ResValue, bool, error) {
+	var value ResValue
+	var found bool
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return ResValue{}, false, err
+		}
+		if !ok {
+			return value, found, nil
+		}
+		switch field {
+		case 1: // null_value
+			value = ResValue{Size: 8, DataType: TypeNull}
+			found = true
+			err = r.skip(wireType)
+		case 2: // empty_value
+			value = ResValue{Size: 8, DataType: TypeNull, Data: 1}
+			found = true
+			err = r.skip(wireType)
+		default:
+			typ, known := protoPrimitiveTypes[field]
+			if !known {
+				err = r.skip(wireType)
+				break
+			}
+			var data uint32
+			if wireType == protoFixed32 {
+				data, err = r.fixed32()
+			} else {
+				data, err = r.readUint(wireType)
+			}
+			if typ == TypeIntBoolean && data != 0 {
+				data = 0xFFFFFFFF
+			}
+			value = ResValue{Size: 8, DataType: typ, Data: data}
+			found = true
+		}
+		if err != nil {
+			return ResValue{}, false, err
+		}
+	}
+}
+
+// readProtoConfig reads a Configuration message.
+// The qualifiers that ResTableConfig doesn't have, such as round screens and HDR, are ignored.
+func readProtoConfig(r *protoReader) (ResTableConfig, error) {
+	var c ResTableConfig
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return ResTableConfig{}, err
+		}
+		if !ok {
+			return c, nil
+		}
+		if field == 3 { // locale
+			var locale string
+			if locale, err = r.readString(wireType); err != nil {
+				return ResTableConfig{}, err
+			}
+			setProtoLocale(&c, locale)
+			continue
+		}
+		if wireType != protoVarint {
+			if err := r.skip(wireType); err != nil {
+				return ResTableConfig{}, err
+			}
+			continue
+		}
+		v, err := r.readUint(wireType)
+		if err != nil {
+			return ResTableConfig{}, err
+		}
+		switch field {
+		case 1: // mcc
+			c.Mcc = uint16(v)
+		case 2: // mnc
+			c.Mnc = uint16(v)
+		case 4: // layout_direction
+			switch v {
+			case 1:
+				c.ScreenLayout |= LayoutDirLTR
+			case 2:
+				c.ScreenLayout |= LayoutDirRTL
+			}
+		case 5: // screen_width
+			c.ScreenWidth = uint16(v)
+		case 6: // screen_height
+			c.ScreenHeight = uint16(v)
+		case 7: // screen_width_dp
+			c.ScreenWidthDp = uint16(v)
+		case 8: // screen_height_dp
+			c.ScreenHeightDp = uint16(v)
+		case 9: // smallest_screen_width_dp
+			c.SmallestScreenWidthDp = uint16(v)
+		case 10: // screen_layout_size
+			c.ScreenLayout |= ScreenLayout(v) & MaskScreenSize
+		case 11: // screen_layout_long
+			switch v {
+			case 1:
+				c.ScreenLayout |= ScreenLongYes
+			case 2:
+				c.ScreenLayout |= ScreenLongNo
+			}
+		case 15: // orientation
+			c.Orientation = uint8(v)
+		case 16: // ui_mode_type
+			c.UIMode |= UIMode(v) & MaskUIModeType
+		case 17: // ui_mode_night
+			switch v {
+			case 1:
+				c.UIMode |= UIModeNightYes
+			case 2:
+				c.UIMode |= UIModeNightNo
+			}
+		case 18: // density
+			c.Density = uint16(v)
+		case 19: // touchscreen
+			c.Touchscreen = uint8(v)
+		case 20: // keys_hidden
+			c.InputFlags |= InputFlags(v) & MaskKeysHidden
+		case 21: // keyboard
+			c.Keyboard = uint8(v)
+		case 22: // nav_hidden
+			c.InputFlags |= InputFlags(v<<2) & MaskNavHidden
+		case 23: // navigation
+			c.Navigation = uint8(v)
+		case 24: // sdk_version
+			c.SDKVersion = uint16(v)
+		}
+	}
+}
+
+// setProtoLocale sets the BCP-47 language tag, such as "en-US" and "b+sr+Latn", to the config.
+// The script and the variant are ignored.
+func setProtoLocale(c *ResTableConfig, locale string) {
+	locale = strings.TrimPrefix(locale, "b+")
+	subtags := strings.FieldsFunc(locale, func(r rune) bool {
+		return r == '-' || r == '+' || r == '_'
+	})
+	if len(subtags) == 0 {
+		return
+	}
+	c.Language = packLocaleCode(strings.ToLower(subtags[0]), 'a')
+	for _, subtag := range subtags[1:] {
+		// the region is two letters or three digits.
+		if len(subtag) == 2 || (len(subtag) == 3 && subtag[0] >= '0' && subtag[0] <= '9') {
+			c.Country = packLocaleCode(strings.ToUpper(subtag), '0')
+			return
+		}
+	}
+}
+
+// packLocaleCode packs the language or the region code into two bytes, in the same way as ResTable_config.
+func packLocaleCode(code string, base byte) [2]uint8 {
+	switch len(code) {
+	case 2:
+		return [2]uint8{code[0], code[1]}
+	case 3:
+		first, second, third := code[0]-base, code[1]-base, code[2]-base
+		return [2]uint8{0x80 | (third << 2) | (second >> 3), (second << 5) | first}
+	}
+	return [2]uint8{}
+}
@@ -0,0 +1,251 @@
+package androidbinary
+
+import (
+	"testing"
+)
+
+func testProtoString(s string) testProto {
+	item := testProto(nil).message(2, testProto(nil).string(1, s))
+	return testProto(nil).message(4, item)
+}
+
+func testProtoPrimitive(field int, v uint64) testProto {
+	item := testProto(nil).message(7, testProto(nil).varint(field, v))
+	return testProto(nil).message(4, item)
+}
+
+func testProtoEntry(id int, name string, values ...testProto) testProto {
+	m := testProto(nil).
+		message(1, testProto(nil).varint(1, uint64(id))).
+		string(2, name)
+	for i := 0; i < len(values); i += 2 {
+		m = m.message(6, testProto(nil).message(1, values[i]).message(2, values[i+1]))
+	}
+	return m
+}
+
+func testProtoTable() testProto {
+	ja := testProto(nil).string(3, "ja")
+	v21 := testProto(nil).varint(24, 21)
+	strings := testProto(nil).
+		message(1, testProto(nil).varint(1, 1)).
+		string(2, "string").
+		message(3, testProtoEntry(0, "app_name", nil, testProtoString("Example"), ja, testProtoString("例"))).
+		message(3, testProtoEntry(1, "icon", nil, testProto(nil).message(4, testProto(nil).message(5, testProto(nil).string(1, "res/drawable/icon.png")))))
+	bools := testProto(nil).
+		message(1, testProto(nil).varint(1, 2)).
+		string(2, "bool").
+		message(3, testProtoEntry(0, "enabled", nil, testProtoPrimitive(8, 0), v21, testProtoPrimitive(8, 1)))
+	styles := testProto(nil).
+		message(1, testProto(nil).varint(1, 3)).
+		string(2, "style").
+		message(3, testProtoEntry(0, "AppTheme", nil, testProto(nil).message(5, testProto(nil).message(1, nil))))
+	pkg := testProto(nil).
+		message(1, testProto(nil).varint(1, 0x7f)).
+		string(2, "com.example").
+		message(3, strings).
+		message(3, bools).
+		message(3, styles)
+	return testProto(nil).
+		message(1, testProto(nil).bytes(1, []byte{0x00})). // source_pool, which is ignored
+		message(2, pkg)
+}
+
+func TestNewTableFileFromProto(t *testing.T) {
+	table, err := NewTableFileFromProto(testProtoTable())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		id     ResID
+		config *ResTableConfig
+		want   interface{}
+	}{
+		{0x7f010000, &ResTableConfig{}, "Example"},
+		{0x7f010000, &ResTableConfig{Language: [2]uint8{'j', 'a'}}, "例"},
+		{0x7f010001, nil, "res/drawable/icon.png"},
+		{0x7f020000, &ResTableConfig{SDKVersion: 19}, false},
+		{0x7f020000, &ResTableConfig{SDKVersion: 30}, true},
+	}
+	for _, c := range cases {
+		got, err := table.GetResource(c.id, c.config)
+		if err != nil {
+			t.Errorf("%v: %v", c.id, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%v: want %v, got %v", c.id, c.want, got)
+		}
+	}
+
+	// attributes are not supported
+	if _, err := table.GetResource(0x7f030000, nil); err == nil {
+		t.Error("want error, got nil")
+	}
+
+	p := table.findPackage(0x7f)
+	if got := p.TypeStrings.Strings; len(got) != 3 || got[0] != "string" || got[2] != "style" {
+		t.Errorf("unexpected type strings: %v", got)
+	}
+	if got := p.KeyStrings.GetString(p.TableTypes[0].Entries[0].Key.Key); got != "app_name" {
+		t.Errorf("want app_name, got %s", got)
+	}
+}
+
+func TestNewTableFileFromProtoMerge(t *testing.T) {
+	feature := testProto(nil).message(2, testProto(nil).
+		message(1, testProto(nil).varint(1, 0x7e)).
+		string(2, "com.example.feature").
+		message(3, testProto(nil).
+			message(1, testProto(nil).varint(1, 1)).
+			string(2, "string").
+			message(3, testProtoEntry(0, "title", nil, testProtoString("Feature")))))
+	table, err := NewTableFileFromProto(append(testProtoTable(), feature...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := table.GetResource(0x7f010000, &ResTableConfig{}); err != nil || got != "Example" {
+		t.Errorf("want Example, got %v, %v", got, err)
+	}
+	if got, err := table.GetResource(0x7e010000, nil); err != nil || got != "Feature" {
+		t.Errorf("want Feature, got %v, %v", got, err)
+	}
+}
+
+func TestReadProtoConfig(t *testing.T) {
+	cases := []struct {
+		config testProto
+		want   ResTableConfig
+	}{
+		{
+			config: testProto(nil).string(3, "en-US").varint(18, 480).varint(24, 21),
+			want: ResTableConfig{
+				Language:   [2]uint8{'e', 'n'},
+				Country:    [2]uint8{'U', 'S'},
+				Density:    480,
+				SDKVersion: 21,
+			},
+		},
+		{
+			config: testProto(nil).string(3, "b+sr+Latn").varint(4, 2).varint(17, 1),
+			want: ResTableConfig{
+				Language:     [2]uint8{'s', 'r'},
+				ScreenLayout: LayoutDirRTL,
+				UIMode:       UIModeNightYes,
+			},
+		},
+		{
+			// three-letter codes are packed
+			config: testProto(nil).string(3, "fil-419"),
+			want: ResTableConfig{
+				Language: [2]uint8{0xad, 0x05},
+				Country:  [2]uint8{0xa4, 0x24},
+			},
+		},
+		{
+			config: testProto(nil).varint(15, 2).varint(20, 2).varint(22, 1).varint(9, 600),
+			want: ResTableConfig{
+				Orientation:           2,
+				InputFlags:            KeysHiddenYes | NavHiddenNo,
+				SmallestScreenWidthDp: 600,
+			},
+		},
+	}
+	for _, c := range cases {
+		got, err := readProtoConfig(&protoReader{data: c.config})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("want %+v, got %+v", c.want, got)
+		}
+	}
+}
+
+func TestNewTableFileFromProtoCompoundValues(t *testing.T) {
+	str := func(s string) testProto {
+		return testProto(nil).message(2, testProto(nil).string(1, s))
+	}
+	ref := func(id uint64) testProto {
+		return testProto(nil).varint(2, id)
+	}
+	compound := func(field int, v testProto) testProto {
+		return testProto(nil).message(5, testProto(nil).message(field, v))
+	}
+	style := testProto(nil).
+		message(1, ref(0x01030005)).
+		message(3, testProto(nil).message(3, ref(0x01010000)).message(4, str("dark")))
+	plural := testProto(nil).
+		message(1, testProto(nil).varint(3, 1).message(4, str("%d item"))).
+		message(1, testProto(nil).varint(3, 5).message(4, str("%d items")))
+	array := testProto(nil).
+		message(1, testProto(nil).message(3, str("first"))).
+		message(1, testProto(nil).message(3, str("second")))
+	pkg := testProto(nil).
+		message(1, testProto(nil).varint(1, 0x7f)).
+		string(2, "com.example").
+		message(3, testProto(nil).
+			message(1, testProto(nil).varint(1, 1)).
+			string(2, "style").
+			message(3, testProtoEntry(0, "AppTheme", nil, compound(2, style)))).
+		message(3, testProto(nil).
+			message(1, testProto(nil).varint(1, 2)).
+			string(2, "plurals").
+			message(3, testProtoEntry(0, "items", nil, compound(5, plural)))).
+		message(3, testProto(nil).
+			message(1, testProto(nil).varint(1, 3)).
+			string(2, "array").
+			message(3, testProtoEntry(0, "names", nil, compound(4, array))))
+	table, err := NewTableFileFromProto(testProto(nil).message(2, pkg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := table.findPackage(0x7f)
+
+	// style
+	e := p.findEntry(1, 0, nil)
+	if e.Key == nil || e.Key.Flags&EntryFlagComplex == 0 || e.Value != nil {
+		t.Fatalf("want a complex entry, got %#v", e)
+	}
+	if e.Parent != 0x01030005 {
+		t.Errorf("want parent 0x01030005, got 0x%08X", uint32(e.Parent))
+	}
+	if len(e.Map) != 1 || e.Map[0].Name != 0x01010000 || table.GetString(ResStringPoolRef(e.Map[0].Value.Data)) != "dark" {
+		t.Errorf("unexpected style items: %#v", e.Map)
+	}
+
+	// plurals
+	cases := []struct {
+		n    int
+		want string
+	}{
+		{1, "%d item"},
+		{2, "%d items"},
+	}
+	for _, c := range cases {
+		got, err := table.GetQuantityString(0x7f020000, &ResTableConfig{Language: [2]uint8{'e', 'n'}}, c.n)
+		if err != nil {
+			t.Errorf("%d: %v", c.n, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%d: want %q, got %q", c.n, c.want, got)
+		}
+	}
+
+	// array
+	e = p.findEntry(3, 0, nil)
+	if len(e.Map) != 2 {
+		t.Fatalf("want 2 items, got %#v", e.Map)
+	}
+	for i, want := range []string{"first", "second"} {
+		m := e.Map[i]
+		if m.Name != 0x02000000+ResID(i) {
+			t.Errorf("%d: want name 0x%08X, got 0x%08X", i, 0x02000000+i, uint32(m.Name))
+		}
+		if got := table.GetString(ResStringPoolRef(m.Value.Data)); got != want {
+			t.Errorf("%d: want %q, got %q", i, want, got)
+		}
+	}
+}
@@ -1,7 +1,13 @@
 package androidbinary
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -53,6 +59,16 @@ func TestGetResourceNil(t *testing.T) {
 	}
 }
 
+func TestGetStringOutOfRange(t *testing.T) {
+	tableFile := loadTestData()
+	if s := tableFile.GetString(ResStringPoolRef(0x7FFFFFFF)); s != "" {
+		t.Errorf("want empty, got %q", s)
+	}
+	if s := new(TableFile).GetString(0); s != "" {
+		t.Errorf("want empty, got %q", s)
+	}
+}
+
 func TestGetResourceDefault(t *testing.T) {
 	tableFile := loadTestData()
 	val, _ := tableFile.GetResource(ResID(0x7f040000), &ResTableConfig{})
@@ -458,3 +474,230 @@ func TestIsBetterThan(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeTableFiles(t *testing.T) {
+	base := loadTestData()
+	split, err := NewTableFileFromProto(testProto(nil).message(2, testProto(nil).
+		message(1, testProto(nil).varint(1, 0x7f)).
+		message(3, testProto(nil).
+			message(1, testProto(nil).varint(1, 4)).
+			string(2, "string").
+			message(3, testProtoEntry(0, "app_name", testProto(nil).string(3, "fr"), testProtoString("Mesure de feux d'artifice"))))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := MergeTableFiles(base, nil, split)
+
+	cases := []struct {
+		language string
+		want     string
+	}{
+		{"", "FireworksMeasure"},
+		{"ja", "花火距離計算"},
+		{"fr", "Mesure de feux d'artifice"},
+	}
+	for _, c := range cases {
+		config := &ResTableConfig{}
+		copy(config.Language[:], c.language)
+		val, err := merged.GetResource(ResID(0x7f040000), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val != c.want {
+			t.Errorf("%s: want %q, got %v", c.language, c.want, val)
+		}
+	}
+
+	// the original tables are not modified.
+	if val, _ := base.GetResource(ResID(0x7f040000), &ResTableConfig{Language: [2]uint8{'f', 'r'}}); val != "FireworksMeasure" {
+		t.Errorf(`got %v want "FireworksMeasure"`, val)
+	}
+}
+
+func TestMergeTableFilesSpans(t *testing.T) {
+	// the spans of the strings are kept in the tables after the first one.
+	newTable := func(strs []string, spans [][]ResStringPoolSpan) *TableFile {
+		return &TableFile{stringPool: &ResStringPool{Strings: strs, Spans: spans}}
+	}
+	bold := []ResStringPoolSpan{{Name: 2, FirstChar: 0, LastChar: 4}}
+	merged := MergeTableFiles(
+		newTable([]string{"plain"}, nil),
+		newTable([]string{"Hello", "World", "b"}, [][]ResStringPoolSpan{bold, bold}),
+		newTable([]string{"Bonjour", "i"}, [][]ResStringPoolSpan{{{Name: 1, FirstChar: 0, LastChar: 2}}}),
+	)
+
+	cases := []struct {
+		ref  ResStringPoolRef
+		want string
+	}{
+		{0, "plain"},
+		{1, "<b>Hello</b>"},
+		{2, "<b>World</b>"},
+		{4, "<i>Bon</i>jour"},
+	}
+	for _, c := range cases {
+		if got := merged.GetStyledString(c.ref); got != c.want {
+			t.Errorf("%d: want %q, got %q", c.ref, c.want, got)
+		}
+	}
+	if len(merged.stringPool.Styles) != len(merged.stringPool.Spans) {
+		t.Errorf("want %d styles, got %d", len(merged.stringPool.Spans), len(merged.stringPool.Styles))
+	}
+	if spans := merged.stringPool.Spans; &spans[1][0] != &spans[2][0] {
+		t.Error("want the spans shared")
+	}
+}
+
+// encodeTestComplexType encodes a type chunk of the entries at entryIndexes,
+// and entries is the data of the entries.
+func encodeTestComplexType(entryIndexes []uint32, entries []byte) *io.SectionReader {
+	headerSize := binary.Size(ResTableType{})
+	entriesStart := headerSize + 4*len(entryIndexes)
+	size := entriesStart + len(entries)
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, ResTableType{
+		Header:       ResChunkHeader{Type: ResTableTypeType, HeaderSize: uint16(headerSize), Size: uint32(size)},
+		ID:           1,
+		EntryCount:   uint32(len(entryIndexes)),
+		EntriesStart: uint32(entriesStart),
+	})
+	binary.Write(buf, binary.LittleEndian, entryIndexes)
+	buf.Write(entries)
+	return io.NewSectionReader(bytes.NewReader(buf.Bytes()), 0, int64(buf.Len()))
+}
+
+// encodeTestComplexEntry encodes the header of a complex entry of count items.
+func encodeTestComplexEntry(count uint32) []byte {
+	buf := make([]byte, 16)
+	binary.LittleEndian.PutUint16(buf[0:], 16)
+	binary.LittleEndian.PutUint16(buf[2:], EntryFlagComplex)
+	binary.LittleEndian.PutUint32(buf[12:], count)
+	return buf
+}
+
+func TestReadTableTypeSharedEntries(t *testing.T) {
+	t.Run("same offset", func(t *testing.T) {
+		// all the entries point at the same complex entry, which is read only once.
+		entries := append(encodeTestComplexEntry(100), make([]byte, 12*100)...)
+		sr := encodeTestComplexType(make([]uint32, 1000), entries)
+		chunkHeader := ResChunkHeader{Type: ResTableTypeType, HeaderSize: uint16(binary.Size(ResTableType{})), Size: uint32(sr.Size())}
+		tableType, err := readTableType(&chunkHeader, sr, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tableType.Entries) != 1000 || len(tableType.Entries[999].Map) != 100 {
+			t.Fatalf("unexpected entries: %d", len(tableType.Entries))
+		}
+		if &tableType.Entries[0].Map[0] != &tableType.Entries[999].Map[0] {
+			t.Error("want the maps shared")
+		}
+	})
+
+	t.Run("overlapped", func(t *testing.T) {
+		// the complex entries overlap the items of the other entries.
+		entries := make([]byte, 16*100+12*100)
+		indexes := make([]uint32, 100)
+		for i := range indexes {
+			indexes[i] = uint32(16 * i)
+			copy(entries[16*i:], encodeTestComplexEntry(100))
+		}
+		sr := encodeTestComplexType(indexes, entries)
+		chunkHeader := ResChunkHeader{Type: ResTableTypeType, HeaderSize: uint16(binary.Size(ResTableType{})), Size: uint32(sr.Size())}
+		_, err := readTableType(&chunkHeader, sr, nil)
+		var countErr *CountError
+		if !errors.As(err, &countErr) || countErr.Field != "EntryCount" {
+			t.Errorf("want EntryCount error, got %v", err)
+		}
+	})
+}
+
+func loadLazyTestData(t testing.TB) *TableFile {
+	t.Helper()
+	data, err := ioutil.ReadFile("testdata/resources.arsc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableFile, err := NewTableFileLazy(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tableFile
+}
+
+func TestNewTableFileLazy(t *testing.T) {
+	eager := loadTestData()
+	lazy := loadLazyTestData(t)
+
+	configs := []*ResTableConfig{
+		nil,
+		{},
+		{Language: [2]uint8{'j', 'a'}},
+		{Language: [2]uint8{'e', 'n'}},
+		{Density: 480},
+	}
+	var count int
+	for id, p := range eager.tablePackages {
+		for _, typ := range p.TableTypes {
+			for i := range typ.Entries {
+				resID := ResID(id<<24 | uint32(typ.Header.ID)<<16 | uint32(i))
+				for _, config := range configs {
+					want, wantErr := eager.GetResource(resID, config)
+					got, gotErr := lazy.GetResource(resID, config)
+					if !reflect.DeepEqual(got, want) || (gotErr == nil) != (wantErr == nil) {
+						t.Errorf("%v, %+v: want %v, %v, got %v, %v", resID, config, want, wantErr, got, gotErr)
+					}
+					count++
+				}
+			}
+		}
+	}
+	if count == 0 {
+		t.Fatal("no resources found")
+	}
+
+	pool := lazy.stringPool
+	if pool.Strings != nil {
+		t.Errorf("want the strings not decoded, got %d strings", len(pool.Strings))
+	}
+	if !reflect.DeepEqual(pool.allStrings(), eager.stringPool.Strings) {
+		t.Errorf("want %v, got %v", eager.stringPool.Strings, pool.allStrings())
+	}
+	ref := ResStringPoolRef(len(eager.stringPool.Strings))
+	if pool.HasString(ref) {
+		t.Errorf("want the pool not to contain %d", ref)
+	}
+	if s := pool.GetString(ref); s != "" {
+		t.Errorf("want empty, got %q", s)
+	}
+
+	// the lazy tables can be merged.
+	merged := MergeTableFiles(lazy, eager)
+	if val, _ := merged.GetResource(ResID(0x7f040000), &ResTableConfig{}); val != "FireworksMeasure" {
+		t.Errorf(`got %v want "FireworksMeasure"`, val)
+	}
+}
+
+func BenchmarkGetResource(b *testing.B) {
+	data, err := ioutil.ReadFile("testdata/resources.arsc")
+	if err != nil {
+		b.Fatal(err)
+	}
+	config := &ResTableConfig{Language: [2]uint8{'j', 'a'}}
+	bench := func(newTableFile func(io.ReaderAt) (*TableFile, error)) func(b *testing.B) {
+		return func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				// open the table and look up a single resource.
+				tableFile, err := newTableFile(bytes.NewReader(data))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := tableFile.GetResource(ResID(0x7f040000), config); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+	b.Run("eager", bench(NewTableFile))
+	b.Run("lazy", bench(NewTableFileLazy))
+}
@@ -0,0 +1,155 @@
+package androidbinary
+
+import (
+	"fmt"
+)
+
+// maxResolveDepth is the limit of the references and the attributes followed to resolve a value,
+// which is same as Resources.Theme.resolveAttribute.
+const maxResolveDepth = 20
+
+// Theme is the attribute values of the styles applied to it, like Resources.Theme.
+// The parents of the styles that are not in the table, such as the styles of the Android framework,
+// are ignored unless the table is merged with them by MergeTableFiles.
+type Theme struct {
+	table  *TableFile
+	config *ResTableConfig
+	attrs  map[ResID]ResValue
+}
+
+// NewTheme returns an empty theme for config.
+func (f *TableFile) NewTheme(config *ResTableConfig) *Theme {
+	return &Theme{
+		table:  f,
+		config: config,
+		attrs:  make(map[ResID]ResValue),
+	}
+}
+
+// ApplyStyle applies the style resource referenced by id and its parents to the theme.
+// If force is true, the attributes of the style override the ones already in the theme.
+func (t *Theme) ApplyStyle(id ResID, force bool) error {
+	attrs, err := t.table.getStyle(id, t.config)
+	if err != nil {
+		return err
+	}
+	for attr, v := range attrs {
+		if _, ok := t.attrs[attr]; ok && !force {
+			continue
+		}
+		t.attrs[attr] = v
+	}
+	return nil
+}
+
+// ResolveAttribute returns the value of the attribute attr in the theme.
+// The references to other resources, such as @color/primary, and the attributes, such as ?attr/colorPrimary,
+// are resolved until a simple value is found.
+// A reference to a complex resource, such as a style and an array, is returned as it is.
+func (t *Theme) ResolveAttribute(attr ResID) (ResValue, error) {
+	return t.resolve(ResValue{Size: 8, DataType: TypeAttribute, Data: uint32(attr)})
+}
+
+// ObtainStyledAttributes returns the values of attrs, like Resources.Theme.obtainStyledAttributes.
+// The values are taken from the style resource referenced by style, and from the theme if the style doesn't define them.
+// style may be 0 to use only the theme.
+// The value is TypeNull if the attribute is not defined or it can't be resolved.
+func (t *Theme) ObtainStyledAttributes(style ResID, attrs []ResID) ([]ResValue, error) {
+	var styleAttrs map[ResID]ResValue
+	if style != 0 {
+		var err error
+		styleAttrs, err = t.table.getStyle(style, t.config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([]ResValue, len(attrs))
+	for i, attr := range attrs {
+		v, ok := styleAttrs[attr]
+		if !ok {
+			v, ok = t.attrs[attr]
+		}
+		if !ok {
+			continue
+		}
+		if v, err := t.resolve(v); err == nil {
+			values[i] = v
+		}
+	}
+	return values, nil
+}
+
+// resolve follows the references and the attributes of v.
+func (t *Theme) resolve(v ResValue) (ResValue, error) {
+	for i := 0; i < maxResolveDepth; i++ {
+		switch v.DataType {
+		case TypeAttribute:
+			attr, ok := t.attrs[ResID(v.Data)]
+			if !ok {
+				return ResValue{}, fmt.Errorf("androidbinary: attribute 0x%08X not found in the theme", v.Data)
+			}
+			v = attr
+		case TypeReference:
+			if v.Data == 0 {
+				// @null
+				return ResValue{}, nil
+			}
+			id := ResID(v.Data)
+			p := t.table.findPackage(id.Package())
+			if p == nil {
+				return ResValue{}, fmt.Errorf("androidbinary: package 0x%02X not found", id.Package())
+			}
+			e := p.findEntry(id.Type(), id.Entry(), t.config)
+			if e.Key == nil {
+				return ResValue{}, fmt.Errorf("androidbinary: entry 0x%04X not found", id.Entry())
+			}
+			if e.Value == nil {
+				return v, nil
+			}
+			v = *e.Value
+		default:
+			return v, nil
+		}
+	}
+	return ResValue{}, fmt.Errorf("androidbinary: too deep references to resolve")
+}
+
+// getStyle returns the attributes of the style resource referenced by id, including the ones of its parents.
+func (f *TableFile) getStyle(id ResID, config *ResTableConfig) (map[ResID]ResValue, error) {
+	p := f.findPackage(id.Package())
+	if p == nil {
+		return nil, fmt.Errorf("androidbinary: package 0x%02X not found", id.Package())
+	}
+	e := p.findEntry(id.Type(), id.Entry(), config)
+	if e.Key == nil {
+		return nil, fmt.Errorf("androidbinary: entry 0x%04X not found", id.Entry())
+	}
+	if e.Value != nil {
+		return nil, fmt.Errorf("androidbinary: resource 0x%08X is not a style", uint32(id))
+	}
+
+	// collect the styles from the child to the root.
+	styles := []TableEntry{e}
+	seen := map[ResID]bool{id: true}
+	for parent := e.Parent; parent != 0 && !seen[parent]; parent = e.Parent {
+		seen[parent] = true
+		p := f.findPackage(parent.Package())
+		if p == nil {
+			break
+		}
+		e = p.findEntry(parent.Type(), parent.Entry(), config)
+		if e.Key == nil || e.Value != nil {
+			break
+		}
+		styles = append(styles, e)
+	}
+
+	attrs := make(map[ResID]ResValue)
+	for i := len(styles) - 1; i >= 0; i-- {
+		for _, m := range styles[i].Map {
+			attrs[m.Name] = m.Value
+		}
+	}
+	return attrs, nil
+}
@@ -0,0 +1,168 @@
+package androidbinary
+
+import (
+	"testing"
+)
+
+func TestTheme(t *testing.T) {
+	const (
+		attrColorPrimary ResID = 0x7f010000
+		attrColorAccent  ResID = 0x7f010001
+		attrTextColor    ResID = 0x7f010002
+		attrBackground   ResID = 0x7f010003
+
+		colorBlue ResID = 0x7f020000
+		colorRed  ResID = 0x7f020001
+
+		styleBase     ResID = 0x7f030000
+		styleAppTheme ResID = 0x7f030001
+		styleOverlay  ResID = 0x7f030002
+		styleButton   ResID = 0x7f030003
+		styleLoop     ResID = 0x7f030004
+	)
+	color := func(argb uint32) TableEntry {
+		return TableEntry{
+			Key:   &ResTableEntry{Size: 8},
+			Value: &ResValue{Size: 8, DataType: TypeIntColorARGB8, Data: argb},
+		}
+	}
+	ref := func(typ DataType, id ResID) ResValue {
+		return ResValue{Size: 8, DataType: typ, Data: uint32(id)}
+	}
+	style := func(parent ResID, items ...ResTableMap) TableEntry {
+		return TableEntry{
+			Key:    &ResTableEntry{Size: 16, Flags: EntryFlagComplex},
+			Parent: parent,
+			Map:    items,
+		}
+	}
+	tableFile := &TableFile{
+		tablePackages: map[uint32]*TablePackage{
+			0x7f: {
+				TableTypes: []*TableType{
+					{
+						Header:  &ResTableType{ID: 2},
+						Entries: []TableEntry{color(0xff0000ff), color(0xffff0000)},
+					},
+					{
+						Header: &ResTableType{ID: 3},
+						Entries: []TableEntry{
+							// Base
+							style(0,
+								ResTableMap{Name: attrColorPrimary, Value: ref(TypeReference, colorRed)},
+								ResTableMap{Name: attrTextColor, Value: ref(TypeAttribute, attrColorPrimary)},
+							),
+							// AppTheme, whose parent is Base and a framework style.
+							style(styleBase,
+								ResTableMap{Name: attrColorPrimary, Value: ref(TypeReference, colorBlue)},
+								ResTableMap{Name: attrColorAccent, Value: ref(TypeAttribute, attrTextColor)},
+							),
+							// Overlay
+							style(0x01030000,
+								ResTableMap{Name: attrColorPrimary, Value: ref(TypeReference, colorRed)},
+								ResTableMap{Name: attrBackground, Value: ref(TypeReference, 0)},
+							),
+							// Button
+							style(0,
+								ResTableMap{Name: attrTextColor, Value: ref(TypeReference, colorRed)},
+							),
+							// Loop, whose parent is itself.
+							style(styleLoop,
+								ResTableMap{Name: attrColorPrimary, Value: ref(TypeAttribute, attrColorPrimary)},
+							),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	theme := tableFile.NewTheme(nil)
+	if err := theme.ApplyStyle(styleAppTheme, true); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		attr ResID
+		want uint32
+	}{
+		{attrColorPrimary, 0xff0000ff},
+
+		// ?attr/colorPrimary of the child style is used.
+		{attrTextColor, 0xff0000ff},
+		{attrColorAccent, 0xff0000ff},
+	}
+	for _, tc := range testCases {
+		v, err := theme.ResolveAttribute(tc.attr)
+		if err != nil {
+			t.Errorf("%v: %v", tc.attr, err)
+			continue
+		}
+		if v.DataType != TypeIntColorARGB8 || v.Data != tc.want {
+			t.Errorf("%v: want 0x%08X, got %+v", tc.attr, tc.want, v)
+		}
+	}
+	if _, err := theme.ResolveAttribute(attrBackground); err == nil {
+		t.Error("want error for the undefined attribute, got nil")
+	}
+
+	t.Run("obtain styled attributes", func(t *testing.T) {
+		values, err := theme.ObtainStyledAttributes(styleButton, []ResID{attrTextColor, attrColorPrimary, attrBackground})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if values[0].Data != 0xffff0000 || values[1].Data != 0xff0000ff || values[2].DataType != TypeNull {
+			t.Errorf("unexpected values: %+v", values)
+		}
+	})
+
+	t.Run("apply without force", func(t *testing.T) {
+		theme := tableFile.NewTheme(nil)
+		if err := theme.ApplyStyle(styleAppTheme, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := theme.ApplyStyle(styleOverlay, false); err != nil {
+			t.Fatal(err)
+		}
+		v, err := theme.ResolveAttribute(attrColorPrimary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Data != 0xff0000ff {
+			t.Errorf("want 0xFF0000FF, got %+v", v)
+		}
+
+		// @null
+		v, err = theme.ResolveAttribute(attrBackground)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.DataType != TypeNull {
+			t.Errorf("want null, got %+v", v)
+		}
+
+		if err := theme.ApplyStyle(styleOverlay, true); err != nil {
+			t.Fatal(err)
+		}
+		v, err = theme.ResolveAttribute(attrColorPrimary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Data != 0xffff0000 {
+			t.Errorf("want 0xFFFF0000, got %+v", v)
+		}
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		theme := tableFile.NewTheme(nil)
+		if err := theme.ApplyStyle(styleLoop, true); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := theme.ResolveAttribute(attrColorPrimary); err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+
+	if err := theme.ApplyStyle(colorBlue, true); err == nil {
+		t.Error("want error for the color resource, got nil")
+	}
+}
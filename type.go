@@ -317,6 +317,18 @@ func (v String) String() (string, error) {
 	return ret, nil
 }
 
+// ResID returns the resource ID if the value is a reference, such as @0x7F0B0001.
+func (v String) ResID() (ResID, bool) {
+	if !IsResID(v.value) {
+		return 0, false
+	}
+	id, err := ParseResID(v.value)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
 // MustString is same as String, but it panics if it fails to parse the value.
 func (v String) MustString() string {
 	ret, err := v.String()
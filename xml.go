@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
 // XMLFile is an XML file expressed in binary format.
@@ -15,6 +20,15 @@ type XMLFile struct {
 	notPrecessedNS map[ResStringPoolRef]ResStringPoolRef
 	namespaces     xmlNamespaces
 	xmlBuffer      bytes.Buffer
+
+	// resourceMap is the resource IDs of the attribute names.
+	// resourceMap[i] is the ID of the i-th string in the pool.
+	resourceMap []uint32
+
+	// elements is the stack of the open elements.
+	// The element is empty if its start element is skipped in the lenient mode.
+	elements []string
+	warnings []*ParseError
 }
 
 type InvalidReferenceError struct {
@@ -83,6 +97,12 @@ type ResXMLTreeAttrExt struct {
 	StyleIndex     uint16
 }
 
+// ResXMLTreeCDataExt is extended XML tree node for CDATA.
+type ResXMLTreeCDataExt struct {
+	Data      ResStringPoolRef
+	TypedData ResValue
+}
+
 // ResXMLTreeAttribute is an attribute of start tags.
 type ResXMLTreeAttribute struct {
 	NS         ResStringPoolRef
@@ -99,26 +119,81 @@ type ResXMLTreeEndElementExt struct {
 
 // NewXMLFile returns a new XMLFile.
 func NewXMLFile(r io.ReaderAt) (*XMLFile, error) {
+	return NewXMLFileWithOptions(r, nil)
+}
+
+// NewXMLFileWithOptions is same as NewXMLFile, but it accepts options.
+// The errors in parsing are *ParseError.
+func NewXMLFileWithOptions(r io.ReaderAt, opts *ParseOptions) (*XMLFile, error) {
+	f, err := newXMLFile(r, opts)
+	if err != nil {
+		return nil, fileError(FileKindXML, err)
+	}
+	return f, nil
+}
+
+func newXMLFile(r io.ReaderAt, opts *ParseOptions) (*XMLFile, error) {
 	f := new(XMLFile)
-	sr := io.NewSectionReader(r, 0, 1<<63-1)
+	p := newParser(FileKindXML, opts)
+	sr := io.NewSectionReader(r, 0, readerSize(r))
 
 	fmt.Fprintf(&f.xmlBuffer, xml.Header)
 
 	header := new(ResChunkHeader)
 	if err := binary.Read(sr, binary.LittleEndian, header); err != nil {
-		return nil, err
+		return nil, chunkError(ResXMLChunkType, 0, err)
+	}
+	end := int64(header.Size)
+	if end > sr.Size() && p.lenient() {
+		err := fmt.Errorf("androidbinary: chunk size %d exceeds the file size %d", end, sr.Size())
+		if err := p.recover(ResXMLChunkType, err); err != nil {
+			return nil, err
+		}
+		end = sr.Size()
 	}
 	offset := int64(header.HeaderSize)
-	for offset < int64(header.Size) {
-		chunkHeader, err := f.readChunk(r, offset)
+	for offset < end {
+		if err := p.chunk(); err != nil {
+			return nil, chunkError(ResNullChunkType, offset, err)
+		}
+		p.base = offset
+		n := f.xmlBuffer.Len()
+		chunkHeader, err := f.readChunk(sr, offset, p)
 		if err != nil {
-			return nil, err
+			// skip the broken chunk in the lenient mode, or stop if the next chunk can't be found.
+			f.xmlBuffer.Truncate(n)
+			if err := p.recover(ResNullChunkType, err); err != nil {
+				return nil, err
+			}
+			if chunkHeader == nil {
+				break
+			}
+		}
+		if err := p.alloc(int64(f.xmlBuffer.Len() - n)); err != nil {
+			return nil, chunkError(chunkHeader.Type, offset, err)
 		}
 		offset += int64(chunkHeader.Size)
 	}
+
+	if len(f.elements) > 0 && p.lenient() {
+		p.base = offset
+		if err := p.recover(ResXMLEndElementType, fmt.Errorf("androidbinary: %d unclosed elements", len(f.elements))); err != nil {
+			return nil, err
+		}
+		for len(f.elements) > 0 {
+			f.closeElement()
+		}
+	}
+	f.warnings = p.warnings
 	return f, nil
 }
 
+// Warnings returns the problems that are tolerated in the lenient mode.
+// See ParseOptions.Lenient.
+func (f *XMLFile) Warnings() []*ParseError {
+	return f.warnings
+}
+
 // Reader returns a reader of XML file expressed in text format.
 func (f *XMLFile) Reader() *bytes.Reader {
 	return bytes.NewReader(f.xmlBuffer.Bytes())
@@ -135,40 +210,39 @@ func (f *XMLFile) Decode(v interface{}, table *TableFile, config *ResTableConfig
 	return nil
 }
 
-func (f *XMLFile) readChunk(r io.ReaderAt, offset int64) (*ResChunkHeader, error) {
-	sr := io.NewSectionReader(r, offset, 1<<63-1-offset)
+func (f *XMLFile) readChunk(r *io.SectionReader, offset int64, p *parser) (*ResChunkHeader, error) {
+	sr := newSectionReader(r, offset, r.Size()-offset)
 	chunkHeader := &ResChunkHeader{}
-	if _, err := sr.Seek(0, io.SeekStart); err != nil {
-		return nil, err
-	}
 	if err := binary.Read(sr, binary.LittleEndian, chunkHeader); err != nil {
-		return nil, err
-	}
-	if chunkHeader.HeaderSize < uint16(binary.Size(chunkHeader)) {
-		return nil, fmt.Errorf("androidbinary: invalid chunk header size: %d", chunkHeader.HeaderSize)
+		return nil, chunkError(ResNullChunkType, offset, err)
 	}
-	if chunkHeader.Size < uint32(chunkHeader.HeaderSize) {
-		return nil, fmt.Errorf("androidbinary: invalid chunk size: %d", chunkHeader.Size)
+	if err := checkChunkHeader(chunkHeader); err != nil {
+		return nil, chunkError(chunkHeader.Type, offset, err)
 	}
 
 	var err error
 	if _, err := sr.Seek(0, io.SeekStart); err != nil {
-		return nil, err
+		return nil, chunkError(chunkHeader.Type, offset, err)
 	}
 	switch chunkHeader.Type {
 	case ResStringPoolChunkType:
-		f.stringPool, err = readStringPool(sr)
+		f.stringPool, err = readStringPool(sr, p)
 	case ResXMLStartNamespaceType:
 		err = f.readStartNamespace(sr)
 	case ResXMLEndNamespaceType:
 		err = f.readEndNamespace(sr)
 	case ResXMLStartElementType:
-		err = f.readStartElement(sr)
+		err = f.readStartElement(sr, p)
 	case ResXMLEndElementType:
-		err = f.readEndElement(sr)
+		err = f.readEndElement(sr, p)
+	case ResXMLCDataType:
+		err = f.readCData(sr, p)
+	case ResXMLResourceMapType:
+		f.resourceMap, err = readXMLResourceMap(sr, p)
 	}
 	if err != nil {
-		return nil, err
+		// the header is returned with the error, so that the chunk can be skipped.
+		return chunkHeader, chunkError(chunkHeader.Type, offset, err)
 	}
 
 	return chunkHeader, nil
@@ -243,7 +317,11 @@ func (f *XMLFile) addNamespacePrefix(ns, name ResStringPoolRef) (string, error)
 	return f.GetString(name), nil
 }
 
-func (f *XMLFile) readStartElement(sr *io.SectionReader) error {
+func (f *XMLFile) readStartElement(sr *io.SectionReader, p *parser) error {
+	// the tag is set after the element is written,
+	// so that the end element of the skipped element is also skipped.
+	f.elements = append(f.elements, "")
+
 	header := new(ResXMLTreeNode)
 	if err := binary.Read(sr, binary.LittleEndian, header); err != nil {
 		return err
@@ -254,83 +332,259 @@ func (f *XMLFile) readStartElement(sr *io.SectionReader) error {
 	}
 	ext := new(ResXMLTreeAttrExt)
 	if err := binary.Read(sr, binary.LittleEndian, ext); err != nil {
-		return nil
+		return err
 	}
 
 	tag, err := f.addNamespacePrefix(ext.NS, ext.Name)
 	if err != nil {
-		return err
+		if !f.HasString(ext.Name) {
+			return err
+		}
+		// drop the unknown namespace in the lenient mode.
+		if err := p.recover(ResXMLStartElementType, err); err != nil {
+			return err
+		}
+		tag = f.GetString(ext.Name)
+	}
+	if tag == "" && p.lenient() {
+		return errors.New("androidbinary: empty element name")
 	}
 	f.xmlBuffer.WriteString("<")
 	f.xmlBuffer.WriteString(tag)
 
 	// output XML namespaces
-	if f.notPrecessedNS != nil {
-		for uri, prefix := range f.notPrecessedNS {
-			if !f.HasString(uri) {
-				return &InvalidReferenceError{Ref: uri}
+	for uri, prefix := range f.notPrecessedNS {
+		if !f.HasString(uri) {
+			if err := p.recover(ResXMLStartElementType, &InvalidReferenceError{Ref: uri}); err != nil {
+				return err
 			}
-			if !f.HasString(prefix) {
-				return &InvalidReferenceError{Ref: prefix}
+			continue
+		}
+		if !f.HasString(prefix) {
+			if err := p.recover(ResXMLStartElementType, &InvalidReferenceError{Ref: prefix}); err != nil {
+				return err
 			}
-			fmt.Fprintf(&f.xmlBuffer, " xmlns:%s=\"", f.GetString(prefix))
-			xml.Escape(&f.xmlBuffer, []byte(f.GetString(uri)))
-			fmt.Fprint(&f.xmlBuffer, "\"")
+			continue
 		}
-		f.notPrecessedNS = nil
+		fmt.Fprintf(&f.xmlBuffer, " xmlns:%s=\"", f.GetString(prefix))
+		xml.Escape(&f.xmlBuffer, []byte(f.GetString(uri)))
+		fmt.Fprint(&f.xmlBuffer, "\"")
 	}
 
 	// process attributes
-	offset := int64(ext.AttributeStart + header.Header.HeaderSize)
+	var names map[string]bool
+	if p.lenient() {
+		names = make(map[string]bool, ext.AttributeCount)
+	}
+	start := int64(ext.AttributeStart + header.Header.HeaderSize)
 	for i := 0; i < int(ext.AttributeCount); i++ {
-		if _, err := sr.Seek(offset, io.SeekStart); err != nil {
+		if _, err := sr.Seek(start+int64(i)*int64(ext.AttributeSize), io.SeekStart); err != nil {
 			return err
 		}
 		attr := new(ResXMLTreeAttribute)
-		binary.Read(sr, binary.LittleEndian, attr)
+		if err := binary.Read(sr, binary.LittleEndian, attr); err != nil {
+			// ignore the rest of the attributes in the lenient mode.
+			if err := p.recover(ResXMLStartElementType, err); err != nil {
+				return err
+			}
+			break
+		}
 
 		var value string
-		if attr.RawValue != NilResStringPoolRef {
-			if !f.HasString(attr.RawValue) {
-				return &InvalidReferenceError{Ref: attr.RawValue}
-			}
+		if attr.RawValue != NilResStringPoolRef && f.HasString(attr.RawValue) {
 			value = f.GetString(attr.RawValue)
 		} else {
-			data := attr.TypedValue.Data
-			switch attr.TypedValue.DataType {
-			case TypeNull:
-				value = ""
-			case TypeReference:
-				value = fmt.Sprintf("@0x%08X", data)
-			case TypeIntDec:
-				value = fmt.Sprintf("%d", data)
-			case TypeIntHex:
-				value = fmt.Sprintf("0x%08X", data)
-			case TypeIntBoolean:
-				if data != 0 {
-					value = "true"
-				} else {
-					value = "false"
+			if attr.RawValue != NilResStringPoolRef {
+				// use the typed value instead in the lenient mode.
+				if err := p.recover(ResXMLStartElementType, &InvalidReferenceError{Ref: attr.RawValue}); err != nil {
+					return err
 				}
-			default:
-				value = fmt.Sprintf("@0x%08X", data)
 			}
+			value = formatTypedValue(attr.TypedValue)
 		}
 
 		name, err := f.addNamespacePrefix(attr.NS, attr.Name)
+		if err == nil && names != nil {
+			if local := f.GetString(attr.Name); local == "" {
+				err = errors.New("androidbinary: empty attribute name")
+			} else if !isXMLName(local) {
+				err = fmt.Errorf("androidbinary: invalid attribute name: %q", local)
+			} else if names[name] {
+				err = fmt.Errorf("androidbinary: duplicate attribute: %s", name)
+			}
+		}
+		if err != nil && names != nil {
+			// the names of the attributes are often broken by obfuscators,
+			// while Android finds the framework attributes by the resource IDs.
+			if framework, ok := f.frameworkAttributeName(attr); ok && !names[framework] {
+				if err := p.recover(ResXMLStartElementType, err); err != nil {
+					return err
+				}
+				name, err = framework, nil
+			}
+		}
 		if err != nil {
-			return err
+			// skip the attribute in the lenient mode.
+			if err := p.recover(ResXMLStartElementType, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if names != nil {
+			names[name] = true
 		}
 		fmt.Fprintf(&f.xmlBuffer, " %s=\"", name)
 		xml.Escape(&f.xmlBuffer, []byte(value))
 		fmt.Fprint(&f.xmlBuffer, "\"")
-		offset += int64(ext.AttributeSize)
 	}
 	fmt.Fprint(&f.xmlBuffer, ">")
+	f.notPrecessedNS = nil
+	f.elements[len(f.elements)-1] = tag
 	return nil
 }
 
-func (f *XMLFile) readEndElement(sr *io.SectionReader) error {
+//go:generate go run ./internal/gen/attrs -i $ANDROID_HOME/platforms/android-35/android.jar -o attrs_table.go
+
+// frameworkAttrNamesFallback is the names of the framework attributes that are commonly used in manifests,
+// used if attrs_table.go is generated from an older android.jar that doesn't have them.
+var frameworkAttrNamesFallback = map[ResID]string{
+	0x010103AF: "supportsRtl",
+	0x010104EA: "extractNativeLibs",
+	0x010104EB: "fullBackupContent",
+	0x010104EC: "usesCleartextTraffic",
+	0x010104EE: "autoVerify",
+	0x01010527: "networkSecurityConfig",
+	0x0101052C: "roundIcon",
+	0x0101055B: "isFeatureSplit",
+	0x01010572: "compileSdkVersion",
+	0x01010573: "compileSdkVersionCodename",
+	0x0101057A: "appComponentFactory",
+}
+
+// frameworkAttrName returns the name of the framework attribute referenced by id.
+func frameworkAttrName(id ResID) (string, bool) {
+	if name, ok := frameworkAttrNames[id]; ok {
+		return name, true
+	}
+	name, ok := frameworkAttrNamesFallback[id]
+	return name, ok
+}
+
+// frameworkAttributeName returns the name of the framework attribute of attr, such as "android:name",
+// which is found by the resource ID in the resource map.
+func (f *XMLFile) frameworkAttributeName(attr *ResXMLTreeAttribute) (string, bool) {
+	if int64(attr.Name) >= int64(len(f.resourceMap)) || attr.NS == NilResStringPoolRef {
+		return "", false
+	}
+	name, ok := frameworkAttrName(ResID(f.resourceMap[attr.Name]))
+	if !ok {
+		return "", false
+	}
+	prefix := f.namespaces.get(attr.NS)
+	if prefix == 0 || !f.HasString(prefix) {
+		return "", false
+	}
+	return f.GetString(prefix) + ":" + name, true
+}
+
+// isXMLName returns whether s is a valid local name of XML.
+func isXMLName(s string) bool {
+	for i, r := range s {
+		if r == '_' || unicode.IsLetter(r) {
+			continue
+		}
+		if i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return s != ""
+}
+
+// readXMLResourceMap reads the resource map chunk, which has the resource IDs of the attribute names.
+func readXMLResourceMap(sr *io.SectionReader, p *parser) ([]uint32, error) {
+	header := new(ResChunkHeader)
+	if err := binary.Read(sr, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+	n := chunkBodySize(header, sr.Size()) / 4
+	if n <= 0 {
+		return nil, nil
+	}
+	if err := p.alloc(4 * n); err != nil {
+		return nil, err
+	}
+	ids := make([]uint32, n)
+	if _, err := sr.Seek(int64(header.HeaderSize), io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(sr, binary.LittleEndian, ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// formatTypedValue returns the string representation of the typed value of an attribute,
+// such as "true", "16.0dip" and "#ff3f51b5", as TypedValue.coerceToString does.
+// The references and the unknown types are formatted as resource IDs.
+func formatTypedValue(v ResValue) string {
+	data := v.Data
+	switch v.DataType {
+	case TypeNull:
+		return ""
+	case TypeReference:
+		return fmt.Sprintf("@0x%08X", data)
+	case TypeIntDec:
+		return fmt.Sprintf("%d", int32(data))
+	case TypeIntHex:
+		return fmt.Sprintf("0x%08X", data)
+	case TypeIntBoolean:
+		if data != 0 {
+			return "true"
+		}
+		return "false"
+	case TypeFloat:
+		return formatFloat(math.Float32frombits(data))
+	case TypeDemention:
+		units := [...]string{"px", "dip", "sp", "pt", "in", "mm"}
+		unit := data & complexUnitMask
+		if int(unit) >= len(units) {
+			break
+		}
+		return formatFloat(complexToFloat(data)) + units[unit]
+	case TypeFraction:
+		units := [...]string{"%", "%p"}
+		unit := data & complexUnitMask
+		if int(unit) >= len(units) {
+			break
+		}
+		return formatFloat(complexToFloat(data)*100) + units[unit]
+	case TypeIntColorARGB8, TypeIntColorRGB8, TypeIntColorARGB4, TypeIntColorRGB4:
+		return fmt.Sprintf("#%08x", data)
+	}
+	return fmt.Sprintf("@0x%08X", data)
+}
+
+const complexUnitMask = 0x0F
+
+// complexToFloat converts a complex value of dimensions and fractions to a float.
+func complexToFloat(data uint32) float32 {
+	// the mantissa is 24 bits signed, and the radix is the position of the binary point.
+	mantissa := float32(int32(data&0xFFFFFF00)) / (1 << 8)
+	radix := [...]float32{1, 1.0 / (1 << 7), 1.0 / (1 << 15), 1.0 / (1 << 23)}
+	return mantissa * radix[(data>>4)&0x3]
+}
+
+// formatFloat formats f as Float.toString of Java does for the ordinary values, such as "16.0" and "0.5".
+func formatFloat(f float32) string {
+	s := strconv.FormatFloat(float64(f), 'f', -1, 32)
+	if !strings.ContainsAny(s, ".NI") {
+		s += ".0"
+	}
+	return s
+}
+
+func (f *XMLFile) readEndElement(sr *io.SectionReader, p *parser) error {
 	header := new(ResXMLTreeNode)
 	if err := binary.Read(sr, binary.LittleEndian, header); err != nil {
 		return err
@@ -342,10 +596,63 @@ func (f *XMLFile) readEndElement(sr *io.SectionReader) error {
 	if err := binary.Read(sr, binary.LittleEndian, ext); err != nil {
 		return err
 	}
+	if p.lenient() {
+		// the names of the end elements are often broken by obfuscators,
+		// so close the open element instead, as Android does.
+		if len(f.elements) == 0 {
+			return errors.New("androidbinary: unexpected end element")
+		}
+		f.closeElement()
+		return nil
+	}
+
 	tag, err := f.addNamespacePrefix(ext.NS, ext.Name)
 	if err != nil {
 		return err
 	}
 	fmt.Fprintf(&f.xmlBuffer, "</%s>", tag)
+	if len(f.elements) > 0 {
+		f.elements = f.elements[:len(f.elements)-1]
+	}
 	return nil
 }
+
+func (f *XMLFile) readCData(sr *io.SectionReader, p *parser) error {
+	header := new(ResXMLTreeNode)
+	if err := binary.Read(sr, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if _, err := sr.Seek(int64(header.Header.HeaderSize), io.SeekStart); err != nil {
+		return err
+	}
+	ext := new(ResXMLTreeCDataExt)
+	if err := binary.Read(sr, binary.LittleEndian, ext); err != nil {
+		return err
+	}
+	if len(f.elements) == 0 || f.elements[len(f.elements)-1] == "" {
+		// the text is out of the root element, or in a skipped element.
+		return nil
+	}
+	if !f.HasString(ext.Data) {
+		// drop the text in the lenient mode.
+		return p.recover(ResXMLCDataType, &InvalidReferenceError{Ref: ext.Data})
+	}
+	if err := p.err(); err != nil {
+		return err
+	}
+	text := f.GetString(ext.Data)
+	if err := p.alloc(int64(len(text))); err != nil {
+		return err
+	}
+	xml.EscapeText(&f.xmlBuffer, []byte(text))
+	return nil
+}
+
+// closeElement writes the end tag of the innermost open element.
+func (f *XMLFile) closeElement() {
+	tag := f.elements[len(f.elements)-1]
+	f.elements = f.elements[:len(f.elements)-1]
+	if tag != "" {
+		fmt.Fprintf(&f.xmlBuffer, "</%s>", tag)
+	}
+}
@@ -0,0 +1,228 @@
+package androidbinary
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// protoNamespace is a namespace declared by an XmlElement message.
+type protoNamespace struct {
+	prefix string
+	uri    string
+}
+
+// protoAttribute is an XmlAttribute message.
+type protoAttribute struct {
+	uri   string
+	name  string
+	value string
+	item  *protoReader
+}
+
+// NewXMLFileFromProto returns a new XMLFile from an XmlNode message of aapt2,
+// which is the format of AndroidManifest.xml in Android App Bundles.
+func NewXMLFileFromProto(data []byte) (*XMLFile, error) {
+	f := new(XMLFile)
+	fmt.Fprintf(&f.xmlBuffer, xml.Header)
+	if err := f.readProtoNode(&protoReader{data: data}, nil, false); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// readProtoNode reads an XmlNode message.
+// The text out of the root element is ignored, same as the CDATA chunks of binary XML.
+func (f *XMLFile) readProtoNode(r *protoReader, namespaces []protoNamespace, inElement bool) error {
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		switch field {
+		case 1: // element
+			var m *protoReader
+			if m, err = r.readMessage(wireType); err == nil {
+				err = f.readProtoElement(m, namespaces)
+			}
+		case 2: // text
+			var text string
+			if text, err = r.readString(wireType); err == nil && inElement {
+				xml.EscapeText(&f.xmlBuffer, []byte(text))
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readProtoElement reads an XmlElement message.
+func (f *XMLFile) readProtoElement(r *protoReader, namespaces []protoNamespace) error {
+	var declared []protoNamespace
+	var uri, name string
+	var attrs []protoAttribute
+	var children []*protoReader
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		var m *protoReader
+		switch field {
+		case 1: // namespace_declaration
+			if m, err = r.readMessage(wireType); err == nil {
+				var ns protoNamespace
+				ns, err = readProtoNamespace(m)
+				declared = append(declared, ns)
+			}
+		case 2: // namespace_uri
+			uri, err = r.readString(wireType)
+		case 3: // name
+			name, err = r.readString(wireType)
+		case 4: // attribute
+			if m, err = r.readMessage(wireType); err == nil {
+				var attr protoAttribute
+				attr, err = readProtoAttribute(m)
+				attrs = append(attrs, attr)
+			}
+		case 5: // child
+			if m, err = r.readMessage(wireType); err == nil {
+				children = append(children, m)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// copy the namespaces not to modify the ones of the parent.
+	namespaces = append(namespaces[:len(namespaces):len(namespaces)], declared...)
+	tag, err := protoQualifiedName(namespaces, uri, name)
+	if err != nil {
+		return err
+	}
+	f.xmlBuffer.WriteString("<")
+	f.xmlBuffer.WriteString(tag)
+
+	// output XML namespaces
+	for _, ns := range declared {
+		fmt.Fprintf(&f.xmlBuffer, " xmlns:%s=\"", ns.prefix)
+		xml.Escape(&f.xmlBuffer, []byte(ns.uri))
+		fmt.Fprint(&f.xmlBuffer, "\"")
+	}
+
+	// process attributes
+	for _, attr := range attrs {
+		value := attr.value
+		if attr.item != nil {
+			item, ok, err := readProtoItem(attr.item)
+			if err != nil {
+				return err
+			}
+			if ok {
+				value = formatProtoItem(item)
+			}
+		}
+		name, err := protoQualifiedName(namespaces, attr.uri, attr.name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&f.xmlBuffer, " %s=\"", name)
+		xml.Escape(&f.xmlBuffer, []byte(value))
+		fmt.Fprint(&f.xmlBuffer, "\"")
+	}
+	fmt.Fprint(&f.xmlBuffer, ">")
+
+	for _, child := range children {
+		if err := f.readProtoNode(child, namespaces, true); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(&f.xmlBuffer, "</%s>", tag)
+	return nil
+}
+
+// protoQualifiedName returns the name with the prefix of the namespace uri.
+func protoQualifiedName(namespaces []protoNamespace, uri, name string) (string, error) {
+	if uri == "" {
+		return name, nil
+	}
+	for i := len(namespaces) - 1; i >= 0; i-- {
+		if namespaces[i].uri == uri {
+			return fmt.Sprintf("%s:%s", namespaces[i].prefix, name), nil
+		}
+	}
+	return "", fmt.Errorf("androidbinary: undeclared namespace: %q", uri)
+}
+
+// formatProtoItem formats the compiled value of an attribute, in the same way as binary XML.
+func formatProtoItem(item protoItem) string {
+	if item.value.DataType == TypeString {
+		return item.str
+	}
+	return formatTypedValue(item.value)
+}
+
+// readProtoNamespace reads an XmlNamespace message.
+func readProtoNamespace(r *protoReader) (protoNamespace, error) {
+	var ns protoNamespace
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return protoNamespace{}, err
+		}
+		if !ok {
+			return ns, nil
+		}
+		switch field {
+		case 1: // prefix
+			ns.prefix, err = r.readString(wireType)
+		case 2: // uri
+			ns.uri, err = r.readString(wireType)
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return protoNamespace{}, err
+		}
+	}
+}
+
+// readProtoAttribute reads an XmlAttribute message.
+func readProtoAttribute(r *protoReader) (protoAttribute, error) {
+	var attr protoAttribute
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil {
+			return protoAttribute{}, err
+		}
+		if !ok {
+			return attr, nil
+		}
+		switch field {
+		case 1: // namespace_uri
+			attr.uri, err = r.readString(wireType)
+		case 2: // name
+			attr.name, err = r.readString(wireType)
+		case 3: // value
+			attr.value, err = r.readString(wireType)
+		case 6: // compiled_item
+			attr.item, err = r.readMessage(wireType)
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return protoAttribute{}, err
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package androidbinary
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+const testAndroidNS = "http://schemas.android.com/apk/res/android"
+
+func testProtoAttribute(uri, name, value string, item testProto) testProto {
+	m := testProto(nil).string(1, uri).string(2, name).string(3, value)
+	if item != nil {
+		m = m.message(6, item)
+	}
+	return m
+}
+
+func testProtoElement(uri, name string, attrs []testProto, children ...testProto) testProto {
+	m := testProto(nil).string(2, uri).string(3, name)
+	for _, attr := range attrs {
+		m = m.message(4, attr)
+	}
+	for _, child := range children {
+		m = m.message(5, testProto(nil).message(1, child))
+	}
+	return m
+}
+
+func TestNewXMLFileFromProto(t *testing.T) {
+	activity := testProtoElement("", "activity", []testProto{
+		testProtoAttribute(testAndroidNS, "name", ".MainActivity", nil),
+	})
+	application := testProtoElement("", "application", []testProto{
+		testProtoAttribute(testAndroidNS, "label", "@string/app_name",
+			testProto(nil).message(1, testProto(nil).varint(2, 0x7f010000))),
+		testProtoAttribute(testAndroidNS, "debuggable", "true",
+			testProto(nil).message(7, testProto(nil).varint(8, 1))),
+	}, activity)
+	// the namespace declaration is in the first field of XmlElement.
+	manifest := testProto(nil).message(1, testProto(nil).string(1, "android").string(2, testAndroidNS))
+	manifest = append(manifest, testProtoElement("", "manifest", []testProto{
+		testProtoAttribute("", "package", "com.example", nil),
+		testProtoAttribute(testAndroidNS, "versionCode", "-1",
+			testProto(nil).message(7, testProto(nil).varint(6, 0xffffffffffffffff))),
+		testProtoAttribute(testAndroidNS, "versionName", "1.0 <beta>", nil),
+	}, application)...)
+
+	f, err := NewXMLFileFromProto(testProto(nil).message(1, manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got XMLManifest
+	if err := xml.NewDecoder(f.Reader()).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Package != "com.example" || got.VersionCode != "-1" || got.VersionName != "1.0 <beta>" {
+		t.Errorf("unexpected manifest: %+v", got)
+	}
+	if len(got.Applications) != 1 {
+		t.Fatalf("want 1 application, got %d", len(got.Applications))
+	}
+	app := got.Applications[0]
+	if app.Label != "@0x7F010000" || app.Debuggable != "true" {
+		t.Errorf("unexpected application: %+v", app)
+	}
+	if len(app.Activities) != 1 || app.Activities[0].Name != ".MainActivity" {
+		t.Errorf("unexpected activities: %+v", app.Activities)
+	}
+}
+
+func TestNewXMLFileFromProtoUndeclaredNamespace(t *testing.T) {
+	manifest := testProtoElement("", "manifest", []testProto{
+		testProtoAttribute(testAndroidNS, "versionName", "1.0", nil),
+	})
+	if _, err := NewXMLFileFromProto(testProto(nil).message(1, manifest)); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestNewXMLFileFromProtoTypedValues(t *testing.T) {
+	// the primitive values are formatted in the same way as binary XML.
+	prim := func(field int, v uint64) testProto {
+		return testProto(nil).message(7, testProto(nil).varint(field, v))
+	}
+	view := testProtoElement("", "TextView", []testProto{
+		testProtoAttribute(testAndroidNS, "textSize", "40dp", prim(13, 0x2801)),
+		testProtoAttribute(testAndroidNS, "textColor", "#3f51b5", prim(10, 0xFF3F51B5)),
+		testProtoAttribute(testAndroidNS, "alpha", "0.5",
+			testProto(nil).message(7, testProto(nil).fixed32(3, 0x3F000000))),
+	})
+	manifest := testProto(nil).message(1, testProto(nil).string(1, "android").string(2, testAndroidNS))
+	manifest = append(manifest, view...)
+
+	f, err := NewXMLFileFromProto(testProto(nil).message(1, manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f.Reader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`android:textSize="40.0dip"`, `android:textColor="#ff3f51b5"`, `android:alpha="0.5"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("want %s in %s", want, data)
+		}
+	}
+}
+
+func TestNewXMLFileFromProtoText(t *testing.T) {
+	// the text nodes are the XmlNode messages of the second field.
+	text := func(s string) testProto {
+		return testProto(nil).message(5, testProto(nil).string(2, s))
+	}
+	domain := append(testProtoElement("", "domain", nil), text("example.com & co")...)
+	config := testProtoElement("", "domain-config", nil, domain)
+	root := testProto(nil).message(1, testProtoElement("", "network-security-config", nil, config))
+	root = append(root, testProto(nil).string(2, "out of the root")...)
+
+	f, err := NewXMLFileFromProto(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f.Reader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := xml.Header + "<network-security-config><domain-config><domain>example.com &amp; co</domain></domain-config></network-security-config>"
+	if string(data) != want {
+		t.Errorf("want %s, got %s", want, data)
+	}
+}
@@ -216,7 +216,7 @@ func TestReadStartElement(t *testing.T) {
 	f.namespaces.add(uriRef, prefixRef)
 	f.stringPool = new(ResStringPool)
 	f.stringPool.Strings = []string{"", "name", "prefix", "http://example.com", "attr", "value"}
-	err := f.readStartElement(sr)
+	err := f.readStartElement(sr, nil)
 
 	if err != nil {
 		t.Errorf("got %v want no error", err)
@@ -227,6 +227,51 @@ func TestReadStartElement(t *testing.T) {
 	}
 }
 
+func TestFormatTypedValue(t *testing.T) {
+	testCases := []struct {
+		value ResValue
+		want  string
+	}{
+		{ResValue{DataType: TypeNull}, ""},
+		{ResValue{DataType: TypeReference, Data: 0x7F040000}, "@0x7F040000"},
+		{ResValue{DataType: TypeIntDec, Data: 42}, "42"},
+		{ResValue{DataType: TypeIntDec, Data: 0xFFFFFFFF}, "-1"},
+		{ResValue{DataType: TypeIntHex, Data: 3}, "0x00000003"},
+		{ResValue{DataType: TypeIntBoolean, Data: 0xFFFFFFFF}, "true"},
+		{ResValue{DataType: TypeFloat, Data: 0x3F000000}, "0.5"},
+		{ResValue{DataType: TypeFloat, Data: 0x40000000}, "2.0"},
+		{ResValue{DataType: TypeDemention, Data: 0x00002801}, "40.0dip"},
+		{ResValue{DataType: TypeDemention, Data: 0x00000E02}, "14.0sp"},
+		{ResValue{DataType: TypeDemention, Data: 0x40000031}, "0.5dip"},
+		{ResValue{DataType: TypeFraction, Data: 0x40000030}, "50.0%"},
+		{ResValue{DataType: TypeIntColorARGB8, Data: 0xFF3F51B5}, "#ff3f51b5"},
+	}
+	for _, tc := range testCases {
+		if got := formatTypedValue(tc.value); got != tc.want {
+			t.Errorf("%+v: want %q, got %q", tc.value, tc.want, got)
+		}
+	}
+}
+
+func TestFrameworkAttrName(t *testing.T) {
+	testCases := []struct {
+		id   ResID
+		want string
+		ok   bool
+	}{
+		{0x01010003, "name", true},
+		{0x010104EC, "usesCleartextTraffic", true},
+		{0x01010527, "networkSecurityConfig", true},
+		{0x7F010000, "", false},
+	}
+	for _, tc := range testCases {
+		got, ok := frameworkAttrName(tc.id)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("%v: want %q, %v, got %q, %v", tc.id, tc.want, tc.ok, got, ok)
+		}
+	}
+}
+
 func TestReadEndElement(t *testing.T) {
 	input := []uint8{
 		0x03, 0x01, // Type = RES_XML_END_ELEMENT_TYPE
@@ -243,7 +288,7 @@ func TestReadEndElement(t *testing.T) {
 	f := new(XMLFile)
 	f.stringPool = new(ResStringPool)
 	f.stringPool.Strings = []string{"", "name"}
-	err := f.readEndElement(sr)
+	err := f.readEndElement(sr, nil)
 
 	if err != nil {
 		t.Errorf("got %v want no error", err)